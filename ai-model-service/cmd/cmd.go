@@ -13,4 +13,9 @@ var RootCmd = &cobra.Command{
 
 func init() {
 	RootCmd.AddCommand(serveCmd)
+	RootCmd.AddCommand(rekeyCmd)
+	RootCmd.AddCommand(secretsCmd)
+	RootCmd.AddCommand(tokenCmd)
+	RootCmd.AddCommand(migrateCmd)
+	RootCmd.AddCommand(catalogCmd)
 }
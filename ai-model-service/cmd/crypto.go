@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/blcvn/backend/services/ai-model-service/helper"
+)
+
+// loadCryptoHelpers builds the envelope encryption helper from AI_SERVICE_MASTER_KEYS
+// ("1:<secret>,2:<secret>", highest version is current) or, for single-key setups,
+// AI_SERVICE_SECRET as master key version 1. The well-known dev secret is only accepted as
+// the current key when AI_SERVICE_DEV_MODE=true; as a retired version it stays readable so
+// rekey can move values encrypted before a master key was configured off it.
+func loadCryptoHelpers() helper.CryptoHelpers {
+	devMode, _ := strconv.ParseBool(getEnv("AI_SERVICE_DEV_MODE", "false"))
+
+	var masterKeys []helper.MasterKey
+	if spec := getEnv("AI_SERVICE_MASTER_KEYS", ""); spec != "" {
+		keys, err := helper.ParseMasterKeys(spec)
+		if err != nil {
+			log.Fatalf("Invalid AI_SERVICE_MASTER_KEYS: %v", err)
+		}
+		masterKeys = keys
+	} else if secret := getEnv("AI_SERVICE_SECRET", ""); secret != "" {
+		masterKeys = []helper.MasterKey{{Version: 1, Secret: secret}}
+	} else if devMode {
+		log.Println("Warning: no master key configured, using the dev secret (AI_SERVICE_DEV_MODE=true)")
+		masterKeys = []helper.MasterKey{{Version: 1, Secret: helper.DevSecret}}
+	} else {
+		log.Fatal("AI_SERVICE_MASTER_KEYS or AI_SERVICE_SECRET must be set (set AI_SERVICE_DEV_MODE=true to use the dev secret)")
+	}
+
+	if devSecretUsed, devSecretCurrent := helper.DevSecretUse(masterKeys); devSecretCurrent && !devMode {
+		log.Fatal("Refusing to start with the dev secret as the current master key outside dev mode")
+	} else if devSecretUsed && !devMode {
+		log.Println("Warning: the dev secret is a retired master key version; run rekey and then remove it")
+	}
+
+	crypto, err := helper.NewCryptoHelpers(masterKeys)
+	if err != nil {
+		log.Fatalf("Failed to initialize encryption: %v", err)
+	}
+	log.Printf("Encryption initialized with master key version %d", crypto.CurrentKeyVersion())
+	return crypto
+}
@@ -9,11 +9,12 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/blcvn/backend/services/ai-model-service/controllers"
-	"github.com/blcvn/backend/services/ai-model-service/dto"
 	"github.com/blcvn/backend/services/ai-model-service/helper"
 	"github.com/blcvn/backend/services/ai-model-service/repository/postgres"
 	"github.com/blcvn/backend/services/ai-model-service/usecases"
@@ -44,6 +45,7 @@ var (
 	jaegerURL   string
 	tlsCertPath string
 	tlsKeyPath  string
+	egressPort  string
 )
 
 var serveCmd = &cobra.Command{
@@ -60,6 +62,7 @@ func init() {
 	serveCmd.Flags().StringVar(&jaegerURL, "jaeger-url", "", "Jaeger collector URL")
 	serveCmd.Flags().StringVar(&tlsCertPath, "tls-cert", "", "Path to TLS certificate")
 	serveCmd.Flags().StringVar(&tlsKeyPath, "tls-key", "", "Path to TLS key")
+	serveCmd.Flags().StringVar(&egressPort, "egress-port", "8086", "Credential lease egress port")
 }
 
 func runServe(cmd *cobra.Command, args []string) {
@@ -69,52 +72,70 @@ func runServe(cmd *cobra.Command, args []string) {
 	vaultToken := getEnv("VAULT_TOKEN", "")
 
 	// Initialize database
-	db, err := gorm.Open(gorm_postgres.New(gorm_postgres.Config{
-		DSN:                  dbURL,
-		PreferSimpleProtocol: true,
-	}), &gorm.Config{})
+	db := openDatabase(dbURL)
+
+	// Migrations normally run as a separate `migrate up` step before a rollout;
+	// AUTO_MIGRATE applies them at startup instead
+	autoMigrate, err := strconv.ParseBool(getEnv("AUTO_MIGRATE", "false"))
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		log.Fatalf("Invalid AUTO_MIGRATE: %v", err)
 	}
-
-	// Auto-migrate models
-	log.Println("Running database migrations...")
-	if err := db.AutoMigrate(&dto.AIModel{}, &dto.UsageLog{}); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	if autoMigrate {
+		log.Println("Running database migrations...")
+		migrateUp(db, 0)
 	}
+	checkSchema(db)
 
 	// Initialize Vault client
-	vaultClient, err := helper.NewVaultClient(vaultAddr, vaultToken)
-	if err != nil {
-		log.Fatalf("Failed to initialize Vault client: %v", err)
+	vaultClient, vaultErr := helper.NewVaultClient(vaultAddr, vaultToken)
+	if vaultErr != nil {
+		log.Fatalf("Failed to initialize Vault client: %v", vaultErr)
 	}
 	defer vaultClient.Close()
 
-	// Load AI Service Secret from Vault (or Env fallback)
-	// We want to store the master secret key in Vault, similar to JWTSecret in auth-service.
-	// For now, let's assume it's in env AI_SERVICE_SECRET or we can read it from Vault path "secret/data/ai-service-config".
-	// The user requested: "load giống JWTSecret của auth-service".
-	// In auth-service, it loads config file from Vault Agent path.
-	// Here, we can try to read from Env first (pushed by Vault Agent to Env or File).
-	// Let's rely on Env AI_SERVICE_SECRET for simplicity as we didn't setup the full config loading struct.
-	aiServiceSecret := getEnv("AI_SERVICE_SECRET", "")
-	if aiServiceSecret == "" {
-		// Log warning or fatal? Fatal if we want to enforce it.
-		// For dev, maybe default to something?
-		log.Println("Warning: AI_SERVICE_SECRET is not set, using default dev secret")
-		aiServiceSecret = "dev-secret-key-must-be-32-bytes-long!" // 32 bytes for AES-256
-	}
+	// Load master keys for provider key encryption and the secret backends
+	cryptoHelper := loadCryptoHelpers()
+	secretStores := loadSecretStores(cryptoHelper, vaultClient)
 
 	// Initialize layers
 	modelRepo := postgres.NewModelRepository(db)
-	cryptoHelper := helper.NewCryptoHelpers(aiServiceSecret)
-
-	// Seed models
-	helper.SeedModels(db, cryptoHelper)
+	apiKeyRepo := postgres.NewAPIKeyRepository(db)
+	tenantRepo := postgres.NewTenantRepository(db)
+	budgetRepo := postgres.NewBudgetRepository(db)
+	providerKeyRepo := postgres.NewProviderKeyRepository(db)
+	aliasRepo := postgres.NewModelAliasRepository(db)
+	auditUsecase := usecases.NewAuditUsecase(postgres.NewAuditRepository(db))
+
+	// Model change events, broadcast across replicas for proxies watching their caches
+	modelEvents := postgres.NewModelEventBus(db, dbURL)
+	eventsCtx, stopEvents := context.WithCancel(context.Background())
+	defer stopEvents()
+	go modelEvents.Run(eventsCtx)
+
+	// Keep global models in line with the model catalog file
+	if catalogFile := getEnv("MODEL_CATALOG_FILE", ""); catalogFile != "" {
+		mode := getEnv("MODEL_CATALOG_SYNC", catalogSyncPlan)
+		switch mode {
+		case catalogSyncOff:
+		case catalogSyncPlan, catalogSyncApply:
+			if err := syncCatalog(db, dbURL, secretStores, catalogFile, mode); err != nil {
+				log.Printf("Failed to sync model catalog: %v", err)
+			}
+		default:
+			log.Fatalf("Invalid MODEL_CATALOG_SYNC: %s", mode)
+		}
+	}
 
-	modelUsecase := usecases.NewModelUsecase(modelRepo, vaultClient, cryptoHelper)
-	transform := helper.NewTransform()
-	modelController := controllers.NewModelController(modelUsecase, transform)
+	budgetNotifier := helper.NewWebhookNotifier(getEnv("BUDGET_ALERT_WEBHOOK_URL", ""), 10*time.Second)
+	budgetUsecase := usecases.NewBudgetUsecase(budgetRepo, modelRepo, tenantRepo, budgetNotifier)
+	providerKeyUsecase := usecases.NewProviderKeyUsecase(providerKeyRepo, modelRepo, secretStores, modelEvents)
+	allowRawCredentials, err := strconv.ParseBool(getEnv("ALLOW_RAW_CREDENTIALS", "true"))
+	if err != nil {
+		log.Fatalf("Invalid ALLOW_RAW_CREDENTIALS: %v", err)
+	}
+	modelUsecase := usecases.NewModelUsecase(modelRepo, tenantRepo, secretStores, budgetUsecase, providerKeyUsecase, modelEvents, auditUsecase, aliasRepo, allowRawCredentials)
+	apiKeyUsecase := usecases.NewAPIKeyUsecase(apiKeyRepo, tenantRepo)
+	tenantUsecase := usecases.NewTenantUsecase(tenantRepo)
 
 	// Setup mTLS
 	var reloader *mtls.CertReloader
@@ -128,6 +149,62 @@ func runServe(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// Credential leases let proxies call providers through the egress without seeing keys.
+	// Leases are bound to the proxy's client certificate, so they need mTLS.
+	var leaseSigner *helper.LeaseSigner
+	if leaseSecret := getEnv("CREDENTIAL_LEASE_SECRET", ""); leaseSecret != "" {
+		if reloader != nil {
+			leaseSigner = helper.NewLeaseSigner(leaseSecret)
+		} else {
+			log.Println("Warning: CREDENTIAL_LEASE_SECRET is set but mTLS is not enabled, credential leases and the egress are disabled")
+		}
+	}
+	leaseTTL, err := time.ParseDuration(getEnv("CREDENTIAL_LEASE_TTL", "5m"))
+	if err != nil {
+		log.Fatalf("Invalid CREDENTIAL_LEASE_TTL: %v", err)
+	}
+	egressURL := getEnv("EGRESS_PUBLIC_URL", fmt.Sprintf("https://localhost:%s/egress", egressPort))
+	leaseUsecase := usecases.NewCredentialLeaseUsecase(modelUsecase, modelRepo, providerKeyRepo, secretStores, auditUsecase, leaseSigner, leaseTTL, egressURL)
+
+	transform := helper.NewTransform()
+	modelController := controllers.NewModelController(modelUsecase, apiKeyUsecase, tenantUsecase, budgetUsecase, providerKeyUsecase, leaseUsecase, auditUsecase, transform)
+
+	// Usage log partition maintenance and retention
+	retentionMonths, err := strconv.Atoi(getEnv("USAGE_RETENTION_MONTHS", "12"))
+	if err != nil {
+		log.Fatalf("Invalid USAGE_RETENTION_MONTHS: %v", err)
+	}
+	maintenanceInterval, err := time.ParseDuration(getEnv("USAGE_MAINTENANCE_INTERVAL", "1h"))
+	if err != nil {
+		log.Fatalf("Invalid USAGE_MAINTENANCE_INTERVAL: %v", err)
+	}
+	usageRetentionUsecase := usecases.NewUsageRetentionUsecase(postgres.NewUsageRetentionRepository(db), retentionMonths)
+	maintenanceCtx, stopMaintenance := context.WithCancel(context.Background())
+	defer stopMaintenance()
+	go usageRetentionUsecase.Start(maintenanceCtx, maintenanceInterval)
+
+	// Purge of deleted models once they can no longer be restored
+	purgeRetentionDays, err := strconv.Atoi(getEnv("MODEL_PURGE_RETENTION_DAYS", "30"))
+	if err != nil {
+		log.Fatalf("Invalid MODEL_PURGE_RETENTION_DAYS: %v", err)
+	}
+	modelPurgeUsecase := usecases.NewModelPurgeUsecase(postgres.NewModelPurgeRepository(db), auditUsecase, time.Duration(purgeRetentionDays)*24*time.Hour)
+	go modelPurgeUsecase.Start(maintenanceCtx, maintenanceInterval)
+
+	// Credential reads are queued for audit and appended to the hash chain in batches
+	auditChainInterval, err := time.ParseDuration(getEnv("AUDIT_CHAIN_INTERVAL", "1s"))
+	if err != nil {
+		log.Fatalf("Invalid AUDIT_CHAIN_INTERVAL: %v", err)
+	}
+	go auditUsecase.Start(maintenanceCtx, auditChainInterval)
+
+	// Budgets touched by logged usage are evaluated together by a single worker
+	budgetEvaluationInterval, err := time.ParseDuration(getEnv("BUDGET_EVALUATION_INTERVAL", "10s"))
+	if err != nil {
+		log.Fatalf("Invalid BUDGET_EVALUATION_INTERVAL: %v", err)
+	}
+	go budgetUsecase.Start(maintenanceCtx, budgetEvaluationInterval)
+
 	// Logging options
 	logger := logging.LoggerFunc(func(ctx context.Context, lvl logging.Level, msg string, fields ...any) {
 		log.Printf("[gRPC] %s: %v", msg, fields)
@@ -151,6 +228,13 @@ func runServe(cmd *cobra.Command, args []string) {
 		),
 	)
 
+	// Internal RPCs are restricted to services and model management to admins
+	authorizer := loadAuthorizer(reloader != nil)
+	serverOpts = append(serverOpts,
+		grpc.ChainUnaryInterceptor(authorizer.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(authorizer.StreamServerInterceptor()),
+	)
+
 	if reloader != nil {
 		creds := credentials.NewTLS(&tls.Config{
 			GetConfigForClient: reloader.GetConfigForClient,
@@ -202,7 +286,7 @@ func runServe(cmd *cobra.Command, args []string) {
 	)
 
 	// Gateway Dial Options
-	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	gatewayCreds := insecure.NewCredentials()
 	// Note: If gRPC server uses TLS, gateway needs to dial with TLS too or insecure skip verify if creating separate internal loop
 	// Since gateway is running in same process, we can target localhost.
 	// If mTLS is ON, we must use TLS.
@@ -211,7 +295,14 @@ func runServe(cmd *cobra.Command, args []string) {
 		// Use the CertReloader to get client config (reuses loaded CA)
 		clientTLS := reloader.ClientTLSConfig()
 		clientTLS.InsecureSkipVerify = true // For localhost internal call if loopback IP doesn't match cert SANs (often an issue)
-		dialOpts = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(clientTLS))}
+		gatewayCreds = credentials.NewTLS(clientTLS)
+	}
+	// Internal RPCs are kept off the HTTP gateway, which marks the calls it relays so the
+	// gateway's own certificate never authorizes them
+	dialOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(gatewayCreds),
+		grpc.WithChainUnaryInterceptor(controllers.GatewayUnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(controllers.GatewayStreamClientInterceptor()),
 	}
 
 	err = pb.RegisterAIModelServiceHandlerFromEndpoint(ctx, mux, fmt.Sprintf("localhost:%s", grpcPort), dialOpts)
@@ -228,17 +319,22 @@ func runServe(cmd *cobra.Command, args []string) {
 		w.Write([]byte("OK"))
 	})
 
-	// Add CORS
-	corsHandler := cors.New(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"*"},
-		AllowCredentials: true,
-	})
+	// Add CORS for the origins listed in CORS_ALLOWED_ORIGINS; without any, cross-origin
+	// browser requests are not allowed
+	var httpHandler http.Handler = httpMux
+	if allowedOrigins := splitList(getEnv("CORS_ALLOWED_ORIGINS", "")); len(allowedOrigins) > 0 {
+		corsHandler := cors.New(cors.Options{
+			AllowedOrigins:   allowedOrigins,
+			AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+			AllowedHeaders:   []string{"Authorization", "Content-Type"},
+			AllowCredentials: true,
+		})
+		httpHandler = corsHandler.Handler(httpMux)
+	}
 
 	httpServer := &http.Server{
 		Addr:    fmt.Sprintf(":%s", httpPort),
-		Handler: corsHandler.Handler(httpMux),
+		Handler: httpHandler,
 	}
 
 	if reloader != nil {
@@ -269,6 +365,27 @@ func runServe(cmd *cobra.Command, args []string) {
 		}
 	}()
 
+	// Start the credential lease egress on its own port, so it is never exposed with the gateway's CORS policy
+	var egressServer *http.Server
+	if leaseSigner != nil {
+		egressMux := http.NewServeMux()
+		egressMux.Handle("/egress/{model}/{path...}", controllers.NewEgressController(leaseUsecase))
+		egressServer = &http.Server{
+			Addr:    fmt.Sprintf(":%s", egressPort),
+			Handler: egressMux,
+			TLSConfig: &tls.Config{
+				GetConfigForClient: reloader.GetConfigForClient,
+			},
+		}
+
+		go func() {
+			log.Printf("Starting credential lease egress on port %s", egressPort)
+			if err := egressServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Failed to serve egress: %v", err)
+			}
+		}()
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -284,11 +401,39 @@ func runServe(cmd *cobra.Command, args []string) {
 	if err := httpServer.Shutdown(ctx); err != nil {
 		log.Printf("HTTP server shutdown error: %v", err)
 	}
+	if egressServer != nil {
+		if err := egressServer.Shutdown(ctx); err != nil {
+			log.Printf("Egress server shutdown error: %v", err)
+		}
+	}
 
 	grpcServer.GracefulStop()
 	log.Println("Servers stopped")
 }
 
+// openDatabase connects to Postgres or exits
+func openDatabase(dbURL string) *gorm.DB {
+	db, err := gorm.Open(gorm_postgres.New(gorm_postgres.Config{
+		DSN:                  dbURL,
+		PreferSimpleProtocol: true,
+	}), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	return db
+}
+
+// splitList splits a comma-separated value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/controllers"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/blcvn/backend/services/ai-model-service/helper"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tokenSubject string
+	tokenRoles   []string
+	tokenTenant  string
+	tokenTTL     time.Duration
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Manage service tokens",
+}
+
+var tokenIssueCmd = &cobra.Command{
+	Use:   "issue",
+	Short: "Issue a service token signed with SERVICE_TOKEN_SECRET",
+	Long: `Issue a service token for a caller that cannot present an mTLS client certificate.
+Proxies need the internal role; tools managing the model catalog need the admin role.
+A token issued with --tenant is only accepted on requests scoped to that tenant.`,
+	Run: runTokenIssue,
+}
+
+func init() {
+	tokenIssueCmd.Flags().StringVar(&tokenSubject, "subject", "", "Identity of the caller, e.g. the proxy ID")
+	tokenIssueCmd.Flags().StringSliceVar(&tokenRoles, "role", nil, "Role to grant (internal, admin); repeatable")
+	tokenIssueCmd.Flags().StringVar(&tokenTenant, "tenant", "", "Restrict the token to one tenant")
+	tokenIssueCmd.Flags().DurationVar(&tokenTTL, "ttl", 90*24*time.Hour, "How long the token is valid")
+	tokenIssueCmd.MarkFlagRequired("subject")
+	tokenIssueCmd.MarkFlagRequired("role")
+	tokenCmd.AddCommand(tokenIssueCmd)
+}
+
+func runTokenIssue(cmd *cobra.Command, args []string) {
+	secret := getEnv("SERVICE_TOKEN_SECRET", "")
+	if secret == "" {
+		log.Fatal("SERVICE_TOKEN_SECRET must be set")
+	}
+
+	claims := &entities.ServiceToken{
+		Subject:   tokenSubject,
+		TenantID:  tokenTenant,
+		ExpiresAt: time.Now().Add(tokenTTL).UTC(),
+	}
+	for _, role := range tokenRoles {
+		switch entities.ServiceRole(role) {
+		case entities.ServiceRoleInternal, entities.ServiceRoleAdmin:
+			claims.Roles = append(claims.Roles, entities.ServiceRole(role))
+		default:
+			log.Fatalf("Unknown role %q, expected internal or admin", role)
+		}
+	}
+
+	token, err := helper.NewServiceTokenSigner(secret).Sign(claims)
+	if err != nil {
+		log.Fatalf("Failed to sign service token: %v", err)
+	}
+	fmt.Println(token)
+}
+
+// loadAuthorizer configures method-level authorization. Callers authenticate with a service
+// token (SERVICE_TOKEN_SECRET) or, when mTLS is on, a client certificate whose identity is
+// listed in INTERNAL_IDENTITIES, ADMIN_IDENTITIES or PROXY_IDENTITIES. Without either, every
+// caller is let through only when AI_SERVICE_DEV_MODE=true.
+func loadAuthorizer(mtlsEnabled bool) *controllers.Authorizer {
+	opts := controllers.AuthorizerOptions{
+		InternalIdentities: splitList(getEnv("INTERNAL_IDENTITIES", "")),
+		AdminIdentities:    splitList(getEnv("ADMIN_IDENTITIES", "")),
+		ProxyIdentities:    splitList(getEnv("PROXY_IDENTITIES", "")),
+	}
+	if secret := getEnv("SERVICE_TOKEN_SECRET", ""); secret != "" {
+		opts.Tokens = helper.NewServiceTokenSigner(secret)
+	}
+
+	hasIdentities := len(opts.InternalIdentities)+len(opts.AdminIdentities)+len(opts.ProxyIdentities) > 0
+	if hasIdentities && !mtlsEnabled {
+		log.Fatal("INTERNAL_IDENTITIES, ADMIN_IDENTITIES and PROXY_IDENTITIES require mTLS, set --tls-cert and --tls-key")
+	}
+
+	if opts.Tokens == nil && !hasIdentities {
+		devMode, _ := strconv.ParseBool(getEnv("AI_SERVICE_DEV_MODE", "false"))
+		if !devMode {
+			log.Fatal("SERVICE_TOKEN_SECRET or mTLS identities must be configured to authorize internal and admin RPCs (set AI_SERVICE_DEV_MODE=true to allow every caller)")
+		}
+		log.Println("Warning: no service authentication configured, every caller may use internal and admin RPCs (AI_SERVICE_DEV_MODE=true)")
+		opts.Disabled = true
+	}
+	return controllers.NewAuthorizer(opts)
+}
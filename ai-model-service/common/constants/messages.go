@@ -10,12 +10,27 @@ const (
 	ErrFailedToCreateModel = "failed to create model: %v"
 	ErrFailedToUpdateModel = "failed to update model: %v"
 	ErrFailedToDeleteModel = "failed to delete model: %v"
+	ErrInvalidModelLimits  = "model limits must not be negative and max output tokens must fit in the context window"
+	ErrInvalidFallback     = "invalid fallback model %s: %s"
+	ErrInvalidModelTier    = "unknown model tier %q, expected cheap, balanced or best"
+	ErrInvalidSunset       = "sunset must be after the deprecation is announced"
+	ErrInvalidReplacement  = "invalid replacement model %s: %s"
+	ErrModelSunset         = "model reached its sunset on %s"
+	ErrModelNotDeleted     = "no deleted model with this ID"
+	ErrModelIsReplacement  = "model replaces deprecated models: %s"
+	ErrFailedToPurgeModel  = "failed to purge model: %v"
+	ErrModelVersionStale   = "model was changed since version %d, reload it and retry"
+	ErrModelVersionMissing = "the model version the update is based on is required with an update mask or a new api key"
+	ErrUnknownModelField   = "unknown model field %q in update mask"
 
 	// Vault errors
 	ErrVaultConnectionFailed = "failed to connect to vault: %v"
 	ErrVaultReadFailed       = "failed to read from vault: %v"
 	ErrVaultPathNotFound     = "vault path not found: %s"
 	ErrInvalidCredentials    = "invalid credentials from vault"
+	ErrVaultWriteFailed      = "failed to write to vault: %v"
+	ErrVaultDeleteFailed     = "failed to delete from vault: %v"
+	ErrVaultTransitFailed    = "vault transit %s failed: %v"
 
 	// Quota errors
 	ErrQuotaExceeded        = "quota exceeded for model: %s"
@@ -24,23 +39,196 @@ const (
 	ErrFailedToCheckQuota   = "failed to check quota: %v"
 
 	// Usage errors
-	ErrFailedToLogUsage = "failed to log usage: %v"
-	ErrFailedToGetUsage = "failed to get usage: %v"
+	ErrFailedToLogUsage      = "failed to log usage: %v"
+	ErrDuplicateUsageEvent   = "usage event already logged"
+	ErrUsageBatchTooLarge    = "usage batch exceeds %d events"
+	ErrFailedToGetUsage      = "failed to get usage: %v"
+	ErrInvalidUsageGroupBy   = "invalid usage group by dimension: %s"
+	ErrInvalidUsageBucket    = "invalid usage time bucket: %s"
+	ErrInvalidTimeRange      = "start time must be before end time"
+	ErrInvalidUserID         = "invalid user ID format"
+	ErrInvalidSessionID      = "invalid session ID format"
+	ErrFailedToMaintainUsage = "failed to maintain usage logs: %v"
+	ErrUsageTimeOutOfRange   = "usage occurred at %s, outside the accepted window of %d days back to %s ahead"
+
+	// API key errors
+	ErrAPIKeyNotFound       = "api key not found"
+	ErrInvalidAPIKeyID      = "invalid api key ID format"
+	ErrInvalidAPIKey        = "invalid api key"
+	ErrAPIKeyRevoked        = "api key has been revoked"
+	ErrAPIKeyExpired        = "api key has expired"
+	ErrAPIKeyBudgetExceeded = "api key budget exhausted"
+	ErrInvalidAPIKeyName    = "api key name is required"
+	ErrAPIKeyTenantRequired = "api key must be bound to a tenant"
+	ErrFailedToCreateAPIKey = "failed to create api key: %v"
+	ErrFailedToRevokeAPIKey = "failed to revoke api key: %v"
+
+	// Tenant errors
+	ErrTenantNotFound        = "tenant not found"
+	ErrTenantAlreadyExists   = "tenant with this name already exists"
+	ErrInvalidTenantID       = "invalid tenant ID format"
+	ErrInvalidTenantName     = "tenant name is required"
+	ErrTenantSuspended       = "tenant is suspended"
+	ErrTenantAccessDenied    = "resource belongs to another tenant"
+	ErrProjectNotFound       = "project not found"
+	ErrProjectAlreadyExists  = "project with this name already exists in tenant"
+	ErrInvalidProjectID      = "invalid project ID format"
+	ErrInvalidProjectName    = "project name is required"
+	ErrFailedToCreateTenant  = "failed to create tenant: %v"
+	ErrFailedToCreateProject = "failed to create project: %v"
+
+	// Pricing errors
+	ErrPricingNotFound       = "no pricing in effect for model"
+	ErrInvalidPricing        = "prices must not be negative"
+	ErrFailedToCreatePricing = "failed to create pricing: %v"
+
+	// Model alias errors
+	ErrModelAliasNotFound      = "model alias not found"
+	ErrInvalidModelAliasName   = "alias name is required and must not be a model ID"
+	ErrModelAliasNameTaken     = "name is already used by a model or another tenant's alias"
+	ErrModelNameTakenByAlias   = "name is already used by a model alias"
+	ErrModelAliasTargetInvalid = "a global alias can only point to a global model"
+	ErrModelAliasChanged       = "model alias no longer points to model %s"
+	ErrInvalidModelAliasRoute  = "invalid route to model %s: %s"
+	ErrModelAliasRouteWeights  = "route weights must add up to at most 100"
+	ErrModelHasAliases         = "model is the target of aliases: %s"
+	ErrFailedToSetModelAlias   = "failed to set model alias: %v"
+
+	// Budget errors
+	ErrBudgetNotFound         = "budget not found"
+	ErrInvalidBudgetID        = "invalid budget ID format"
+	ErrInvalidBudgetScope     = "budget scope must be one of tenant, project, user or model"
+	ErrInvalidBudgetScopeID   = "budget scope ID must be a valid UUID"
+	ErrInvalidBudgetPeriod    = "budget period must be daily or monthly"
+	ErrInvalidBudgetLimit     = "budget limit must be greater than zero"
+	ErrInvalidBudgetThreshold = "budget thresholds must be between 1 and 100 percent"
+	ErrBudgetExceeded         = "budget exceeded for %s %s"
+	ErrFailedToCreateBudget   = "failed to create budget: %v"
+	ErrFailedToSendAlert      = "failed to send budget alert: %v"
+
+	// Provider key errors
+	ErrProviderKeyNotFound       = "provider key not found"
+	ErrInvalidProviderKeyID      = "invalid provider key ID format"
+	ErrProviderKeyRequired       = "provider api key is required"
+	ErrInvalidProviderKeyWeight  = "provider key weight must be between 1 and 1000"
+	ErrInvalidProviderKeyStatus  = "provider key status must be active or disabled"
+	ErrProviderKeyRevoked        = "provider key has been revoked"
+	ErrNoActiveProviderKeys      = "no active provider keys for model"
+	ErrFailedToCreateProviderKey = "failed to create provider key: %v"
+	ErrFailedToUpdateProviderKey = "failed to update provider key: %v"
+
+	// Secret store errors
+	ErrFailedToRekey            = "failed to rekey secret: %v"
+	ErrFailedToEncryptAPIKey    = "failed to encrypt api key: %v"
+	ErrFailedToDecryptAPIKey    = "failed to decrypt api key: %v"
+	ErrUnknownSecretBackend     = "unknown secret backend: %s"
+	ErrSecretBackendUnavailable = "secret backend is not configured: %s"
+	ErrSecretBackendUnchanged   = "model secrets are already stored in %s"
+	ErrSecretsChanged           = "model secrets changed during migration, retry"
+
+	// Credential lease errors
+	ErrRawCredentialsDisabled    = "raw credentials are disabled, use a credential lease"
+	ErrCredentialLeasesDisabled  = "credential leases are not configured"
+	ErrProxyIdentityRequired     = "a verified mTLS client certificate is required for a credential lease"
+	ErrProxyIdentityNotAllowed   = "caller is not an allowed proxy identity"
+	ErrInvalidCredentialLease    = "invalid credential lease: %v"
+	ErrCredentialLeaseNotAllowed = "credential lease is not valid for this model or proxy"
+	ErrFailedToSignLease         = "failed to sign credential lease: %v"
+
+	// Authorization errors
+	ErrInvalidServiceToken        = "invalid service token: %v"
+	ErrServiceTokenTenantMismatch = "service token is only valid for tenant %s"
+	ErrAdminRoleRequired          = "admin role is required"
+	ErrInternalRoleRequired       = "method is only available to internal services"
+	ErrMethodNotExposed           = "method is not available over HTTP"
+	ErrTenantRequired             = "caller is not bound to a tenant"
+	ErrAuthenticationRequired     = "authentication is required"
+
+	// Audit errors
+	ErrFailedToRecordAudit = "failed to record audit event: %v"
+
+	// Model catalog errors
+	ErrInvalidCatalog            = "invalid model catalog: %v"
+	ErrInvalidCatalogModel       = "invalid model catalog entry %q: %s"
+	ErrDuplicateCatalogModel     = "model %q is listed twice in the catalog"
+	ErrCatalogNameTaken          = "name is used by a model of tenant %s"
+	ErrCatalogModelWithoutAPIKey = "created without an api key, add one with AddProviderKey"
+	ErrFailedToApplyCatalog      = "failed to apply catalog to model %s: %v"
+
+	// Model event errors
+	ErrFailedToPublishModelEvent = "failed to publish model event: %v"
+	ErrModelEventsUnavailable    = "model event stream is not connected"
+	ErrModelEventsInterrupted    = "model event stream interrupted, resubscribe and drop cached models"
 
 	// Validation errors
 	ErrInvalidModelName = "model name is required"
 	ErrInvalidProvider  = "provider is required"
 	ErrInvalidBaseURL   = "base URL is required"
+	ErrProviderModelID  = "provider model ID is required"
+	ErrAPIKeyRequired   = "api key is required"
+	ErrInvalidQuota     = "quotas must not be negative"
+	ErrInvalidCost      = "cost per 1k tokens must not be negative"
+	ErrInvalidStatus    = "unknown model status %q, expected active, disabled or deprecated"
+)
+
+// gRPC metadata keys carrying the tenant scope of a request
+const (
+	MetadataTenantID  = "x-tenant-id"
+	MetadataProjectID = "x-project-id"
+	MetadataProxyID   = "x-proxy-id"
+	MetadataRequestID = "x-request-id"
+)
+
+// gRPC metadata keys used to authorize a request
+const (
+	MetadataAuthorization = "authorization"
+	// MetadataGateway marks requests relayed by the HTTP gateway
+	MetadataGateway = "x-ai-model-gateway"
 )
 
 // Success messages
 const (
-	MsgModelCreated         = "model created successfully"
-	MsgModelUpdated         = "model updated successfully"
-	MsgModelDeleted         = "model deleted successfully"
-	MsgModelRetrieved       = "model retrieved successfully"
-	MsgModelsListed         = "models listed successfully"
-	MsgCredentialsRetrieved = "credentials retrieved successfully"
-	MsgUsageLogged          = "usage logged successfully"
-	MsgQuotaChecked         = "quota checked successfully"
+	MsgModelCreated          = "model created successfully"
+	MsgModelUpdated          = "model updated successfully"
+	MsgModelDeleted          = "model deleted successfully"
+	MsgModelRestored         = "model restored successfully"
+	MsgModelRetrieved        = "model retrieved successfully"
+	MsgModelsListed          = "models listed successfully"
+	MsgCredentialsRetrieved  = "credentials retrieved successfully"
+	MsgCredentialLeaseIssued = "credential lease issued successfully"
+	MsgWatchingModels        = "watching model changes"
+	MsgAuditEventsListed     = "audit events listed successfully"
+	MsgAuditChainVerified    = "audit chain verified"
+	MsgAuditChainBroken      = "audit chain broken at sequence %d"
+	MsgUsageLogged           = "usage logged successfully"
+	MsgUsageBatchLogged      = "usage batch logged successfully"
+	MsgUsageStatsRetrieved   = "usage stats retrieved successfully"
+	MsgQuotaChecked          = "quota checked successfully"
+	MsgAPIKeyCreated         = "api key created successfully"
+	MsgAPIKeysListed         = "api keys listed successfully"
+	MsgAPIKeyRevoked         = "api key revoked successfully"
+	MsgAPIKeyVerified        = "api key verified successfully"
+	MsgTenantCreated         = "tenant created successfully"
+	MsgTenantsListed         = "tenants listed successfully"
+	MsgProjectCreated        = "project created successfully"
+	MsgProjectsListed        = "projects listed successfully"
+	MsgPricingSet            = "pricing set successfully"
+	MsgPricingListed         = "pricing listed successfully"
+	MsgModelAliasSet         = "model alias set successfully"
+	MsgModelAliasRetrieved   = "model alias retrieved successfully"
+	MsgModelAliasesListed    = "model aliases listed successfully"
+	MsgModelAliasDeleted     = "model alias deleted successfully"
+	MsgModelAliasHistory     = "model alias history listed successfully"
+	MsgCandidatesListed      = "routing candidates listed successfully"
+	MsgModelDeprecated       = "model deprecated successfully"
+	MsgDeprecatedCallers     = "deprecated model callers listed successfully"
+	MsgBudgetCreated         = "budget created successfully"
+	MsgBudgetsListed         = "budgets listed successfully"
+	MsgBudgetDeleted         = "budget deleted successfully"
+	MsgBudgetStatusChecked   = "budget status checked successfully"
+	MsgProviderKeyAdded      = "provider key added successfully"
+	MsgProviderKeysListed    = "provider keys listed successfully"
+	MsgProviderKeyUpdated    = "provider key updated successfully"
+	MsgProviderKeyRevoked    = "provider key revoked successfully"
+	MsgProviderKeyReported   = "provider key failure recorded"
 )
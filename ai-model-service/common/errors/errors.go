@@ -11,6 +11,7 @@ const (
 	FORBIDDEN           ErrorCode = 403
 	NOT_FOUND           ErrorCode = 404
 	CONFLICT_ERROR      ErrorCode = 409
+	FAILED_PRECONDITION ErrorCode = 412
 	INTERNAL_ERROR      ErrorCode = 500
 	SERVICE_UNAVAILABLE ErrorCode = 503
 )
@@ -47,17 +48,33 @@ func (e *baseError) GetCode() ErrorCode {
 
 // Common error constructors
 func BadRequest(message string) BaseError {
-	return NewBaseError(BAD_REQUEST, fmt.Errorf(message))
+	return NewBaseError(BAD_REQUEST, fmt.Errorf("%s", message))
 }
 
 func NotFound(message string) BaseError {
-	return NewBaseError(NOT_FOUND, fmt.Errorf(message))
+	return NewBaseError(NOT_FOUND, fmt.Errorf("%s", message))
+}
+
+func Unauthorized(message string) BaseError {
+	return NewBaseError(UNAUTHORIZED, fmt.Errorf("%s", message))
+}
+
+func Forbidden(message string) BaseError {
+	return NewBaseError(FORBIDDEN, fmt.Errorf("%s", message))
 }
 
 func Conflict(message string) BaseError {
-	return NewBaseError(CONFLICT_ERROR, fmt.Errorf(message))
+	return NewBaseError(CONFLICT_ERROR, fmt.Errorf("%s", message))
+}
+
+func FailedPrecondition(message string) BaseError {
+	return NewBaseError(FAILED_PRECONDITION, fmt.Errorf("%s", message))
 }
 
 func Internal(err error) BaseError {
 	return NewBaseError(INTERNAL_ERROR, err)
 }
+
+func ServiceUnavailable(message string) BaseError {
+	return NewBaseError(SERVICE_UNAVAILABLE, fmt.Errorf("%s", message))
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	pb "github.com/blcvn/kratos-proto/go/ai-model"
+)
+
+// CreateAPIKey issues a new virtual API key for proxy callers
+func (c *modelController) CreateAPIKey(ctx context.Context, req *pb.CreateAPIKeyRequest) (*pb.CreateAPIKeyResponse, error) {
+	payload, err := c.transform.Pb2CreateAPIKeyPayload(req.GetPayload())
+	if err != nil {
+		return &pb.CreateAPIKeyResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_BAD_REQUEST,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	key, secret, usecaseErr := c.apiKeyUsecase.CreateAPIKey(ctx, scopeFromContext(ctx), payload)
+	if usecaseErr != nil {
+		return &pb.CreateAPIKeyResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(usecaseErr.GetCode()),
+				Message: usecaseErr.Error(),
+			},
+		}, nil
+	}
+
+	keyPb, err := c.transform.APIKey2Pb(key)
+	if err != nil {
+		return &pb.CreateAPIKeyResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_INTERNAL,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	return &pb.CreateAPIKeyResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgAPIKeyCreated,
+		},
+		ApiKey: keyPb,
+		Secret: secret,
+	}, nil
+}
+
+// ListAPIKeys lists virtual API keys
+func (c *modelController) ListAPIKeys(ctx context.Context, req *pb.ListAPIKeysRequest) (*pb.ListAPIKeysResponse, error) {
+	keys, err := c.apiKeyUsecase.ListAPIKeys(ctx, scopeFromContext(ctx), &entities.APIKeyFilter{
+		UserID: req.GetUserId(),
+	})
+	if err != nil {
+		return &pb.ListAPIKeysResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	keysPb := make([]*pb.APIKey, 0, len(keys))
+	for _, key := range keys {
+		keyPb, err := c.transform.APIKey2Pb(key)
+		if err != nil {
+			continue
+		}
+		keysPb = append(keysPb, keyPb)
+	}
+
+	return &pb.ListAPIKeysResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgAPIKeysListed,
+		},
+		ApiKeys: keysPb,
+	}, nil
+}
+
+// RevokeAPIKey revokes a virtual API key
+func (c *modelController) RevokeAPIKey(ctx context.Context, req *pb.RevokeAPIKeyRequest) (*pb.ResponseEmpty, error) {
+	if err := c.apiKeyUsecase.RevokeAPIKey(ctx, scopeFromContext(ctx), req.GetId()); err != nil {
+		return &pb.ResponseEmpty{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	return &pb.ResponseEmpty{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgAPIKeyRevoked,
+		},
+	}, nil
+}
+
+// VerifyAPIKey authenticates a virtual API key on behalf of the proxy (internal gRPC only)
+func (c *modelController) VerifyAPIKey(ctx context.Context, req *pb.VerifyAPIKeyRequest) (*pb.VerifyAPIKeyResponse, error) {
+	identity, err := c.apiKeyUsecase.VerifyAPIKey(ctx, req.GetApiKey())
+	if err != nil {
+		return &pb.VerifyAPIKeyResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	identityPb, transformErr := c.transform.CallerIdentity2Pb(identity)
+	if transformErr != nil {
+		return &pb.VerifyAPIKeyResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_INTERNAL,
+				Message: fmt.Sprintf("transform error: %v", transformErr),
+			},
+		}, nil
+	}
+
+	return &pb.VerifyAPIKeyResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgAPIKeyVerified,
+		},
+		Identity: identityPb,
+	}, nil
+}
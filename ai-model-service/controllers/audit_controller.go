@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	pb "github.com/blcvn/kratos-proto/go/ai-model"
+)
+
+// ListAuditEvents lists audit events of model changes and credential access
+func (c *modelController) ListAuditEvents(ctx context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	filter, err := c.transform.Pb2AuditEventFilter(req)
+	if err != nil {
+		return &pb.ListAuditEventsResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_BAD_REQUEST,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	events, total, usecaseErr := c.auditUsecase.ListAuditEvents(ctx, scopeFromContext(ctx), filter)
+	if usecaseErr != nil {
+		return &pb.ListAuditEventsResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(usecaseErr.GetCode()),
+				Message: usecaseErr.Error(),
+			},
+		}, nil
+	}
+
+	eventsPb := make([]*pb.AuditEvent, 0, len(events))
+	for _, event := range events {
+		eventPb, err := c.transform.AuditEvent2Pb(event)
+		if err != nil {
+			continue
+		}
+		eventsPb = append(eventsPb, eventPb)
+	}
+
+	return &pb.ListAuditEventsResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgAuditEventsListed,
+		},
+		Events: eventsPb,
+		Total:  total,
+	}, nil
+}
+
+// VerifyAuditChain checks the audit log's hash chain for altered or removed events
+func (c *modelController) VerifyAuditChain(ctx context.Context, req *pb.VerifyAuditChainRequest) (*pb.VerifyAuditChainResponse, error) {
+	result, err := c.auditUsecase.VerifyAuditChain(ctx, scopeFromContext(ctx))
+	if err != nil {
+		return &pb.VerifyAuditChainResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	message := constants.MsgAuditChainVerified
+	if !result.Verified {
+		message = fmt.Sprintf(constants.MsgAuditChainBroken, result.BrokenSequence)
+	}
+	return &pb.VerifyAuditChainResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: message,
+		},
+		Verified:       result.Verified,
+		Checked:        result.Checked,
+		BrokenSequence: result.BrokenSequence,
+	}, nil
+}
@@ -0,0 +1,279 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// methodAccess is the kind of caller an RPC is restricted to
+type methodAccess int
+
+const (
+	// accessTenant RPCs are scoped to the tenant of the caller's token, or the tenant an admin
+	// or internal service acts for
+	accessTenant methodAccess = iota
+	// accessAdmin RPCs manage the model catalog, tenants, API keys and budgets and require the
+	// admin role
+	accessAdmin
+	// accessInternal RPCs are called by proxies and require the internal role
+	accessInternal
+	// accessCredentials RPCs hand out provider credentials; they require the internal role
+	// and, when proxy identities are configured, one of those identities
+	accessCredentials
+)
+
+// methodAccessLevels lists the RPCs that need more than a tenant-scoped caller, by method name
+var methodAccessLevels = map[string]methodAccess{
+	"CreateTenant":      accessAdmin,
+	"ListTenants":       accessAdmin,
+	"CreateProject":     accessAdmin,
+	"CreateAPIKey":      accessAdmin,
+	"ListAPIKeys":       accessAdmin,
+	"RevokeAPIKey":      accessAdmin,
+	"CreateBudget":      accessAdmin,
+	"ListBudgets":       accessAdmin,
+	"DeleteBudget":      accessAdmin,
+	"CreateModel":       accessAdmin,
+	"UpdateModel":       accessAdmin,
+	"DeleteModel":       accessAdmin,
+	"RestoreModel":      accessAdmin,
+	"DeprecateModel":    accessAdmin,
+	"SetModelPricing":   accessAdmin,
+	"SetModelAlias":     accessAdmin,
+	"DeleteModelAlias":  accessAdmin,
+	"AddProviderKey":    accessAdmin,
+	"UpdateProviderKey": accessAdmin,
+	"RevokeProviderKey": accessAdmin,
+	"ListAuditEvents":   accessAdmin,
+	"VerifyAuditChain":  accessAdmin,
+
+	"LogUsage":                 accessInternal,
+	"LogUsageBatch":            accessInternal,
+	"CheckQuota":               accessInternal,
+	"ListRoutingCandidates":    accessInternal,
+	"VerifyAPIKey":             accessInternal,
+	"ReportProviderKeyFailure": accessInternal,
+	"WatchModels":              accessInternal,
+
+	"GetCredentials":       accessCredentials,
+	"IssueCredentialLease": accessCredentials,
+}
+
+// accessOf returns the access level of a full gRPC method name
+func accessOf(fullMethod string) methodAccess {
+	return methodAccessLevels[path.Base(fullMethod)]
+}
+
+// AuthorizerOptions configures how callers are authenticated
+type AuthorizerOptions struct {
+	// Tokens verifies service tokens sent as "authorization: Bearer <token>"; nil disables them
+	Tokens iServiceTokenVerifier
+	// InternalIdentities and AdminIdentities grant roles to verified mTLS client identities
+	InternalIdentities []string
+	AdminIdentities    []string
+	// ProxyIdentities, when set, are the only identities allowed to fetch credentials
+	ProxyIdentities []string
+	// Disabled lets every caller through, for local development only
+	Disabled bool
+}
+
+// Authorizer enforces method-level access on the gRPC server. Callers authenticate with a
+// verified mTLS client certificate or a signed service token.
+type Authorizer struct {
+	tokens          iServiceTokenVerifier
+	identityRoles   map[string][]entities.ServiceRole
+	proxyIdentities map[string]bool
+	disabled        bool
+}
+
+// UnaryServerInterceptor rejects unary calls the caller is not authorized for
+func (a *Authorizer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		principal, scope, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(contextWithScope(contextWithPrincipal(ctx, principal), scope), req)
+	}
+}
+
+// StreamServerInterceptor rejects streams the caller is not authorized for
+func (a *Authorizer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		principal, scope, err := a.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		ctx := contextWithScope(contextWithPrincipal(ss.Context(), principal), scope)
+		return handler(srv, &principalServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authorize checks the caller may call fullMethod and returns the authenticated principal, if
+// any, and the tenant scope the request acts in
+func (a *Authorizer) authorize(ctx context.Context, fullMethod string) (*entities.Principal, *entities.TenantScope, error) {
+	if isInternal(fullMethod) && fromGateway(ctx) {
+		return nil, nil, status.Error(codes.NotFound, constants.ErrMethodNotExposed)
+	}
+
+	principal, err := a.principal(ctx)
+	if err != nil {
+		if a.disabled {
+			return nil, forwardedScope(ctx), nil
+		}
+		return nil, nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if a.disabled {
+		return principal, forwardedScope(ctx), nil
+	}
+	// No RPC is public
+	if principal == nil {
+		return nil, nil, status.Error(codes.Unauthenticated, constants.ErrAuthenticationRequired)
+	}
+
+	switch accessOf(fullMethod) {
+	case accessAdmin:
+		if !principal.HasRole(entities.ServiceRoleAdmin) {
+			return nil, nil, status.Error(codes.PermissionDenied, constants.ErrAdminRoleRequired)
+		}
+	case accessInternal:
+		if !principal.HasRole(entities.ServiceRoleInternal) {
+			return nil, nil, status.Error(codes.PermissionDenied, constants.ErrInternalRoleRequired)
+		}
+	case accessCredentials:
+		if !principal.HasRole(entities.ServiceRoleInternal) {
+			return nil, nil, status.Error(codes.PermissionDenied, constants.ErrInternalRoleRequired)
+		}
+		if len(a.proxyIdentities) > 0 && !a.proxyIdentities[principal.Identity] {
+			return nil, nil, status.Error(codes.PermissionDenied, constants.ErrProxyIdentityNotAllowed)
+		}
+	}
+
+	scope, err := tenantScope(ctx, principal)
+	if err != nil {
+		return nil, nil, err
+	}
+	return principal, scope, nil
+}
+
+// tenantScope resolves the tenant a request acts for from its principal. A token bound to a
+// tenant fixes it; admin and internal principals act for the tenant they forward. Anyone else
+// is refused rather than given platform-wide access.
+func tenantScope(ctx context.Context, principal *entities.Principal) (*entities.TenantScope, error) {
+	forwarded := forwardedScope(ctx)
+	switch {
+	case principal.TenantID != "":
+		if forwarded.TenantID != "" && forwarded.TenantID != principal.TenantID {
+			return nil, status.Error(codes.PermissionDenied, fmt.Sprintf(constants.ErrServiceTokenTenantMismatch, principal.TenantID))
+		}
+		return &entities.TenantScope{TenantID: principal.TenantID, ProjectID: forwarded.ProjectID}, nil
+	case principal.HasRole(entities.ServiceRoleAdmin), principal.HasRole(entities.ServiceRoleInternal):
+		return forwarded, nil
+	}
+	return nil, status.Error(codes.PermissionDenied, constants.ErrTenantRequired)
+}
+
+// principal authenticates the caller. A service token takes precedence over the mTLS
+// certificate; requests relayed by the HTTP gateway carry the gateway's own certificate,
+// so only tokens count for them. It returns nil for an anonymous caller.
+func (a *Authorizer) principal(ctx context.Context) (*entities.Principal, error) {
+	if token := bearerToken(ctx); token != "" && a.tokens != nil {
+		claims, err := a.tokens.VerifyServiceToken(token, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf(constants.ErrInvalidServiceToken, err)
+		}
+		return &entities.Principal{Identity: claims.Subject, Roles: claims.Roles, TenantID: claims.TenantID}, nil
+	}
+
+	if fromGateway(ctx) {
+		return nil, nil
+	}
+	identity := verifiedPeerIdentity(ctx)
+	if roles, ok := a.identityRoles[identity]; ok && identity != "" {
+		return &entities.Principal{Identity: identity, Roles: roles}, nil
+	}
+	return nil, nil
+}
+
+// principalKey is the context key of the authenticated principal
+type principalKey struct{}
+
+func contextWithPrincipal(ctx context.Context, principal *entities.Principal) context.Context {
+	if principal == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// principalFromContext returns the principal the authorizer authenticated, if any
+func principalFromContext(ctx context.Context) *entities.Principal {
+	principal, _ := ctx.Value(principalKey{}).(*entities.Principal)
+	return principal
+}
+
+// principalServerStream carries the authenticated principal in a stream's context
+type principalServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *principalServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// GatewayUnaryClientInterceptor marks calls relayed by the HTTP gateway and keeps internal
+// RPCs off it, so they are only reachable over gRPC
+func GatewayUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if isInternal(method) {
+			return status.Error(codes.NotFound, constants.ErrMethodNotExposed)
+		}
+		return invoker(metadata.AppendToOutgoingContext(ctx, constants.MetadataGateway, "true"), method, req, reply, cc, opts...)
+	}
+}
+
+// GatewayStreamClientInterceptor is GatewayUnaryClientInterceptor for streaming RPCs
+func GatewayStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if isInternal(method) {
+			return nil, status.Error(codes.NotFound, constants.ErrMethodNotExposed)
+		}
+		return streamer(metadata.AppendToOutgoingContext(ctx, constants.MetadataGateway, "true"), desc, cc, method, opts...)
+	}
+}
+
+// isInternal reports whether an RPC is only served over gRPC
+func isInternal(fullMethod string) bool {
+	access := accessOf(fullMethod)
+	return access == accessInternal || access == accessCredentials
+}
+
+// fromGateway reports whether a request was relayed by the HTTP gateway
+func fromGateway(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	return ok && len(md.Get(constants.MetadataGateway)) > 0
+}
+
+// bearerToken reads the token from "authorization: Bearer <token>" metadata
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, value := range md.Get(constants.MetadataAuthorization) {
+		if token, ok := strings.CutPrefix(value, "Bearer "); ok {
+			return token
+		}
+	}
+	return ""
+}
@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	pb "github.com/blcvn/kratos-proto/go/ai-model"
+)
+
+// CreateBudget creates a USD spend budget
+func (c *modelController) CreateBudget(ctx context.Context, req *pb.CreateBudgetRequest) (*pb.CreateBudgetResponse, error) {
+	payload, err := c.transform.Pb2CreateBudgetPayload(req.GetPayload())
+	if err != nil {
+		return &pb.CreateBudgetResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_BAD_REQUEST,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	budget, usecaseErr := c.budgetUsecase.CreateBudget(ctx, scopeFromContext(ctx), payload)
+	if usecaseErr != nil {
+		return &pb.CreateBudgetResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(usecaseErr.GetCode()),
+				Message: usecaseErr.Error(),
+			},
+		}, nil
+	}
+
+	budgetPb, err := c.transform.Budget2Pb(budget)
+	if err != nil {
+		return &pb.CreateBudgetResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_INTERNAL,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	return &pb.CreateBudgetResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgBudgetCreated,
+		},
+		Budget: budgetPb,
+	}, nil
+}
+
+// ListBudgets lists budgets
+func (c *modelController) ListBudgets(ctx context.Context, req *pb.ListBudgetsRequest) (*pb.ListBudgetsResponse, error) {
+	budgets, err := c.budgetUsecase.ListBudgets(ctx, scopeFromContext(ctx))
+	if err != nil {
+		return &pb.ListBudgetsResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	budgetsPb := make([]*pb.Budget, 0, len(budgets))
+	for _, budget := range budgets {
+		budgetPb, err := c.transform.Budget2Pb(budget)
+		if err != nil {
+			continue
+		}
+		budgetsPb = append(budgetsPb, budgetPb)
+	}
+
+	return &pb.ListBudgetsResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgBudgetsListed,
+		},
+		Budgets: budgetsPb,
+	}, nil
+}
+
+// DeleteBudget deletes a budget
+func (c *modelController) DeleteBudget(ctx context.Context, req *pb.DeleteBudgetRequest) (*pb.ResponseEmpty, error) {
+	if err := c.budgetUsecase.DeleteBudget(ctx, scopeFromContext(ctx), req.GetId()); err != nil {
+		return &pb.ResponseEmpty{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	return &pb.ResponseEmpty{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgBudgetDeleted,
+		},
+	}, nil
+}
+
+// GetBudgetStatus reports spend against the budgets applying to a tenant, project, user and model.
+// Blocked is set when any hard-stop budget is exhausted; the proxy rejects such requests.
+func (c *modelController) GetBudgetStatus(ctx context.Context, req *pb.GetBudgetStatusRequest) (*pb.GetBudgetStatusResponse, error) {
+	statuses, err := c.budgetUsecase.GetBudgetStatus(ctx, scopeFromContext(ctx), &entities.BudgetQuery{
+		TenantID:  req.GetTenantId(),
+		ProjectID: req.GetProjectId(),
+		UserID:    req.GetUserId(),
+		ModelID:   req.GetModelId(),
+	})
+	if err != nil {
+		return &pb.GetBudgetStatusResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	message := constants.MsgBudgetStatusChecked
+	blocked := false
+	statusesPb := make([]*pb.BudgetStatus, 0, len(statuses))
+	for _, status := range statuses {
+		if status.Blocked && !blocked {
+			blocked = true
+			message = fmt.Sprintf(constants.ErrBudgetExceeded, status.Budget.Scope, status.Budget.ScopeID)
+		}
+		statusPb, err := c.transform.BudgetStatus2Pb(status)
+		if err != nil {
+			continue
+		}
+		statusesPb = append(statusesPb, statusPb)
+	}
+
+	return &pb.GetBudgetStatusResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: message,
+		},
+		Statuses: statusesPb,
+		Blocked:  blocked,
+	}, nil
+}
@@ -1,12 +1,62 @@
 package controllers
 
+import (
+	"net/http"
+
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+)
+
 // NewModelController creates a new model controller
 func NewModelController(
 	usecase iModelUsecase,
+	apiKeyUsecase iAPIKeyUsecase,
+	tenantUsecase iTenantUsecase,
+	budgetUsecase iBudgetUsecase,
+	providerKeyUsecase iProviderKeyUsecase,
+	leaseUsecase iCredentialLeaseUsecase,
+	auditUsecase iAuditUsecase,
 	transform iTransform,
 ) *modelController {
 	return &modelController{
-		usecase:   usecase,
-		transform: transform,
+		usecase:            usecase,
+		apiKeyUsecase:      apiKeyUsecase,
+		tenantUsecase:      tenantUsecase,
+		budgetUsecase:      budgetUsecase,
+		providerKeyUsecase: providerKeyUsecase,
+		leaseUsecase:       leaseUsecase,
+		auditUsecase:       auditUsecase,
+		transform:          transform,
+	}
+}
+
+// NewEgressController creates the egress that forwards leased provider calls
+func NewEgressController(leaseUsecase iCredentialLeaseUsecase) *egressController {
+	return &egressController{
+		leaseUsecase: leaseUsecase,
+		transport:    http.DefaultTransport,
+	}
+}
+
+// NewAuthorizer creates the method-level authorizer
+func NewAuthorizer(opts AuthorizerOptions) *Authorizer {
+	identityRoles := make(map[string][]entities.ServiceRole)
+	for _, identity := range opts.InternalIdentities {
+		identityRoles[identity] = append(identityRoles[identity], entities.ServiceRoleInternal)
+	}
+	for _, identity := range opts.AdminIdentities {
+		identityRoles[identity] = append(identityRoles[identity], entities.ServiceRoleAdmin)
+	}
+	proxyIdentities := make(map[string]bool, len(opts.ProxyIdentities))
+	for _, identity := range opts.ProxyIdentities {
+		proxyIdentities[identity] = true
+		// A listed proxy is an internal caller even if it is not in InternalIdentities
+		identityRoles[identity] = append(identityRoles[identity], entities.ServiceRoleInternal)
+	}
+
+	return &Authorizer{
+		tokens:          opts.Tokens,
+		identityRoles:   identityRoles,
+		proxyIdentities: proxyIdentities,
+		disabled:        opts.Disabled,
 	}
 }
@@ -5,13 +5,20 @@ import (
 	"fmt"
 
 	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
 	pb "github.com/blcvn/kratos-proto/go/ai-model"
 )
 
 type modelController struct {
 	pb.UnimplementedAIModelServiceServer
-	usecase   iModelUsecase
-	transform iTransform
+	usecase            iModelUsecase
+	apiKeyUsecase      iAPIKeyUsecase
+	tenantUsecase      iTenantUsecase
+	budgetUsecase      iBudgetUsecase
+	providerKeyUsecase iProviderKeyUsecase
+	leaseUsecase       iCredentialLeaseUsecase
+	auditUsecase       iAuditUsecase
+	transform          iTransform
 }
 
 // CreateModel creates a new AI model
@@ -29,7 +36,7 @@ func (c *modelController) CreateModel(ctx context.Context, req *pb.CreateModelRe
 	}
 
 	// Call usecase
-	model, usecaseErr := c.usecase.CreateModel(ctx, payload)
+	model, usecaseErr := c.usecase.CreateModel(ctx, scopeFromContext(ctx), payload)
 	if usecaseErr != nil {
 		return &pb.CreateModelResponse{
 			Metadata: req.Metadata,
@@ -64,7 +71,7 @@ func (c *modelController) CreateModel(ctx context.Context, req *pb.CreateModelRe
 
 // GetModel retrieves a model by ID
 func (c *modelController) GetModel(ctx context.Context, req *pb.GetModelRequest) (*pb.GetModelResponse, error) {
-	model, err := c.usecase.GetModel(ctx, req.GetId())
+	model, err := c.usecase.GetModel(ctx, scopeFromContext(ctx), req.GetId())
 	if err != nil {
 		return &pb.GetModelResponse{
 			Metadata: req.Metadata,
@@ -101,6 +108,8 @@ func (c *modelController) ListModels(ctx context.Context, req *pb.ListModelsRequ
 	filter, err := c.transform.Pb2ModelFilter(
 		req.GetFilter().GetProvider(),
 		req.GetFilter().GetStatus(),
+		req.GetFilter().GetTier(),
+		req.GetFilter().GetIncludeDeleted(),
 		req.GetFilter().GetPage(),
 		req.GetFilter().GetPageSize(),
 	)
@@ -114,7 +123,7 @@ func (c *modelController) ListModels(ctx context.Context, req *pb.ListModelsRequ
 		}, nil
 	}
 
-	models, total, usecaseErr := c.usecase.ListModels(ctx, filter)
+	models, total, usecaseErr := c.usecase.ListModels(ctx, scopeFromContext(ctx), filter)
 	if usecaseErr != nil {
 		return &pb.ListModelsResponse{
 			Metadata: req.Metadata,
@@ -159,7 +168,7 @@ func (c *modelController) UpdateModel(ctx context.Context, req *pb.UpdateModelRe
 		}, nil
 	}
 
-	model, usecaseErr := c.usecase.UpdateModel(ctx, payload)
+	model, usecaseErr := c.usecase.UpdateModel(ctx, scopeFromContext(ctx), payload)
 	if usecaseErr != nil {
 		return &pb.UpdateModelResponse{
 			Metadata: req.Metadata,
@@ -193,7 +202,7 @@ func (c *modelController) UpdateModel(ctx context.Context, req *pb.UpdateModelRe
 
 // DeleteModel deletes a model
 func (c *modelController) DeleteModel(ctx context.Context, req *pb.DeleteModelRequest) (*pb.ResponseEmpty, error) {
-	err := c.usecase.DeleteModel(ctx, req.GetId())
+	err := c.usecase.DeleteModel(ctx, scopeFromContext(ctx), req.GetId())
 	if err != nil {
 		// Note: We can't return error details in Empty response
 		// Consider logging here
@@ -215,9 +224,43 @@ func (c *modelController) DeleteModel(ctx context.Context, req *pb.DeleteModelRe
 	}, nil
 }
 
+// RestoreModel restores a deleted model
+func (c *modelController) RestoreModel(ctx context.Context, req *pb.RestoreModelRequest) (*pb.RestoreModelResponse, error) {
+	model, usecaseErr := c.usecase.RestoreModel(ctx, scopeFromContext(ctx), req.GetId())
+	if usecaseErr != nil {
+		return &pb.RestoreModelResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(usecaseErr.GetCode()),
+				Message: usecaseErr.Error(),
+			},
+		}, nil
+	}
+
+	modelPb, err := c.transform.Model2Pb(model)
+	if err != nil {
+		return &pb.RestoreModelResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_INTERNAL,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	return &pb.RestoreModelResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgModelRestored,
+		},
+		Model: modelPb,
+	}, nil
+}
+
 // GetCredentials retrieves API credentials from Vault (internal gRPC only)
 func (c *modelController) GetCredentials(ctx context.Context, req *pb.GetCredentialsRequest) (*pb.GetCredentialsResponse, error) {
-	creds, err := c.usecase.GetCredentials(ctx, req.GetModelId())
+	creds, err := c.usecase.GetCredentials(ctx, scopeFromContext(ctx), req.GetModelId())
 	if err != nil {
 		return &pb.GetCredentialsResponse{
 			Metadata: req.Metadata,
@@ -261,11 +304,11 @@ func (c *modelController) LogUsage(ctx context.Context, req *pb.LogUsageRequest)
 		}, nil
 	}
 
-	usecaseErr := c.usecase.LogUsage(ctx, payload)
+	usecaseErr := c.usecase.LogUsage(ctx, scopeFromContext(ctx), payload)
 	if usecaseErr != nil {
 		return &pb.ResponseEmpty{
 			Result: &pb.Result{
-				Code:    pb.ResultCode_INTERNAL, // Map error code appropriately
+				Code:    pb.ResultCode(usecaseErr.GetCode()),
 				Message: usecaseErr.Error(),
 			},
 		}, nil
@@ -279,9 +322,46 @@ func (c *modelController) LogUsage(ctx context.Context, req *pb.LogUsageRequest)
 	}, nil
 }
 
+// LogUsageBatch logs a batch of usage events delivered by the proxy outbox (internal gRPC only)
+func (c *modelController) LogUsageBatch(ctx context.Context, req *pb.LogUsageBatchRequest) (*pb.LogUsageBatchResponse, error) {
+	payloads := make([]*entities.LogUsagePayload, 0, len(req.GetPayloads()))
+	// An event that cannot be decoded is rejected on its own rather than failing the batch
+	var malformed []string
+	for _, payloadPb := range req.GetPayloads() {
+		payload, err := c.transform.Pb2LogUsagePayload(payloadPb)
+		if err != nil {
+			malformed = append(malformed, payloadPb.GetEventId())
+			continue
+		}
+		payloads = append(payloads, payload)
+	}
+
+	rejected, failed, usecaseErr := c.usecase.LogUsageBatch(ctx, scopeFromContext(ctx), payloads)
+	if usecaseErr != nil {
+		return &pb.LogUsageBatchResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(usecaseErr.GetCode()),
+				Message: usecaseErr.Error(),
+			},
+		}, nil
+	}
+
+	return &pb.LogUsageBatchResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgUsageBatchLogged,
+		},
+		Accepted:         int32(len(payloads) - len(rejected) - len(failed)),
+		RejectedEventIds: append(rejected, malformed...),
+		FailedEventIds:   failed,
+	}, nil
+}
+
 // CheckQuota checks quota limits (internal gRPC only)
 func (c *modelController) CheckQuota(ctx context.Context, req *pb.CheckQuotaRequest) (*pb.CheckQuotaResponse, error) {
-	quota, err := c.usecase.CheckQuota(ctx, req.GetModelId())
+	quota, err := c.usecase.CheckQuota(ctx, scopeFromContext(ctx), req.GetModelId())
 	if err != nil {
 		return &pb.CheckQuotaResponse{
 			Metadata: req.Metadata,
@@ -315,13 +395,43 @@ func (c *modelController) CheckQuota(ctx context.Context, req *pb.CheckQuotaRequ
 
 // GetUsageStats retrieves usage statistics
 func (c *modelController) GetUsageStats(ctx context.Context, req *pb.GetUsageStatsRequest) (*pb.GetUsageStatsResponse, error) {
-	// TODO: Implement usage stats aggregation
+	filter, err := c.transform.Pb2UsageStatsFilter(req)
+	if err != nil {
+		return &pb.GetUsageStatsResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_BAD_REQUEST,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	stats, usecaseErr := c.usecase.GetUsageStats(ctx, scopeFromContext(ctx), filter)
+	if usecaseErr != nil {
+		return &pb.GetUsageStatsResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(usecaseErr.GetCode()),
+				Message: usecaseErr.Error(),
+			},
+		}, nil
+	}
+
+	statsPb := make([]*pb.UsageStats, 0, len(stats))
+	for _, stat := range stats {
+		statPb, err := c.transform.UsageStats2Pb(stat)
+		if err != nil {
+			continue
+		}
+		statsPb = append(statsPb, statPb)
+	}
+
 	return &pb.GetUsageStatsResponse{
 		Metadata: req.Metadata,
 		Result: &pb.Result{
 			Code:    pb.ResultCode_SUCCESS,
-			Message: "Usage stats retrieved",
+			Message: constants.MsgUsageStatsRetrieved,
 		},
-		Stats: []*pb.UsageStats{},
+		Stats: statsPb,
 	}, nil
 }
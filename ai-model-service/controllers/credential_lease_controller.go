@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	pb "github.com/blcvn/kratos-proto/go/ai-model"
+)
+
+// IssueCredentialLease issues a short-lived lease for calling a model's provider through the
+// egress, in place of its raw API key (internal gRPC only). The lease is bound to the proxy's
+// verified mTLS client certificate.
+func (c *modelController) IssueCredentialLease(ctx context.Context, req *pb.IssueCredentialLeaseRequest) (*pb.CredentialLeaseResponse, error) {
+	lease, err := c.leaseUsecase.IssueCredentialLease(ctx, scopeFromContext(ctx), req.GetModelId(), verifiedPeerIdentity(ctx))
+	if err != nil {
+		return &pb.CredentialLeaseResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	leasePb, transformErr := c.transform.CredentialLease2Pb(lease)
+	if transformErr != nil {
+		return &pb.CredentialLeaseResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_INTERNAL,
+				Message: fmt.Sprintf("transform error: %v", transformErr),
+			},
+		}, nil
+	}
+
+	return &pb.CredentialLeaseResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgCredentialLeaseIssued,
+		},
+		Lease: leasePb,
+	}, nil
+}
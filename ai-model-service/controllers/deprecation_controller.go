@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	pb "github.com/blcvn/kratos-proto/go/ai-model"
+)
+
+// DeprecateModel deprecates a model with a sunset date and a replacement
+func (c *modelController) DeprecateModel(ctx context.Context, req *pb.DeprecateModelRequest) (*pb.DeprecateModelResponse, error) {
+	payload, err := c.transform.Pb2DeprecateModelPayload(req.GetPayload())
+	if err != nil {
+		return &pb.DeprecateModelResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_BAD_REQUEST,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	model, usecaseErr := c.usecase.DeprecateModel(ctx, scopeFromContext(ctx), payload)
+	if usecaseErr != nil {
+		return &pb.DeprecateModelResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(usecaseErr.GetCode()),
+				Message: usecaseErr.Error(),
+			},
+		}, nil
+	}
+
+	modelPb, err := c.transform.Model2Pb(model)
+	if err != nil {
+		return &pb.DeprecateModelResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_INTERNAL,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	return &pb.DeprecateModelResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgModelDeprecated,
+		},
+		Model: modelPb,
+	}, nil
+}
+
+// GetDeprecatedModelCallers reports who still calls deprecated models
+func (c *modelController) GetDeprecatedModelCallers(ctx context.Context, req *pb.GetDeprecatedModelCallersRequest) (*pb.GetDeprecatedModelCallersResponse, error) {
+	filter := &entities.DeprecatedModelCallerFilter{ModelID: req.GetModelId()}
+	if startTime := req.GetStartTime(); startTime != nil {
+		filter.StartTime = startTime.AsTime()
+	}
+
+	callers, err := c.usecase.GetDeprecatedModelCallers(ctx, scopeFromContext(ctx), filter)
+	if err != nil {
+		return &pb.GetDeprecatedModelCallersResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	callersPb := make([]*pb.DeprecatedModelCaller, 0, len(callers))
+	for _, caller := range callers {
+		callerPb, err := c.transform.DeprecatedModelCaller2Pb(caller)
+		if err != nil {
+			continue
+		}
+		callersPb = append(callersPb, callerPb)
+	}
+
+	return &pb.GetDeprecatedModelCallersResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgDeprecatedCallers,
+		},
+		Callers: callersPb,
+	}, nil
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+)
+
+// egressController forwards provider calls made with a credential lease, replacing the lease
+// with the real API key so the key never leaves the AI Model Service.
+// Requests arrive at /egress/{model}/{path...} and go to <model base URL>/{path...}.
+type egressController struct {
+	leaseUsecase iCredentialLeaseUsecase
+	transport    http.RoundTripper
+}
+
+func (c *egressController) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := leaseFromRequest(r)
+	if token == "" {
+		writeEgressError(w, http.StatusProxyAuthRequired, "credential lease is required")
+		return
+	}
+
+	target, err := c.leaseUsecase.ResolveCredentialLease(r.Context(), token, r.PathValue("model"), egressIdentity(r))
+	if err != nil {
+		writeEgressError(w, egressStatus(err), err.Error())
+		return
+	}
+
+	upstream, parseErr := url.Parse(strings.TrimSuffix(target.BaseURL, "/") + "/" + r.PathValue("path"))
+	if parseErr != nil {
+		writeEgressError(w, http.StatusBadGateway, "invalid provider base URL")
+		return
+	}
+	upstream.RawQuery = r.URL.RawQuery
+
+	proxy := &httputil.ReverseProxy{
+		Transport: c.transport,
+		// Stream provider responses (SSE) as they arrive
+		FlushInterval: -1,
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL = upstream
+			pr.Out.Host = ""
+			pr.Out.Header.Del("Authorization")
+			pr.Out.Header.Del("X-Api-Key")
+			for name, value := range target.Headers {
+				pr.Out.Header.Set(name, value)
+			}
+			setProviderKey(pr.Out.Header, target.Provider, target.APIKey)
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			log.Printf("Egress: provider call for model %s failed: %v", r.PathValue("model"), err)
+			writeEgressError(w, http.StatusBadGateway, "provider request failed")
+		},
+	}
+	proxy.ServeHTTP(w, r)
+}
+
+// leaseFromRequest reads the lease from wherever the provider SDK put its API key
+func leaseFromRequest(r *http.Request) string {
+	if token := r.Header.Get("X-Api-Key"); token != "" {
+		return token
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return token
+	}
+	return ""
+}
+
+// egressIdentity identifies the calling proxy by its verified client certificate. Without one
+// the identity is empty and the lease is refused.
+func egressIdentity(r *http.Request) string {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		return certificateIdentity(r.TLS.VerifiedChains[0][0])
+	}
+	return ""
+}
+
+// setProviderKey authenticates the upstream request the way the provider expects
+func setProviderKey(header http.Header, provider, apiKey string) {
+	switch provider {
+	case "anthropic":
+		header.Set("X-Api-Key", apiKey)
+	default:
+		header.Set("Authorization", "Bearer "+apiKey)
+	}
+}
+
+// egressStatus maps lease failures to 407 rather than 401/403, so the proxy does not mistake
+// a rejected lease for a rejected provider key and quarantine the key
+func egressStatus(err errors.BaseError) int {
+	switch err.GetCode() {
+	case errors.UNAUTHORIZED, errors.FORBIDDEN:
+		return http.StatusProxyAuthRequired
+	case errors.NOT_FOUND:
+		return http.StatusNotFound
+	case errors.BAD_REQUEST:
+		return http.StatusBadRequest
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+func writeEgressError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"type":  "error",
+		"error": map[string]string{"type": "egress_error", "message": message},
+	})
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"time"
 
 	"github.com/blcvn/backend/services/ai-model-service/common/errors"
 	"github.com/blcvn/backend/services/ai-model-service/entities"
@@ -10,14 +11,78 @@ import (
 
 // iModelUsecase defines usecase interface
 type iModelUsecase interface {
-	CreateModel(ctx context.Context, payload *entities.CreateModelPayload) (*entities.AIModel, errors.BaseError)
-	GetModel(ctx context.Context, id string) (*entities.AIModel, errors.BaseError)
-	ListModels(ctx context.Context, filter *entities.ModelFilter) ([]*entities.AIModel, int64, errors.BaseError)
-	UpdateModel(ctx context.Context, payload *entities.UpdateModelPayload) (*entities.AIModel, errors.BaseError)
-	DeleteModel(ctx context.Context, id string) errors.BaseError
-	GetCredentials(ctx context.Context, modelID string) (*entities.Credentials, errors.BaseError)
-	LogUsage(ctx context.Context, payload *entities.LogUsagePayload) errors.BaseError
-	CheckQuota(ctx context.Context, modelID string) (*entities.QuotaStatus, errors.BaseError)
+	CreateModel(ctx context.Context, scope *entities.TenantScope, payload *entities.CreateModelPayload) (*entities.AIModel, errors.BaseError)
+	GetModel(ctx context.Context, scope *entities.TenantScope, id string) (*entities.AIModel, errors.BaseError)
+	ListModels(ctx context.Context, scope *entities.TenantScope, filter *entities.ModelFilter) ([]*entities.AIModel, int64, errors.BaseError)
+	UpdateModel(ctx context.Context, scope *entities.TenantScope, payload *entities.UpdateModelPayload) (*entities.AIModel, errors.BaseError)
+	DeleteModel(ctx context.Context, scope *entities.TenantScope, id string) errors.BaseError
+	RestoreModel(ctx context.Context, scope *entities.TenantScope, id string) (*entities.AIModel, errors.BaseError)
+	GetCredentials(ctx context.Context, scope *entities.TenantScope, modelID string) (*entities.Credentials, errors.BaseError)
+	LogUsage(ctx context.Context, scope *entities.TenantScope, payload *entities.LogUsagePayload) errors.BaseError
+	LogUsageBatch(ctx context.Context, scope *entities.TenantScope, payloads []*entities.LogUsagePayload) ([]string, []string, errors.BaseError)
+	CheckQuota(ctx context.Context, scope *entities.TenantScope, modelID string) (*entities.QuotaStatus, errors.BaseError)
+	SetModelPricing(ctx context.Context, scope *entities.TenantScope, payload *entities.SetModelPricingPayload) (*entities.ModelPricing, errors.BaseError)
+	ListModelPricing(ctx context.Context, scope *entities.TenantScope, modelID string) ([]*entities.ModelPricing, errors.BaseError)
+	SetModelAlias(ctx context.Context, scope *entities.TenantScope, payload *entities.SetModelAliasPayload) (*entities.ModelAlias, errors.BaseError)
+	GetModelAlias(ctx context.Context, scope *entities.TenantScope, name string) (*entities.ModelAlias, errors.BaseError)
+	ListModelAliases(ctx context.Context, scope *entities.TenantScope, filter *entities.ModelAliasFilter) ([]*entities.ModelAlias, errors.BaseError)
+	DeleteModelAlias(ctx context.Context, scope *entities.TenantScope, name string) errors.BaseError
+	ListModelAliasHistory(ctx context.Context, scope *entities.TenantScope, name string) ([]*entities.ModelAliasRevision, errors.BaseError)
+	ListRoutingCandidates(ctx context.Context, scope *entities.TenantScope, tier entities.ModelTier) ([]*entities.RoutingCandidate, errors.BaseError)
+	DeprecateModel(ctx context.Context, scope *entities.TenantScope, payload *entities.DeprecateModelPayload) (*entities.AIModel, errors.BaseError)
+	GetDeprecatedModelCallers(ctx context.Context, scope *entities.TenantScope, filter *entities.DeprecatedModelCallerFilter) ([]*entities.DeprecatedModelCaller, errors.BaseError)
+	GetUsageStats(ctx context.Context, scope *entities.TenantScope, filter *entities.UsageStatsFilter) ([]*entities.UsageStats, errors.BaseError)
+	WatchModels(ctx context.Context, scope *entities.TenantScope, send func(*entities.ModelEvent) error) errors.BaseError
+}
+
+// iAPIKeyUsecase defines virtual API key usecase interface
+type iAPIKeyUsecase interface {
+	CreateAPIKey(ctx context.Context, scope *entities.TenantScope, payload *entities.CreateAPIKeyPayload) (*entities.APIKey, string, errors.BaseError)
+	ListAPIKeys(ctx context.Context, scope *entities.TenantScope, filter *entities.APIKeyFilter) ([]*entities.APIKey, errors.BaseError)
+	RevokeAPIKey(ctx context.Context, scope *entities.TenantScope, id string) errors.BaseError
+	VerifyAPIKey(ctx context.Context, plaintext string) (*entities.CallerIdentity, errors.BaseError)
+}
+
+// iTenantUsecase defines tenant usecase interface
+type iTenantUsecase interface {
+	CreateTenant(ctx context.Context, scope *entities.TenantScope, payload *entities.CreateTenantPayload) (*entities.Tenant, errors.BaseError)
+	ListTenants(ctx context.Context, scope *entities.TenantScope) ([]*entities.Tenant, errors.BaseError)
+	CreateProject(ctx context.Context, scope *entities.TenantScope, payload *entities.CreateProjectPayload) (*entities.Project, errors.BaseError)
+	ListProjects(ctx context.Context, scope *entities.TenantScope, tenantID string) ([]*entities.Project, errors.BaseError)
+}
+
+// iBudgetUsecase defines budget usecase interface
+type iBudgetUsecase interface {
+	CreateBudget(ctx context.Context, scope *entities.TenantScope, payload *entities.CreateBudgetPayload) (*entities.Budget, errors.BaseError)
+	ListBudgets(ctx context.Context, scope *entities.TenantScope) ([]*entities.Budget, errors.BaseError)
+	DeleteBudget(ctx context.Context, scope *entities.TenantScope, id string) errors.BaseError
+	GetBudgetStatus(ctx context.Context, scope *entities.TenantScope, query *entities.BudgetQuery) ([]*entities.BudgetStatus, errors.BaseError)
+}
+
+// iProviderKeyUsecase defines provider key pool usecase interface
+type iProviderKeyUsecase interface {
+	AddProviderKey(ctx context.Context, scope *entities.TenantScope, payload *entities.AddProviderKeyPayload) (*entities.ProviderKey, errors.BaseError)
+	ListProviderKeys(ctx context.Context, scope *entities.TenantScope, modelID string) ([]*entities.ProviderKey, errors.BaseError)
+	UpdateProviderKey(ctx context.Context, scope *entities.TenantScope, payload *entities.UpdateProviderKeyPayload) (*entities.ProviderKey, errors.BaseError)
+	RevokeProviderKey(ctx context.Context, scope *entities.TenantScope, modelID, id string) errors.BaseError
+	ReportProviderKeyFailure(ctx context.Context, scope *entities.TenantScope, failure *entities.ProviderKeyFailure) errors.BaseError
+}
+
+// iCredentialLeaseUsecase defines credential lease usecase interface
+type iCredentialLeaseUsecase interface {
+	IssueCredentialLease(ctx context.Context, scope *entities.TenantScope, modelID, identity string) (*entities.IssuedCredentialLease, errors.BaseError)
+	ResolveCredentialLease(ctx context.Context, token, modelID, identity string) (*entities.EgressTarget, errors.BaseError)
+}
+
+// iAuditUsecase defines audit log usecase interface
+type iAuditUsecase interface {
+	ListAuditEvents(ctx context.Context, scope *entities.TenantScope, filter *entities.AuditEventFilter) ([]*entities.AuditEvent, int64, errors.BaseError)
+	VerifyAuditChain(ctx context.Context, scope *entities.TenantScope) (*entities.AuditChainVerification, errors.BaseError)
+}
+
+// iServiceTokenVerifier verifies signed service tokens
+type iServiceTokenVerifier interface {
+	VerifyServiceToken(token string, now time.Time) (*entities.ServiceToken, error)
 }
 
 // iTransform defines transformation interface
@@ -25,11 +90,38 @@ type iTransform interface {
 	// Entity to Proto
 	Model2Pb(model *entities.AIModel) (*pb.AIModel, error)
 	Credentials2Pb(creds *entities.Credentials) (*pb.Credentials, error)
+	CredentialLease2Pb(lease *entities.IssuedCredentialLease) (*pb.CredentialLease, error)
+	ModelEvent2Pb(event *entities.ModelEvent) (*pb.ModelEvent, error)
 	QuotaStatus2Pb(quota *entities.QuotaStatus) (*pb.QuotaStatus, error)
+	APIKey2Pb(key *entities.APIKey) (*pb.APIKey, error)
+	CallerIdentity2Pb(identity *entities.CallerIdentity) (*pb.CallerIdentity, error)
+	Tenant2Pb(tenant *entities.Tenant) (*pb.Tenant, error)
+	Project2Pb(project *entities.Project) (*pb.Project, error)
+	Budget2Pb(budget *entities.Budget) (*pb.Budget, error)
+	BudgetStatus2Pb(status *entities.BudgetStatus) (*pb.BudgetStatus, error)
+	ModelPricing2Pb(pricing *entities.ModelPricing) (*pb.ModelPricing, error)
+	ModelAlias2Pb(alias *entities.ModelAlias) (*pb.ModelAlias, error)
+	ModelAliasRevision2Pb(revision *entities.ModelAliasRevision) (*pb.ModelAliasRevision, error)
+	RoutingCandidate2Pb(candidate *entities.RoutingCandidate) (*pb.RoutingCandidate, error)
+	DeprecatedModelCaller2Pb(caller *entities.DeprecatedModelCaller) (*pb.DeprecatedModelCaller, error)
+	UsageStats2Pb(stats *entities.UsageStats) (*pb.UsageStats, error)
+	ProviderKey2Pb(key *entities.ProviderKey) (*pb.ProviderKey, error)
+	AuditEvent2Pb(event *entities.AuditEvent) (*pb.AuditEvent, error)
 
 	// Proto to Entity
 	Pb2CreateModelPayload(pb *pb.CreateModelPayload) (*entities.CreateModelPayload, error)
 	Pb2UpdateModelPayload(pb *pb.UpdateModelPayload) (*entities.UpdateModelPayload, error)
 	Pb2LogUsagePayload(pb *pb.LogUsagePayload) (*entities.LogUsagePayload, error)
-	Pb2ModelFilter(provider string, status pb.ModelStatus, page, pageSize int32) (*entities.ModelFilter, error)
+	Pb2DeprecateModelPayload(pb *pb.DeprecateModelPayload) (*entities.DeprecateModelPayload, error)
+	Pb2ModelFilter(provider string, status pb.ModelStatus, tier string, includeDeleted bool, page, pageSize int32) (*entities.ModelFilter, error)
+	Pb2CreateAPIKeyPayload(pb *pb.CreateAPIKeyPayload) (*entities.CreateAPIKeyPayload, error)
+	Pb2CreateTenantPayload(pb *pb.CreateTenantPayload) (*entities.CreateTenantPayload, error)
+	Pb2CreateProjectPayload(pb *pb.CreateProjectPayload) (*entities.CreateProjectPayload, error)
+	Pb2CreateBudgetPayload(pb *pb.CreateBudgetPayload) (*entities.CreateBudgetPayload, error)
+	Pb2SetModelPricingPayload(pb *pb.SetModelPricingPayload) (*entities.SetModelPricingPayload, error)
+	Pb2SetModelAliasPayload(pb *pb.SetModelAliasPayload) (*entities.SetModelAliasPayload, error)
+	Pb2UsageStatsFilter(req *pb.GetUsageStatsRequest) (*entities.UsageStatsFilter, error)
+	Pb2AddProviderKeyPayload(pb *pb.AddProviderKeyPayload) (*entities.AddProviderKeyPayload, error)
+	Pb2UpdateProviderKeyPayload(pb *pb.UpdateProviderKeyPayload) (*entities.UpdateProviderKeyPayload, error)
+	Pb2AuditEventFilter(req *pb.ListAuditEventsRequest) (*entities.AuditEventFilter, error)
 }
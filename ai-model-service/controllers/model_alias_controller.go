@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	pb "github.com/blcvn/kratos-proto/go/ai-model"
+)
+
+// SetModelAlias creates a model alias or atomically re-points it to another model
+func (c *modelController) SetModelAlias(ctx context.Context, req *pb.SetModelAliasRequest) (*pb.SetModelAliasResponse, error) {
+	payload, err := c.transform.Pb2SetModelAliasPayload(req.GetPayload())
+	if err != nil {
+		return &pb.SetModelAliasResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_BAD_REQUEST,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	alias, usecaseErr := c.usecase.SetModelAlias(ctx, scopeFromContext(ctx), payload)
+	if usecaseErr != nil {
+		return &pb.SetModelAliasResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(usecaseErr.GetCode()),
+				Message: usecaseErr.Error(),
+			},
+		}, nil
+	}
+
+	aliasPb, err := c.transform.ModelAlias2Pb(alias)
+	if err != nil {
+		return &pb.SetModelAliasResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_INTERNAL,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	return &pb.SetModelAliasResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgModelAliasSet,
+		},
+		Alias: aliasPb,
+	}, nil
+}
+
+// GetModelAlias retrieves a model alias and the model it points to
+func (c *modelController) GetModelAlias(ctx context.Context, req *pb.GetModelAliasRequest) (*pb.GetModelAliasResponse, error) {
+	alias, usecaseErr := c.usecase.GetModelAlias(ctx, scopeFromContext(ctx), req.GetName())
+	if usecaseErr != nil {
+		return &pb.GetModelAliasResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(usecaseErr.GetCode()),
+				Message: usecaseErr.Error(),
+			},
+		}, nil
+	}
+
+	aliasPb, err := c.transform.ModelAlias2Pb(alias)
+	if err != nil {
+		return &pb.GetModelAliasResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_INTERNAL,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	return &pb.GetModelAliasResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgModelAliasRetrieved,
+		},
+		Alias: aliasPb,
+	}, nil
+}
+
+// ListModelAliases lists model aliases, optionally only those pointing to a model
+func (c *modelController) ListModelAliases(ctx context.Context, req *pb.ListModelAliasesRequest) (*pb.ListModelAliasesResponse, error) {
+	aliases, err := c.usecase.ListModelAliases(ctx, scopeFromContext(ctx), &entities.ModelAliasFilter{ModelID: req.GetModelId()})
+	if err != nil {
+		return &pb.ListModelAliasesResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	aliasesPb := make([]*pb.ModelAlias, 0, len(aliases))
+	for _, alias := range aliases {
+		aliasPb, err := c.transform.ModelAlias2Pb(alias)
+		if err != nil {
+			continue
+		}
+		aliasesPb = append(aliasesPb, aliasPb)
+	}
+
+	return &pb.ListModelAliasesResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgModelAliasesListed,
+		},
+		Aliases: aliasesPb,
+	}, nil
+}
+
+// DeleteModelAlias deletes a model alias, keeping its history
+func (c *modelController) DeleteModelAlias(ctx context.Context, req *pb.DeleteModelAliasRequest) (*pb.ResponseEmpty, error) {
+	if err := c.usecase.DeleteModelAlias(ctx, scopeFromContext(ctx), req.GetName()); err != nil {
+		return &pb.ResponseEmpty{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	return &pb.ResponseEmpty{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgModelAliasDeleted,
+		},
+	}, nil
+}
+
+// ListModelAliasHistory lists the models an alias pointed to over time, newest first
+func (c *modelController) ListModelAliasHistory(ctx context.Context, req *pb.ListModelAliasHistoryRequest) (*pb.ListModelAliasHistoryResponse, error) {
+	revisions, err := c.usecase.ListModelAliasHistory(ctx, scopeFromContext(ctx), req.GetName())
+	if err != nil {
+		return &pb.ListModelAliasHistoryResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	revisionsPb := make([]*pb.ModelAliasRevision, 0, len(revisions))
+	for _, revision := range revisions {
+		revisionPb, err := c.transform.ModelAliasRevision2Pb(revision)
+		if err != nil {
+			continue
+		}
+		revisionsPb = append(revisionsPb, revisionPb)
+	}
+
+	return &pb.ListModelAliasHistoryResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgModelAliasHistory,
+		},
+		Revisions: revisionsPb,
+	}, nil
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	pb "github.com/blcvn/kratos-proto/go/ai-model"
+)
+
+// WatchModels streams model changes so proxies can invalidate cached models and credentials
+// (internal gRPC only). The first response carries no event and confirms the watch is in place;
+// a final non-success result means events may have been missed.
+func (c *modelController) WatchModels(req *pb.WatchModelsRequest, stream pb.AIModelService_WatchModelsServer) error {
+	ctx := stream.Context()
+	err := c.usecase.WatchModels(ctx, scopeFromContext(ctx), func(event *entities.ModelEvent) error {
+		if event == nil {
+			return stream.Send(&pb.WatchModelsResponse{
+				Metadata: req.Metadata,
+				Result: &pb.Result{
+					Code:    pb.ResultCode_SUCCESS,
+					Message: constants.MsgWatchingModels,
+				},
+			})
+		}
+
+		eventPb, err := c.transform.ModelEvent2Pb(event)
+		if err != nil {
+			return stream.Send(&pb.WatchModelsResponse{
+				Metadata: req.Metadata,
+				Result: &pb.Result{
+					Code:    pb.ResultCode_INTERNAL,
+					Message: fmt.Sprintf("transform error: %v", err),
+				},
+			})
+		}
+		return stream.Send(&pb.WatchModelsResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_SUCCESS,
+				Message: constants.MsgWatchingModels,
+			},
+			Event: eventPb,
+		})
+	})
+	if err != nil {
+		return stream.Send(&pb.WatchModelsResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		})
+	}
+	return nil
+}
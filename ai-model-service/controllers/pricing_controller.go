@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	pb "github.com/blcvn/kratos-proto/go/ai-model"
+)
+
+// SetModelPricing adds an effective-dated pricing version to a model
+func (c *modelController) SetModelPricing(ctx context.Context, req *pb.SetModelPricingRequest) (*pb.SetModelPricingResponse, error) {
+	payload, err := c.transform.Pb2SetModelPricingPayload(req.GetPayload())
+	if err != nil {
+		return &pb.SetModelPricingResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_BAD_REQUEST,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	pricing, usecaseErr := c.usecase.SetModelPricing(ctx, scopeFromContext(ctx), payload)
+	if usecaseErr != nil {
+		return &pb.SetModelPricingResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(usecaseErr.GetCode()),
+				Message: usecaseErr.Error(),
+			},
+		}, nil
+	}
+
+	pricingPb, err := c.transform.ModelPricing2Pb(pricing)
+	if err != nil {
+		return &pb.SetModelPricingResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_INTERNAL,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	return &pb.SetModelPricingResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgPricingSet,
+		},
+		Pricing: pricingPb,
+	}, nil
+}
+
+// ListModelPricing lists the pricing history of a model
+func (c *modelController) ListModelPricing(ctx context.Context, req *pb.ListModelPricingRequest) (*pb.ListModelPricingResponse, error) {
+	pricing, err := c.usecase.ListModelPricing(ctx, scopeFromContext(ctx), req.GetModelId())
+	if err != nil {
+		return &pb.ListModelPricingResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	pricingPb := make([]*pb.ModelPricing, 0, len(pricing))
+	for _, version := range pricing {
+		versionPb, err := c.transform.ModelPricing2Pb(version)
+		if err != nil {
+			continue
+		}
+		pricingPb = append(pricingPb, versionPb)
+	}
+
+	return &pb.ListModelPricingResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgPricingListed,
+		},
+		Pricing: pricingPb,
+	}, nil
+}
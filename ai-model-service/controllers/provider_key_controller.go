@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	pb "github.com/blcvn/kratos-proto/go/ai-model"
+)
+
+// AddProviderKey adds a provider API key to a model's key pool
+func (c *modelController) AddProviderKey(ctx context.Context, req *pb.AddProviderKeyRequest) (*pb.ProviderKeyResponse, error) {
+	payload, err := c.transform.Pb2AddProviderKeyPayload(req.GetPayload())
+	if err != nil {
+		return &pb.ProviderKeyResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_BAD_REQUEST,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	key, usecaseErr := c.providerKeyUsecase.AddProviderKey(ctx, scopeFromContext(ctx), payload)
+	if usecaseErr != nil {
+		return &pb.ProviderKeyResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(usecaseErr.GetCode()),
+				Message: usecaseErr.Error(),
+			},
+		}, nil
+	}
+
+	return c.providerKeyResponse(req.Metadata, key, constants.MsgProviderKeyAdded), nil
+}
+
+// ListProviderKeys lists the keys in a model's pool
+func (c *modelController) ListProviderKeys(ctx context.Context, req *pb.ListProviderKeysRequest) (*pb.ListProviderKeysResponse, error) {
+	keys, err := c.providerKeyUsecase.ListProviderKeys(ctx, scopeFromContext(ctx), req.GetModelId())
+	if err != nil {
+		return &pb.ListProviderKeysResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	keysPb := make([]*pb.ProviderKey, 0, len(keys))
+	for _, key := range keys {
+		keyPb, err := c.transform.ProviderKey2Pb(key)
+		if err != nil {
+			continue
+		}
+		keysPb = append(keysPb, keyPb)
+	}
+
+	return &pb.ListProviderKeysResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgProviderKeysListed,
+		},
+		Keys: keysPb,
+	}, nil
+}
+
+// UpdateProviderKey reweights, enables or disables a key
+func (c *modelController) UpdateProviderKey(ctx context.Context, req *pb.UpdateProviderKeyRequest) (*pb.ProviderKeyResponse, error) {
+	payload, err := c.transform.Pb2UpdateProviderKeyPayload(req.GetPayload())
+	if err != nil {
+		return &pb.ProviderKeyResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_BAD_REQUEST,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	key, usecaseErr := c.providerKeyUsecase.UpdateProviderKey(ctx, scopeFromContext(ctx), payload)
+	if usecaseErr != nil {
+		return &pb.ProviderKeyResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(usecaseErr.GetCode()),
+				Message: usecaseErr.Error(),
+			},
+		}, nil
+	}
+
+	return c.providerKeyResponse(req.Metadata, key, constants.MsgProviderKeyUpdated), nil
+}
+
+// RevokeProviderKey permanently removes a key from a model's pool
+func (c *modelController) RevokeProviderKey(ctx context.Context, req *pb.RevokeProviderKeyRequest) (*pb.ResponseEmpty, error) {
+	if err := c.providerKeyUsecase.RevokeProviderKey(ctx, scopeFromContext(ctx), req.GetModelId(), req.GetId()); err != nil {
+		return &pb.ResponseEmpty{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	return &pb.ResponseEmpty{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgProviderKeyRevoked,
+		},
+	}, nil
+}
+
+// ReportProviderKeyFailure quarantines a key the provider rejected (internal gRPC only)
+func (c *modelController) ReportProviderKeyFailure(ctx context.Context, req *pb.ReportProviderKeyFailureRequest) (*pb.ResponseEmpty, error) {
+	err := c.providerKeyUsecase.ReportProviderKeyFailure(ctx, scopeFromContext(ctx), &entities.ProviderKeyFailure{
+		ModelID:    req.GetModelId(),
+		KeyID:      req.GetKeyId(),
+		StatusCode: req.GetStatusCode(),
+		RetryAfter: time.Duration(req.GetRetryAfterSeconds()) * time.Second,
+	})
+	if err != nil {
+		return &pb.ResponseEmpty{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	return &pb.ResponseEmpty{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgProviderKeyReported,
+		},
+	}, nil
+}
+
+func (c *modelController) providerKeyResponse(metadata *pb.Metadata, key *entities.ProviderKey, message string) *pb.ProviderKeyResponse {
+	keyPb, err := c.transform.ProviderKey2Pb(key)
+	if err != nil {
+		return &pb.ProviderKeyResponse{
+			Metadata: metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_INTERNAL,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}
+	}
+
+	return &pb.ProviderKeyResponse{
+		Metadata: metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: message,
+		},
+		Key: keyPb,
+	}
+}
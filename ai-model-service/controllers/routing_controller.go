@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	pb "github.com/blcvn/kratos-proto/go/ai-model"
+)
+
+// ListRoutingCandidates lists the models of a tier with their price and remaining quota
+func (c *modelController) ListRoutingCandidates(ctx context.Context, req *pb.ListRoutingCandidatesRequest) (*pb.ListRoutingCandidatesResponse, error) {
+	candidates, err := c.usecase.ListRoutingCandidates(ctx, scopeFromContext(ctx), entities.ModelTier(req.GetTier()))
+	if err != nil {
+		return &pb.ListRoutingCandidatesResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	candidatesPb := make([]*pb.RoutingCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		candidatePb, err := c.transform.RoutingCandidate2Pb(candidate)
+		if err != nil {
+			continue
+		}
+		candidatesPb = append(candidatesPb, candidatePb)
+	}
+
+	return &pb.ListRoutingCandidatesResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgCandidatesListed,
+		},
+		Candidates: candidatesPb,
+	}, nil
+}
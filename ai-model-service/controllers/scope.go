@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"net"
+	"strings"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// auditMetadataKeys are the request metadata recorded with audit events
+var auditMetadataKeys = []string{
+	constants.MetadataRequestID,
+	constants.MetadataProxyID,
+	"user-agent",
+	"grpcgateway-user-agent",
+}
+
+// scopeKey is the context key of the tenant scope the authorizer resolved
+type scopeKey struct{}
+
+func contextWithScope(ctx context.Context, scope *entities.TenantScope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// scopeFromContext returns the tenant scope the authorizer resolved for the request, with the
+// actor that made it. A request the authorizer did not scope is restricted to global resources.
+func scopeFromContext(ctx context.Context) *entities.TenantScope {
+	scope := &entities.TenantScope{Actor: actorFromContext(ctx)}
+	if resolved, ok := ctx.Value(scopeKey{}).(*entities.TenantScope); ok && resolved != nil {
+		scope.TenantID = resolved.TenantID
+		scope.ProjectID = resolved.ProjectID
+		scope.Platform = resolved.Platform
+	}
+	return scope
+}
+
+// forwardedScope reads the tenant and project forwarded in gRPC metadata. It is only trusted
+// from admin and internal principals, such as a proxy acting for its caller; without a tenant
+// they act at platform level.
+func forwardedScope(ctx context.Context) *entities.TenantScope {
+	scope := &entities.TenantScope{}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(constants.MetadataTenantID); len(values) > 0 {
+			scope.TenantID = values[0]
+		}
+		if values := md.Get(constants.MetadataProjectID); len(values) > 0 {
+			scope.ProjectID = values[0]
+		}
+	}
+	scope.Platform = scope.TenantID == ""
+	return scope
+}
+
+// actorFromContext describes who made the request for the audit log: the authenticated
+// principal, where the request came from and selected request metadata
+func actorFromContext(ctx context.Context) *entities.Actor {
+	actor := &entities.Actor{SourceIP: sourceIP(ctx)}
+	if principal := principalFromContext(ctx); principal != nil {
+		actor.Identity = principal.Identity
+	} else if !fromGateway(ctx) {
+		actor.Identity = verifiedPeerIdentity(ctx)
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return actor
+	}
+	for _, key := range auditMetadataKeys {
+		if values := md.Get(key); len(values) > 0 {
+			if actor.Metadata == nil {
+				actor.Metadata = make(map[string]string)
+			}
+			actor.Metadata[key] = values[0]
+		}
+	}
+	return actor
+}
+
+// sourceIP returns the caller's address. For requests relayed by the HTTP gateway that is the
+// last X-Forwarded-For entry, which the gateway appends itself; earlier entries are client supplied.
+func sourceIP(ctx context.Context) string {
+	if fromGateway(ctx) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get("x-forwarded-for"); len(values) > 0 {
+			forwarded := strings.Split(values[len(values)-1], ",")
+			return strings.TrimSpace(forwarded[len(forwarded)-1])
+		}
+		return ""
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// verifiedPeerIdentity returns the identity of the caller's verified mTLS client certificate, if any
+func verifiedPeerIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 {
+		return ""
+	}
+	return certificateIdentity(tlsInfo.State.VerifiedChains[0][0])
+}
+
+// certificateIdentity names a peer by its first URI SAN (e.g. a SPIFFE ID) or its common name
+func certificateIdentity(cert *x509.Certificate) string {
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	return cert.Subject.CommonName
+}
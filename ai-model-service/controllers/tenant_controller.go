@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	pb "github.com/blcvn/kratos-proto/go/ai-model"
+)
+
+// CreateTenant creates a new tenant
+func (c *modelController) CreateTenant(ctx context.Context, req *pb.CreateTenantRequest) (*pb.CreateTenantResponse, error) {
+	payload, err := c.transform.Pb2CreateTenantPayload(req.GetPayload())
+	if err != nil {
+		return &pb.CreateTenantResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_BAD_REQUEST,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	tenant, usecaseErr := c.tenantUsecase.CreateTenant(ctx, scopeFromContext(ctx), payload)
+	if usecaseErr != nil {
+		return &pb.CreateTenantResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(usecaseErr.GetCode()),
+				Message: usecaseErr.Error(),
+			},
+		}, nil
+	}
+
+	tenantPb, err := c.transform.Tenant2Pb(tenant)
+	if err != nil {
+		return &pb.CreateTenantResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_INTERNAL,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	return &pb.CreateTenantResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgTenantCreated,
+		},
+		Tenant: tenantPb,
+	}, nil
+}
+
+// ListTenants lists tenants visible to the caller
+func (c *modelController) ListTenants(ctx context.Context, req *pb.ListTenantsRequest) (*pb.ListTenantsResponse, error) {
+	tenants, err := c.tenantUsecase.ListTenants(ctx, scopeFromContext(ctx))
+	if err != nil {
+		return &pb.ListTenantsResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	tenantsPb := make([]*pb.Tenant, 0, len(tenants))
+	for _, tenant := range tenants {
+		tenantPb, err := c.transform.Tenant2Pb(tenant)
+		if err != nil {
+			continue
+		}
+		tenantsPb = append(tenantsPb, tenantPb)
+	}
+
+	return &pb.ListTenantsResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgTenantsListed,
+		},
+		Tenants: tenantsPb,
+	}, nil
+}
+
+// CreateProject creates a project within a tenant
+func (c *modelController) CreateProject(ctx context.Context, req *pb.CreateProjectRequest) (*pb.CreateProjectResponse, error) {
+	payload, err := c.transform.Pb2CreateProjectPayload(req.GetPayload())
+	if err != nil {
+		return &pb.CreateProjectResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_BAD_REQUEST,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	project, usecaseErr := c.tenantUsecase.CreateProject(ctx, scopeFromContext(ctx), payload)
+	if usecaseErr != nil {
+		return &pb.CreateProjectResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(usecaseErr.GetCode()),
+				Message: usecaseErr.Error(),
+			},
+		}, nil
+	}
+
+	projectPb, err := c.transform.Project2Pb(project)
+	if err != nil {
+		return &pb.CreateProjectResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode_INTERNAL,
+				Message: fmt.Sprintf("transform error: %v", err),
+			},
+		}, nil
+	}
+
+	return &pb.CreateProjectResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgProjectCreated,
+		},
+		Project: projectPb,
+	}, nil
+}
+
+// ListProjects lists the projects of a tenant
+func (c *modelController) ListProjects(ctx context.Context, req *pb.ListProjectsRequest) (*pb.ListProjectsResponse, error) {
+	projects, err := c.tenantUsecase.ListProjects(ctx, scopeFromContext(ctx), req.GetTenantId())
+	if err != nil {
+		return &pb.ListProjectsResponse{
+			Metadata: req.Metadata,
+			Result: &pb.Result{
+				Code:    pb.ResultCode(err.GetCode()),
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	projectsPb := make([]*pb.Project, 0, len(projects))
+	for _, project := range projects {
+		projectPb, err := c.transform.Project2Pb(project)
+		if err != nil {
+			continue
+		}
+		projectsPb = append(projectsPb, projectPb)
+	}
+
+	return &pb.ListProjectsResponse{
+		Metadata: req.Metadata,
+		Result: &pb.Result{
+			Code:    pb.ResultCode_SUCCESS,
+			Message: constants.MsgProjectsListed,
+		},
+		Projects: projectsPb,
+	}, nil
+}
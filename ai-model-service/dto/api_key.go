@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// APIKey represents the database model for virtual API keys
+type APIKey struct {
+	ID            uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name          string          `gorm:"type:varchar(255);not null"`
+	Prefix        string          `gorm:"type:varchar(32);not null"`
+	KeyHash       string          `gorm:"type:varchar(64);uniqueIndex;not null"`
+	TenantID      *uuid.UUID      `gorm:"type:uuid;index"`
+	ProjectID     *uuid.UUID      `gorm:"type:uuid"`
+	UserID        *uuid.UUID      `gorm:"type:uuid;index"`
+	AllowedModels string          `gorm:"type:jsonb;default:'[]'"`
+	ExpiresAt     *time.Time      `gorm:"type:timestamp"`
+	BudgetUSD     decimal.Decimal `gorm:"column:budget_usd;type:decimal(12,4);default:0"`
+	SpentUSD      decimal.Decimal `gorm:"column:spent_usd;type:decimal(14,8);not null;default:0"`
+	Status        string          `gorm:"type:varchar(50);default:'active';index"`
+	LastUsedAt    *time.Time      `gorm:"type:timestamp"`
+	CreatedAt     time.Time       `gorm:"default:now()"`
+	UpdatedAt     time.Time       `gorm:"default:now()"`
+}
+
+// TableName specifies the table name for APIKey
+func (APIKey) TableName() string {
+	return "ai_api_keys"
+}
@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEvent represents the database model for the append-only audit log
+type AuditEvent struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key"`
+	Sequence     int64     `gorm:"autoIncrement;not null;uniqueIndex"`
+	TenantID     string    `gorm:"type:varchar(64);index:idx_ai_audit_events_tenant_occurred"`
+	Actor        string    `gorm:"type:varchar(255);not null;index"`
+	Action       string    `gorm:"type:varchar(64);not null"`
+	ResourceType string    `gorm:"type:varchar(64);not null;index:idx_ai_audit_events_resource"`
+	ResourceID   string    `gorm:"type:varchar(255);not null;index:idx_ai_audit_events_resource"`
+	Changes      string    `gorm:"type:jsonb;not null;default:'[]'"`
+	SourceIP     string    `gorm:"type:varchar(64)"`
+	Metadata     string    `gorm:"type:jsonb;not null;default:'{}'"`
+	OccurredAt   time.Time `gorm:"type:timestamptz;not null;index:idx_ai_audit_events_tenant_occurred"`
+	PrevHash     string    `gorm:"type:varchar(64);not null;default:''"`
+	Hash         string    `gorm:"type:varchar(64);not null"`
+}
+
+// TableName specifies the table name for AuditEvent
+func (AuditEvent) TableName() string {
+	return "ai_audit_events"
+}
+
+// PendingAuditEvent is an audit event recorded but not yet appended to the chain
+type PendingAuditEvent struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key"`
+	TenantID     string    `gorm:"type:varchar(64)"`
+	Actor        string    `gorm:"type:varchar(255);not null"`
+	Action       string    `gorm:"type:varchar(64);not null"`
+	ResourceType string    `gorm:"type:varchar(64);not null"`
+	ResourceID   string    `gorm:"type:varchar(255);not null"`
+	Changes      string    `gorm:"type:jsonb;not null;default:'[]'"`
+	SourceIP     string    `gorm:"type:varchar(64)"`
+	Metadata     string    `gorm:"type:jsonb;not null;default:'{}'"`
+	OccurredAt   time.Time `gorm:"type:timestamptz;not null;index:idx_ai_audit_events_pending_occurred"`
+}
+
+// TableName specifies the table name for PendingAuditEvent
+func (PendingAuditEvent) TableName() string {
+	return "ai_audit_events_pending"
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Budget represents the database model for USD spend budgets
+type Budget struct {
+	ID         uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID   *uuid.UUID      `gorm:"type:uuid;index"`
+	Scope      string          `gorm:"type:varchar(50);not null;index:idx_budgets_scope"`
+	ScopeID    string          `gorm:"type:varchar(255);not null;index:idx_budgets_scope"`
+	Period     string          `gorm:"type:varchar(50);not null"`
+	LimitUSD   decimal.Decimal `gorm:"column:limit_usd;type:decimal(12,4);not null"`
+	Thresholds string          `gorm:"type:jsonb;default:'[50,80,100]'"`
+	WebhookURL string          `gorm:"type:text"`
+	HardStop   bool            `gorm:"default:false"`
+	CreatedAt  time.Time       `gorm:"default:now()"`
+	UpdatedAt  time.Time       `gorm:"default:now()"`
+}
+
+// TableName specifies the table name for Budget
+func (Budget) TableName() string {
+	return "ai_budgets"
+}
+
+// BudgetAlert records a threshold alert already sent for a budget period
+type BudgetAlert struct {
+	ID          uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	BudgetID    uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_budget_alerts_unique"`
+	PeriodStart time.Time       `gorm:"not null;uniqueIndex:idx_budget_alerts_unique"`
+	Threshold   int32           `gorm:"not null;uniqueIndex:idx_budget_alerts_unique"`
+	SpentUSD    decimal.Decimal `gorm:"column:spent_usd;type:decimal(12,4)"`
+	CreatedAt   time.Time       `gorm:"default:now()"`
+}
+
+// TableName specifies the table name for BudgetAlert
+func (BudgetAlert) TableName() string {
+	return "ai_budget_alerts"
+}
@@ -10,19 +10,35 @@ import (
 
 // AIModel represents the database model for AI models
 type AIModel struct {
-	ID              uuid.UUID       `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	Name            string          `gorm:"type:varchar(255);uniqueIndex;not null"`
-	Provider        string          `gorm:"type:varchar(100);not null;index"`
-	ModelID         string          `gorm:"type:varchar(255);not null"`
-	BaseURL         string          `gorm:"type:text;not null"`
-	EncryptedAPIKey string          `gorm:"type:text"`
-	Config          string          `gorm:"type:jsonb;default:'{}'"`
-	QuotaDaily      int64           `gorm:"default:100000"`
-	QuotaMonthly    int64           `gorm:"default:3000000"`
-	CostPer1kTokens decimal.Decimal `gorm:"type:decimal(10,4)"`
-	Status          string          `gorm:"type:varchar(50);default:'active';index"`
-	CreatedAt       time.Time       `gorm:"default:now()"`
-	UpdatedAt       time.Time       `gorm:"default:now()"`
+	ID                uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID          *uuid.UUID      `gorm:"type:uuid;index"`
+	Name              string          `gorm:"type:varchar(255);uniqueIndex:idx_ai_models_name_active,where:deleted_at IS NULL;not null"`
+	Provider          string          `gorm:"type:varchar(100);not null;index"`
+	ModelID           string          `gorm:"type:varchar(255);not null"`
+	BaseURL           string          `gorm:"type:text;not null"`
+	EncryptedAPIKey   string          `gorm:"type:text"`
+	SecretBackend     string          `gorm:"type:varchar(50);not null;default:'local'"`
+	Config            string          `gorm:"type:jsonb;default:'{}'"`
+	QuotaDaily        int64           `gorm:"default:100000"`
+	QuotaMonthly      int64           `gorm:"default:3000000"`
+	CostPer1kTokens   decimal.Decimal `gorm:"column:cost_per_1k_tokens;type:decimal(10,4)"`
+	Status            string          `gorm:"type:varchar(50);default:'active';index"`
+	Source            string          `gorm:"type:varchar(50);not null;default:'api'"`
+	SupportsTools     bool            `gorm:"not null;default:false"`
+	SupportsVision    bool            `gorm:"not null;default:false"`
+	SupportsJSONMode  bool            `gorm:"column:supports_json_mode;not null;default:false"`
+	SupportsStreaming bool            `gorm:"not null"`
+	ContextWindow     int32           `gorm:"not null;default:0"`
+	MaxOutputTokens   int32           `gorm:"not null;default:0"`
+	FallbackModels    string          `gorm:"type:jsonb;not null;default:'[]'"`
+	Tier              string          `gorm:"type:varchar(20);not null;default:'';index"`
+	DeprecatedAt      *time.Time      `gorm:"type:timestamptz"`
+	SunsetAt          *time.Time      `gorm:"type:timestamptz"`
+	ReplacementID     *uuid.UUID      `gorm:"type:uuid"`
+	CreatedAt         time.Time       `gorm:"default:now()"`
+	UpdatedAt         time.Time       `gorm:"default:now()"`
+	DeletedAt         gorm.DeletedAt  `gorm:"type:timestamptz;index"`
+	Version           int64           `gorm:"not null;default:1"`
 }
 
 // TableName specifies the table name for AIModel
@@ -38,20 +54,51 @@ func (m *AIModel) BeforeUpdate(tx *gorm.DB) error {
 
 // UsageLog represents the database model for usage logs
 type UsageLog struct {
-	ID           uuid.UUID       `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	ModelID      uuid.UUID       `gorm:"type:uuid;not null;index:idx_usage_model_created"`
-	UserID       *uuid.UUID      `gorm:"type:uuid;index:idx_usage_user_created"`
-	SessionID    *uuid.UUID      `gorm:"type:uuid"`
-	PromptHash   string          `gorm:"type:varchar(64);index"`
-	TokensUsed   int64           `gorm:"not null"`
-	Cost         decimal.Decimal `gorm:"type:decimal(10,4)"`
-	LatencyMs    int32           `gorm:"type:integer"`
-	Status       string          `gorm:"type:varchar(50);not null"`
-	ErrorMessage string          `gorm:"type:text"`
-	CreatedAt    time.Time       `gorm:"default:now();index:idx_usage_model_created,idx_usage_user_created,idx_usage_created_date"`
+	ID               uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ModelID          uuid.UUID       `gorm:"type:uuid;not null;index:idx_usage_model_created"`
+	TenantID         *uuid.UUID      `gorm:"type:uuid;index:idx_usage_tenant_created"`
+	ProjectID        *uuid.UUID      `gorm:"type:uuid;index:idx_usage_project_created"`
+	UserID           *uuid.UUID      `gorm:"type:uuid;index:idx_usage_user_created"`
+	SessionID        *uuid.UUID      `gorm:"type:uuid"`
+	APIKeyID         *uuid.UUID      `gorm:"column:api_key_id;type:uuid;index"`
+	Alias            string          `gorm:"type:varchar(255)"`
+	RequestedModelID *uuid.UUID      `gorm:"type:uuid;index"`
+	PromptHash       string          `gorm:"type:varchar(64);index"`
+	TokensUsed       int64           `gorm:"not null"`
+	PromptTokens     int64           `gorm:"default:0"`
+	CompletionTokens int64           `gorm:"default:0"`
+	CachedReadTokens int64           `gorm:"default:0"`
+	CacheWriteTokens int64           `gorm:"default:0"`
+	ImageCount       int64           `gorm:"default:0"`
+	PricingID        *uuid.UUID      `gorm:"type:uuid"`
+	Cost             decimal.Decimal `gorm:"type:decimal(14,8)"`
+	LatencyMs        int32           `gorm:"type:integer"`
+	Status           string          `gorm:"type:varchar(50);not null"`
+	ErrorMessage     string          `gorm:"type:text"`
+	EventID          *string         `gorm:"type:varchar(64);uniqueIndex:idx_usage_event"`
+	CreatedAt        time.Time       `gorm:"default:now();index:idx_usage_model_created,idx_usage_user_created,idx_usage_tenant_created,idx_usage_project_created,idx_usage_created_date;uniqueIndex:idx_usage_event"`
 }
 
 // TableName specifies the table name for UsageLog
 func (UsageLog) TableName() string {
 	return "ai_usage_logs"
 }
+
+// ModelPricing represents the database model for effective-dated model prices
+type ModelPricing struct {
+	ID                    uuid.UUID       `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ModelID               uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex:idx_pricing_model_effective"`
+	InputPer1kTokens      decimal.Decimal `gorm:"column:input_per_1k_tokens;type:decimal(14,8);default:0"`
+	OutputPer1kTokens     decimal.Decimal `gorm:"column:output_per_1k_tokens;type:decimal(14,8);default:0"`
+	CachedReadPer1kTokens decimal.Decimal `gorm:"column:cached_read_per_1k_tokens;type:decimal(14,8);default:0"`
+	CacheWritePer1kTokens decimal.Decimal `gorm:"column:cache_write_per_1k_tokens;type:decimal(14,8);default:0"`
+	PerImage              decimal.Decimal `gorm:"type:decimal(14,8);default:0"`
+	PerRequest            decimal.Decimal `gorm:"type:decimal(14,8);default:0"`
+	EffectiveFrom         time.Time       `gorm:"not null;uniqueIndex:idx_pricing_model_effective"`
+	CreatedAt             time.Time       `gorm:"default:now()"`
+}
+
+// TableName specifies the table name for ModelPricing
+func (ModelPricing) TableName() string {
+	return "ai_model_pricing"
+}
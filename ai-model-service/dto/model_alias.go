@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ModelAlias represents the database model for a name that resolves to a concrete model
+type ModelAlias struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name      string     `gorm:"type:varchar(255);uniqueIndex;not null"`
+	TenantID  *uuid.UUID `gorm:"type:uuid;index"`
+	ModelID   uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Routes    string     `gorm:"type:jsonb;not null;default:'[]'"`
+	CreatedAt time.Time  `gorm:"default:now()"`
+	UpdatedAt time.Time  `gorm:"default:now()"`
+}
+
+// TableName specifies the table name for ModelAlias
+func (ModelAlias) TableName() string {
+	return "ai_model_aliases"
+}
+
+// ModelAliasRevision represents the database model for one entry of an alias's history
+type ModelAliasRevision struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	AliasName       string     `gorm:"type:varchar(255);not null;index"`
+	TenantID        *uuid.UUID `gorm:"type:uuid"`
+	PreviousModelID *uuid.UUID `gorm:"type:uuid"`
+	ModelID         *uuid.UUID `gorm:"type:uuid"`
+	Routes          string     `gorm:"type:jsonb;not null;default:'[]'"`
+	Actor           string     `gorm:"type:varchar(255);not null"`
+	CreatedAt       time.Time  `gorm:"default:now()"`
+}
+
+// TableName specifies the table name for ModelAliasRevision
+func (ModelAliasRevision) TableName() string {
+	return "ai_model_alias_history"
+}
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProviderKey represents the database model for a model's pool of provider API keys
+type ProviderKey struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ModelID           uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Label             string     `gorm:"type:varchar(255)"`
+	KeyHint           string     `gorm:"type:varchar(16)"`
+	EncryptedKey      string     `gorm:"type:text"`
+	Weight            int32      `gorm:"default:1"`
+	Status            string     `gorm:"type:varchar(50);default:'active'"`
+	QuarantinedUntil  *time.Time `gorm:"type:timestamp"`
+	FailureCount      int32      `gorm:"default:0"`
+	LastFailureAt     *time.Time `gorm:"type:timestamp"`
+	LastRateLimitedAt *time.Time `gorm:"type:timestamp"`
+	CreatedAt         time.Time  `gorm:"default:now()"`
+	UpdatedAt         time.Time  `gorm:"default:now()"`
+}
+
+// TableName specifies the table name for ProviderKey
+func (ProviderKey) TableName() string {
+	return "ai_model_keys"
+}
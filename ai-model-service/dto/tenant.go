@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tenant represents the database model for tenants
+type Tenant struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name         string    `gorm:"type:varchar(255);uniqueIndex;not null"`
+	QuotaDaily   int64     `gorm:"default:0"`
+	QuotaMonthly int64     `gorm:"default:0"`
+	Status       string    `gorm:"type:varchar(50);default:'active';index"`
+	CreatedAt    time.Time `gorm:"default:now()"`
+	UpdatedAt    time.Time `gorm:"default:now()"`
+}
+
+// TableName specifies the table name for Tenant
+func (Tenant) TableName() string {
+	return "ai_tenants"
+}
+
+// Project represents the database model for tenant projects
+type Project struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TenantID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_projects_tenant_name"`
+	Name      string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_projects_tenant_name"`
+	CreatedAt time.Time `gorm:"default:now()"`
+	UpdatedAt time.Time `gorm:"default:now()"`
+}
+
+// TableName specifies the table name for Project
+func (Project) TableName() string {
+	return "ai_projects"
+}
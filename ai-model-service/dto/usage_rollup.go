@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// UsageRollup represents usage pre-aggregated per model and tenant for one time bucket.
+// TenantID is uuid.Nil for usage without a tenant so it can be part of the primary key.
+type UsageRollup struct {
+	BucketStart      time.Time       `gorm:"primaryKey"`
+	ModelID          uuid.UUID       `gorm:"type:uuid;primaryKey"`
+	TenantID         uuid.UUID       `gorm:"type:uuid;primaryKey;index"`
+	RequestCount     int64           `gorm:"not null;default:0"`
+	ErrorCount       int64           `gorm:"not null;default:0"`
+	TokensUsed       int64           `gorm:"not null;default:0"`
+	SuccessTokens    int64           `gorm:"not null;default:0"`
+	PromptTokens     int64           `gorm:"not null;default:0"`
+	CompletionTokens int64           `gorm:"not null;default:0"`
+	Cost             decimal.Decimal `gorm:"type:decimal(14,8);not null;default:0"`
+	UpdatedAt        time.Time       `gorm:"default:now()"`
+}
+
+// HourlyUsage is the hourly usage rollup
+type HourlyUsage UsageRollup
+
+// TableName specifies the table name for HourlyUsage
+func (HourlyUsage) TableName() string {
+	return "ai_usage_hourly"
+}
+
+// DailyUsage is the daily (UTC) usage rollup
+type DailyUsage UsageRollup
+
+// TableName specifies the table name for DailyUsage
+func (DailyUsage) TableName() string {
+	return "ai_usage_daily"
+}
+
+// DailySpend is the daily (UTC) spend of one project or user within a tenant, kept for budgets.
+// Scope is "project" or "user"; TenantID is uuid.Nil for usage without a tenant.
+type DailySpend struct {
+	BucketStart time.Time       `gorm:"primaryKey"`
+	Scope       string          `gorm:"type:varchar(16);primaryKey"`
+	ScopeID     uuid.UUID       `gorm:"type:uuid;primaryKey"`
+	TenantID    uuid.UUID       `gorm:"type:uuid;primaryKey"`
+	Cost        decimal.Decimal `gorm:"type:decimal(14,8);not null;default:0"`
+	UpdatedAt   time.Time       `gorm:"default:now()"`
+}
+
+// TableName specifies the table name for DailySpend
+func (DailySpend) TableName() string {
+	return "ai_usage_spend_daily"
+}
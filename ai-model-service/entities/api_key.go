@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// APIKeyStatus represents the status of a virtual API key
+type APIKeyStatus string
+
+const (
+	APIKeyStatusActive  APIKeyStatus = "active"
+	APIKeyStatusRevoked APIKeyStatus = "revoked"
+)
+
+// APIKey represents a virtual API key issued to a proxy caller.
+// Only the SHA-256 hash of the key is stored; the plaintext is returned once on creation.
+type APIKey struct {
+	ID            string
+	Name          string
+	Prefix        string
+	KeyHash       string
+	TenantID      string
+	ProjectID     string
+	UserID        string
+	AllowedModels []string
+	ExpiresAt     *time.Time
+	BudgetUSD     decimal.Decimal
+	SpentUSD      decimal.Decimal
+	Status        APIKeyStatus
+	LastUsedAt    *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// CallerIdentity is the identity attached to a request authenticated with a virtual API key
+type CallerIdentity struct {
+	APIKeyID      string
+	TenantID      string
+	ProjectID     string
+	UserID        string
+	AllowedModels []string
+}
+
+// CreateAPIKeyPayload represents the payload for issuing a virtual API key
+type CreateAPIKeyPayload struct {
+	Name          string
+	TenantID      string
+	ProjectID     string
+	UserID        string
+	AllowedModels []string
+	ExpiresAt     *time.Time
+	BudgetUSD     decimal.Decimal
+}
+
+// APIKeyFilter represents filter criteria for listing virtual API keys
+type APIKeyFilter struct {
+	TenantID string
+	UserID   string
+	Status   APIKeyStatus
+}
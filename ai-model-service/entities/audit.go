@@ -0,0 +1,105 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// AuditAction names what an audit event records
+type AuditAction string
+
+const (
+	AuditActionModelCreated      AuditAction = "model.created"
+	AuditActionModelUpdated      AuditAction = "model.updated"
+	AuditActionModelDeleted      AuditAction = "model.deleted"
+	AuditActionModelRestored     AuditAction = "model.restored"
+	AuditActionModelPurged       AuditAction = "model.purged"
+	AuditActionPricingSet        AuditAction = "model.pricing_set"
+	AuditActionCredentialsRead   AuditAction = "credentials.read"
+	AuditActionCredentialsLeased AuditAction = "credentials.leased"
+	AuditActionModelAliasSet     AuditAction = "model_alias.set"
+	AuditActionModelAliasDeleted AuditAction = "model_alias.deleted"
+)
+
+// Audited resource types
+const (
+	AuditResourceModel      = "model"
+	AuditResourceModelAlias = "model_alias"
+)
+
+// AuditRedacted replaces secret values in audit changes
+const AuditRedacted = "[redacted]"
+
+// Actor is who made a request, as recorded in audit events
+type Actor struct {
+	// Identity is the authenticated principal, or "anonymous"
+	Identity string
+	SourceIP string
+	// Metadata carries request metadata worth keeping, e.g. the request ID and user agent
+	Metadata map[string]string
+}
+
+// AuditChange is the old and new value of one changed field
+type AuditChange struct {
+	Field    string `json:"field"`
+	OldValue string `json:"old,omitempty"`
+	NewValue string `json:"new,omitempty"`
+}
+
+// AuditEvent is an entry of the append-only audit log. Each event's Hash covers its content and
+// the previous event's hash, so a modified or removed event breaks the chain after it.
+type AuditEvent struct {
+	ID              string            `json:"id"`
+	Sequence        int64             `json:"-"`
+	TenantID        string            `json:"tenant_id,omitempty"`
+	Actor           string            `json:"actor"`
+	Action          AuditAction       `json:"action"`
+	ResourceType    string            `json:"resource_type"`
+	ResourceID      string            `json:"resource_id"`
+	Changes         []AuditChange     `json:"changes,omitempty"`
+	SourceIP        string            `json:"source_ip,omitempty"`
+	RequestMetadata map[string]string `json:"metadata,omitempty"`
+	OccurredAt      time.Time         `json:"occurred_at"`
+	PrevHash        string            `json:"-"`
+	Hash            string            `json:"-"`
+}
+
+// AuditEventFilter represents filter criteria for listing audit events
+type AuditEventFilter struct {
+	TenantID     string
+	Actor        string
+	Action       AuditAction
+	ResourceType string
+	ResourceID   string
+	StartTime    time.Time
+	EndTime      time.Time
+	Page         int32
+	PageSize     int32
+}
+
+// AuditChainVerification is the result of checking the audit log's hash chain
+type AuditChainVerification struct {
+	Verified bool
+	Checked  int64
+	// BrokenSequence is the first event whose hash does not match, when not verified
+	BrokenSequence int64
+}
+
+// ComputeHash returns the hex SHA-256 of prevHash and the event's content. OccurredAt is hashed
+// in UTC at microsecond precision, as stored by Postgres.
+func (e *AuditEvent) ComputeHash(prevHash string) (string, error) {
+	content := *e
+	content.OccurredAt = e.OccurredAt.UTC().Truncate(time.Microsecond)
+	payload, err := json.Marshal(&content)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.New()
+	sum.Write([]byte(prevHash))
+	sum.Write([]byte{'\n'})
+	sum.Write(payload)
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
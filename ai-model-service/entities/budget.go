@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BudgetScope represents what a budget limits spend for
+type BudgetScope string
+
+const (
+	BudgetScopeTenant  BudgetScope = "tenant"
+	BudgetScopeProject BudgetScope = "project"
+	BudgetScopeUser    BudgetScope = "user"
+	BudgetScopeModel   BudgetScope = "model"
+)
+
+// BudgetPeriod represents the window a budget is measured over
+type BudgetPeriod string
+
+const (
+	BudgetPeriodDaily   BudgetPeriod = "daily"
+	BudgetPeriodMonthly BudgetPeriod = "monthly"
+)
+
+// DefaultBudgetThresholds are the alert thresholds (percent of limit) used when none are configured
+var DefaultBudgetThresholds = []int32{50, 80, 100}
+
+// Budget represents a USD spend budget with alert thresholds and an optional hard stop
+type Budget struct {
+	ID         string
+	TenantID   string
+	Scope      BudgetScope
+	ScopeID    string
+	Period     BudgetPeriod
+	LimitUSD   decimal.Decimal
+	Thresholds []int32
+	WebhookURL string
+	HardStop   bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// PeriodBounds returns the start and end of the budget period containing t
+func (b *Budget) PeriodBounds(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	if b.Period == BudgetPeriodDaily {
+		start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 0, 1)
+	}
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// BudgetStatus represents the current spend against a budget
+type BudgetStatus struct {
+	Budget       *Budget
+	SpentUSD     decimal.Decimal
+	RemainingUSD decimal.Decimal
+	PercentUsed  float64
+	Exceeded     bool
+	Blocked      bool
+	PeriodStart  time.Time
+	PeriodEnd    time.Time
+}
+
+// BudgetQuery identifies the request context budgets are evaluated for
+type BudgetQuery struct {
+	TenantID  string
+	ProjectID string
+	UserID    string
+	ModelID   string
+}
+
+// BudgetAlert is the webhook payload sent when spend crosses a threshold
+type BudgetAlert struct {
+	BudgetID    string          `json:"budget_id"`
+	TenantID    string          `json:"tenant_id,omitempty"`
+	Scope       BudgetScope     `json:"scope"`
+	ScopeID     string          `json:"scope_id"`
+	Period      BudgetPeriod    `json:"period"`
+	Threshold   int32           `json:"threshold"`
+	LimitUSD    decimal.Decimal `json:"limit_usd"`
+	SpentUSD    decimal.Decimal `json:"spent_usd"`
+	HardStop    bool            `json:"hard_stop"`
+	PeriodStart time.Time       `json:"period_start"`
+	TriggeredAt time.Time       `json:"triggered_at"`
+}
+
+// CreateBudgetPayload represents the payload for creating a budget
+type CreateBudgetPayload struct {
+	TenantID   string
+	Scope      BudgetScope
+	ScopeID    string
+	Period     BudgetPeriod
+	LimitUSD   decimal.Decimal
+	Thresholds []int32
+	WebhookURL string
+	HardStop   bool
+}
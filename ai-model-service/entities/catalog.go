@@ -0,0 +1,121 @@
+package entities
+
+import "github.com/shopspring/decimal"
+
+// ModelSource records what manages a model's definition
+type ModelSource string
+
+const (
+	// ModelSourceAPI models are created and edited through the API
+	ModelSourceAPI ModelSource = "api"
+	// ModelSourceCatalog models are kept in line with the model catalog file
+	ModelSourceCatalog ModelSource = "catalog"
+)
+
+// ModelCatalog is the declarative list of global models, loaded from a YAML or JSON file.
+// Applying it creates and updates the listed models and deprecates catalog models it no longer lists.
+type ModelCatalog struct {
+	// Providers holds defaults shared by the models of each provider, keyed by provider name
+	Providers map[string]CatalogProvider `yaml:"providers" json:"providers"`
+	Models    []CatalogModel             `yaml:"models" json:"models"`
+}
+
+// CatalogProvider holds the defaults for a provider's models
+type CatalogProvider struct {
+	BaseURL string `yaml:"base_url" json:"base_url"`
+	// APIKeyEnv names the environment variable holding the key given to newly created models
+	APIKeyEnv string `yaml:"api_key_env" json:"api_key_env"`
+}
+
+// CatalogModel declares one model. Unset optional fields leave the stored value alone, and the
+// API key is only ever set when the model is created, so keys managed by operators are kept.
+type CatalogModel struct {
+	Name          string        `yaml:"name" json:"name"`
+	Provider      string        `yaml:"provider" json:"provider"`
+	ModelID       string        `yaml:"model_id" json:"model_id"`
+	BaseURL       string        `yaml:"base_url" json:"base_url"`
+	APIKeyEnv     string        `yaml:"api_key_env" json:"api_key_env"`
+	SecretBackend SecretBackend `yaml:"secret_backend" json:"secret_backend"`
+	Status        ModelStatus   `yaml:"status" json:"status"`
+	// CostPer1kTokens is the flat rate used when the model has no pricing version
+	CostPer1kTokens *decimal.Decimal `yaml:"cost_per_1k_tokens" json:"cost_per_1k_tokens"`
+	Pricing         *CatalogPricing  `yaml:"pricing" json:"pricing"`
+	Limits          CatalogLimits    `yaml:"limits" json:"limits"`
+	// Capabilities lists every feature the model supports: tools, vision, json_mode and streaming
+	Capabilities []string          `yaml:"capabilities" json:"capabilities"`
+	Fallbacks    []string          `yaml:"fallbacks" json:"fallbacks"`
+	Config       map[string]string `yaml:"config" json:"config"`
+	// Tier puts the model in a routing tier: cheap, balanced or best; "" takes it out
+	Tier *ModelTier `yaml:"tier" json:"tier"`
+}
+
+// CatalogPricing is the current price of a catalog model. A change adds a pricing version
+// effective from the time the catalog is applied.
+type CatalogPricing struct {
+	InputPer1kTokens      decimal.Decimal `yaml:"input_per_1k_tokens" json:"input_per_1k_tokens"`
+	OutputPer1kTokens     decimal.Decimal `yaml:"output_per_1k_tokens" json:"output_per_1k_tokens"`
+	CachedReadPer1kTokens decimal.Decimal `yaml:"cached_read_per_1k_tokens" json:"cached_read_per_1k_tokens"`
+	CacheWritePer1kTokens decimal.Decimal `yaml:"cache_write_per_1k_tokens" json:"cache_write_per_1k_tokens"`
+	PerImage              decimal.Decimal `yaml:"per_image" json:"per_image"`
+	PerRequest            decimal.Decimal `yaml:"per_request" json:"per_request"`
+}
+
+// Matches reports whether a stored pricing version has the same prices
+func (p *CatalogPricing) Matches(pricing *ModelPricing) bool {
+	return pricing != nil &&
+		p.InputPer1kTokens.Equal(pricing.InputPer1kTokens) &&
+		p.OutputPer1kTokens.Equal(pricing.OutputPer1kTokens) &&
+		p.CachedReadPer1kTokens.Equal(pricing.CachedReadPer1kTokens) &&
+		p.CacheWritePer1kTokens.Equal(pricing.CacheWritePer1kTokens) &&
+		p.PerImage.Equal(pricing.PerImage) &&
+		p.PerRequest.Equal(pricing.PerRequest)
+}
+
+// CatalogLimits are the quotas and token limits of a catalog model; nil leaves the stored value alone
+type CatalogLimits struct {
+	QuotaDaily      *int64 `yaml:"quota_daily" json:"quota_daily"`
+	QuotaMonthly    *int64 `yaml:"quota_monthly" json:"quota_monthly"`
+	ContextWindow   *int32 `yaml:"context_window" json:"context_window"`
+	MaxOutputTokens *int32 `yaml:"max_output_tokens" json:"max_output_tokens"`
+}
+
+// CatalogAction is what applying the catalog does to one model
+type CatalogAction string
+
+const (
+	CatalogActionCreate    CatalogAction = "create"
+	CatalogActionUpdate    CatalogAction = "update"
+	CatalogActionDeprecate CatalogAction = "deprecate"
+	CatalogActionUnchanged CatalogAction = "unchanged"
+	// CatalogActionSkip marks an entry that cannot be applied, e.g. its name is taken by a tenant's model
+	CatalogActionSkip CatalogAction = "skip"
+)
+
+// CatalogChange is the planned change to one model
+type CatalogChange struct {
+	Action    CatalogAction
+	ModelName string
+	// ModelID is empty for models still to be created
+	ModelID string
+	Changes []AuditChange
+	// Pricing is set when a new pricing version will be added
+	Pricing *CatalogPricing
+	// Warning explains a skipped entry or a model created without an API key
+	Warning string
+}
+
+// CatalogPlan is the diff between the catalog and the stored models
+type CatalogPlan struct {
+	Changes []*CatalogChange
+}
+
+// Count returns how many models the plan applies action to
+func (p *CatalogPlan) Count(action CatalogAction) int {
+	count := 0
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,30 @@
+package entities
+
+import "time"
+
+// CredentialLease is a short-lived grant to call a model's provider through the egress,
+// bound to one pool key and to the proxy it was issued to. It never carries the key itself.
+type CredentialLease struct {
+	ID        string    `json:"lid"`
+	ModelID   string    `json:"mid"`
+	KeyID     string    `json:"kid,omitempty"`
+	Identity  string    `json:"sub"`
+	ExpiresAt time.Time `json:"exp"`
+}
+
+// IssuedCredentialLease is a signed lease and the egress URL the proxy uses in place of the
+// provider's base URL
+type IssuedCredentialLease struct {
+	Token     string
+	BaseURL   string
+	KeyID     string
+	ExpiresAt time.Time
+}
+
+// EgressTarget is where the egress forwards a leased request and the key it injects
+type EgressTarget struct {
+	Provider string
+	BaseURL  string
+	APIKey   string
+	Headers  map[string]string
+}
@@ -0,0 +1,50 @@
+package entities
+
+import "time"
+
+// ModelDeprecation describes the retirement of a deprecated model. Until the sunset the model
+// keeps serving and callers are warned; from then on the proxy sends them to the replacement.
+type ModelDeprecation struct {
+	AnnouncedAt time.Time
+	// SunsetAt is when the model stops serving; nil keeps it serving with warnings
+	SunsetAt *time.Time
+	// ReplacementID is the model callers are redirected to after the sunset
+	ReplacementID string
+}
+
+// Sunset reports whether the model has reached its sunset at t
+func (d *ModelDeprecation) Sunset(t time.Time) bool {
+	return d.SunsetAt != nil && !t.Before(*d.SunsetAt)
+}
+
+// DeprecateModelPayload represents the payload for deprecating a model
+type DeprecateModelPayload struct {
+	ModelID string
+	// AnnouncedAt defaults to now
+	AnnouncedAt   time.Time
+	SunsetAt      *time.Time
+	ReplacementID string
+}
+
+// DeprecatedModelCallerFilter selects the usage a deprecated model callers report covers
+type DeprecatedModelCallerFilter struct {
+	TenantID  string
+	ModelID   string
+	StartTime time.Time
+}
+
+// DeprecatedModelCaller is a caller that still uses a deprecated model: its calls since the
+// report's start time, including those redirected to the replacement after the sunset
+type DeprecatedModelCaller struct {
+	ModelID         string
+	ModelName       string
+	SunsetAt        *time.Time
+	ReplacementID   string
+	TenantID        string
+	ProjectID       string
+	UserID          string
+	APIKeyID        string
+	RequestCount    int64
+	RedirectedCount int64
+	LastCalledAt    time.Time
+}
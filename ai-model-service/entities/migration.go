@@ -0,0 +1,31 @@
+package entities
+
+// Migration is one versioned schema change
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationState is one migration and whether it is applied
+type MigrationState struct {
+	Version int64
+	Name    string
+	Applied bool
+}
+
+// MigrationStatus reports the database schema version against the embedded migrations
+type MigrationStatus struct {
+	// Current is the applied version, 0 for an empty database
+	Current int64
+	// Dirty means a migration failed part-way under another tool and must be fixed by hand
+	Dirty      bool
+	Latest     int64
+	Migrations []MigrationState
+}
+
+// UpToDate reports whether every migration is applied
+func (s *MigrationStatus) UpToDate() bool {
+	return !s.Dirty && s.Current == s.Latest
+}
@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"slices"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -15,21 +16,83 @@ const (
 	ModelStatusDeprecated ModelStatus = "deprecated"
 )
 
+// Valid reports whether s is a known model status
+func (s ModelStatus) Valid() bool {
+	switch s {
+	case ModelStatusActive, ModelStatusDisabled, ModelStatusDeprecated:
+		return true
+	}
+	return false
+}
+
+// ModelTier is a capability tier callers can ask the proxy for instead of a model; the proxy
+// picks one of the tier's models by price, latency, health and remaining quota
+type ModelTier string
+
+const (
+	// ModelTierNone keeps a model out of tier routing
+	ModelTierNone     ModelTier = ""
+	ModelTierCheap    ModelTier = "cheap"
+	ModelTierBalanced ModelTier = "balanced"
+	ModelTierBest     ModelTier = "best"
+)
+
+// Valid reports whether t is a known tier or ModelTierNone
+func (t ModelTier) Valid() bool {
+	switch t {
+	case ModelTierNone, ModelTierCheap, ModelTierBalanced, ModelTierBest:
+		return true
+	}
+	return false
+}
+
+// ModelCapabilities are the request features a model supports
+type ModelCapabilities struct {
+	Tools     bool
+	Vision    bool
+	JSONMode  bool
+	Streaming bool
+}
+
+// ModelLimits are a model's token limits; 0 means the limit is unknown and not enforced
+type ModelLimits struct {
+	// ContextWindow is the most prompt plus output tokens a request may use
+	ContextWindow   int32
+	MaxOutputTokens int32
+}
+
 // AIModel represents an AI model configuration
 type AIModel struct {
 	ID              string
+	TenantID        string
 	Name            string
 	Provider        string
 	ModelID         string
 	BaseURL         string
 	EncryptedAPIKey string
+	SecretBackend   SecretBackend
 	Config          map[string]string
 	QuotaDaily      int64
 	QuotaMonthly    int64
 	CostPer1kTokens decimal.Decimal
 	Status          ModelStatus
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	Source          ModelSource
+	Capabilities    ModelCapabilities
+	Limits          ModelLimits
+	// FallbackModels are tried in order when a request needs more than this model supports
+	FallbackModels []string
+	Tier           ModelTier
+	// Alias is the alias the model was looked up by, if any. The proxy splits traffic
+	// between the model and the alias's routes.
+	Alias *ModelAlias
+	// Deprecation is set while the model is deprecated
+	Deprecation *ModelDeprecation
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	// DeletedAt is set once the model is deleted; it can be restored until it is purged
+	DeletedAt *time.Time
+	// Version is incremented by every change to the model, for compare-and-swap updates
+	Version int64
 }
 
 // UsageStatus represents the status of a usage log
@@ -43,21 +106,31 @@ const (
 
 // UsageLog represents an AI usage log entry
 type UsageLog struct {
-	ID           string
-	ModelID      string
-	UserID       string
-	SessionID    string
-	PromptHash   string
-	TokensUsed   int64
-	Cost         decimal.Decimal
-	LatencyMs    int32
-	Status       UsageStatus
-	ErrorMessage string
-	CreatedAt    time.Time
+	ID               string
+	ModelID          string
+	TenantID         string
+	ProjectID        string
+	UserID           string
+	SessionID        string
+	APIKeyID         string
+	PromptHash       string
+	TokensUsed       int64
+	PromptTokens     int64
+	CompletionTokens int64
+	CachedReadTokens int64
+	CacheWriteTokens int64
+	ImageCount       int64
+	PricingID        string
+	Cost             decimal.Decimal
+	LatencyMs        int32
+	Status           UsageStatus
+	ErrorMessage     string
+	CreatedAt        time.Time
 }
 
 // Credentials represents API credentials from Vault
 type Credentials struct {
+	KeyID   string
 	APIKey  string
 	BaseURL string
 	Headers map[string]string
@@ -65,53 +138,159 @@ type Credentials struct {
 
 // QuotaStatus represents quota usage information
 type QuotaStatus struct {
-	Exceeded     bool
-	DailyUsed    int64
-	DailyLimit   int64
-	MonthlyUsed  int64
-	MonthlyLimit int64
-	ResetTime    string
+	Exceeded           bool
+	ExceededScope      string
+	DailyUsed          int64
+	DailyLimit         int64
+	MonthlyUsed        int64
+	MonthlyLimit       int64
+	TenantDailyUsed    int64
+	TenantDailyLimit   int64
+	TenantMonthlyUsed  int64
+	TenantMonthlyLimit int64
+	ResetTime          string
+}
+
+// Remaining returns the fewest tokens left under the model's and tenant's daily and monthly
+// quotas, or -1 when none of them is limited
+func (s *QuotaStatus) Remaining() int64 {
+	remaining := int64(-1)
+	for _, quota := range [][2]int64{
+		{s.DailyLimit, s.DailyUsed},
+		{s.MonthlyLimit, s.MonthlyUsed},
+		{s.TenantDailyLimit, s.TenantDailyUsed},
+		{s.TenantMonthlyLimit, s.TenantMonthlyUsed},
+	} {
+		if quota[0] <= 0 {
+			continue
+		}
+		left := max(quota[0]-quota[1], 0)
+		if remaining < 0 || left < remaining {
+			remaining = left
+		}
+	}
+	return remaining
 }
 
+// Quota scopes reported in QuotaStatus.ExceededScope
+const (
+	QuotaScopeModel  = "model"
+	QuotaScopeTenant = "tenant"
+)
+
 // CreateModelPayload represents the payload for creating a model
 type CreateModelPayload struct {
+	ID              string // assigned by the usecase so secrets can be stored under the model
+	TenantID        string
 	Name            string
 	Provider        string
 	ModelID         string
 	BaseURL         string
 	APIKey          string
+	SecretBackend   SecretBackend
 	Config          map[string]string
 	QuotaDaily      int64
 	QuotaMonthly    int64
 	CostPer1kTokens decimal.Decimal
+	Status          ModelStatus
+	Source          ModelSource
+	Capabilities    ModelCapabilities
+	Limits          ModelLimits
+	FallbackModels  []string
+	Tier            ModelTier
 }
 
-// UpdateModelPayload represents the payload for updating a model
+// Model fields an update can write, as named in its field mask
+const (
+	ModelFieldName            = "name"
+	ModelFieldProvider        = "provider"
+	ModelFieldModelID         = "model_id"
+	ModelFieldBaseURL         = "base_url"
+	ModelFieldAPIKey          = "api_key"
+	ModelFieldConfig          = "config"
+	ModelFieldQuotaDaily      = "quota_daily"
+	ModelFieldQuotaMonthly    = "quota_monthly"
+	ModelFieldCostPer1kTokens = "cost_per_1k_tokens"
+	ModelFieldStatus          = "status"
+	ModelFieldCapabilities    = "capabilities"
+	ModelFieldLimits          = "limits"
+	ModelFieldFallbackModels  = "fallback_models"
+	ModelFieldTier            = "tier"
+)
+
+// ModelUpdateFields lists every field an update can write
+var ModelUpdateFields = []string{
+	ModelFieldName, ModelFieldProvider, ModelFieldModelID, ModelFieldBaseURL, ModelFieldAPIKey,
+	ModelFieldConfig, ModelFieldQuotaDaily, ModelFieldQuotaMonthly, ModelFieldCostPer1kTokens,
+	ModelFieldStatus, ModelFieldCapabilities, ModelFieldLimits, ModelFieldFallbackModels, ModelFieldTier,
+}
+
+// UpdateModelPayload represents the payload for updating a model. Only the fields named in
+// Fields are written, zero values included: a quota of 0 removes it, an empty config clears it
+// and ModelTierNone takes the model out of tier routing.
 type UpdateModelPayload struct {
-	ID           string
-	Name         string
-	Config       map[string]string
-	QuotaDaily   int64
-	QuotaMonthly int64
-	Status       ModelStatus
+	ID              string
+	Name            string
+	Provider        string
+	ModelID         string
+	BaseURL         string
+	APIKey          string
+	Config          map[string]string
+	QuotaDaily      int64
+	QuotaMonthly    int64
+	CostPer1kTokens decimal.Decimal
+	Status          ModelStatus
+	Capabilities    ModelCapabilities
+	Limits          ModelLimits
+	FallbackModels  []string
+	Tier            ModelTier
+	Fields          []string
+	// Masked is set when Fields came from an update mask rather than from the fields that were set
+	Masked bool
+	// Version is the version of the model the update was based on. The update is refused with a
+	// conflict if the model changed since. It may only be 0 for unmasked updates that keep the key.
+	Version int64
+}
+
+// Updates reports whether the update writes field
+func (p *UpdateModelPayload) Updates(field string) bool {
+	return slices.Contains(p.Fields, field)
 }
 
 // LogUsagePayload represents the payload for logging usage
 type LogUsagePayload struct {
-	ModelID      string
-	UserID       string
-	SessionID    string
-	PromptHash   string
-	TokensUsed   int64
-	LatencyMs    int32
-	Status       UsageStatus
-	ErrorMessage string
+	ModelID          string
+	TenantID         string
+	ProjectID        string
+	UserID           string
+	SessionID        string
+	APIKeyID         string
+	Alias            string
+	RequestedModelID string
+	PromptHash       string
+	TokensUsed       int64
+	PromptTokens     int64
+	CompletionTokens int64
+	CachedReadTokens int64
+	CacheWriteTokens int64
+	ImageCount       int64
+	LatencyMs        int32
+	Status           UsageStatus
+	ErrorMessage     string
+	EventID          string
+	OccurredAt       *time.Time
 }
 
 // ModelFilter represents filter criteria for listing models
 type ModelFilter struct {
+	TenantID string
 	Provider string
 	Status   ModelStatus
+	Tier     ModelTier
 	Page     int32
 	PageSize int32
+	// IncludeDeleted lists deleted models too
+	IncludeDeleted bool
+	// ReplacementID lists the deprecated models the given model replaces
+	ReplacementID string
 }
@@ -0,0 +1,64 @@
+package entities
+
+import "time"
+
+// ModelAlias is a stable name, e.g. "default-chat" or "claude-sonnet-latest", that resolves to a
+// concrete model. Callers use the alias and operators re-point it without a client change.
+type ModelAlias struct {
+	ID string
+	// Name shares the namespace of model names, so an alias never shadows a model
+	Name string
+	// TenantID is empty for a global alias, which may only point to global models
+	TenantID string
+	// ModelID is the model that serves the traffic the routes leave over
+	ModelID   string
+	ModelName string
+	// Routes send a share of the alias's traffic to other models, e.g. for a canary rollout
+	Routes    []ModelAliasRoute
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ModelAliasRoute sends Weight percent of an alias's traffic to a model. Callers are assigned
+// to a route by a stable hash of their user or session, so each keeps seeing the same model.
+type ModelAliasRoute struct {
+	ModelID   string
+	ModelName string
+	Weight    int32
+}
+
+// ModelAliasRevision records one change of the model an alias points to. An empty
+// PreviousModelID marks the alias's creation and an empty ModelID its deletion.
+type ModelAliasRevision struct {
+	ID              string
+	AliasName       string
+	TenantID        string
+	PreviousModelID string
+	ModelID         string
+	Routes          []ModelAliasRoute
+	Actor           string
+	CreatedAt       time.Time
+}
+
+// SetModelAliasPayload creates an alias or re-points an existing one
+type SetModelAliasPayload struct {
+	Name     string
+	TenantID string
+	// ModelID is the ID or name of the target model
+	ModelID string
+	// Routes replace the alias's weighted routes; their model IDs may also be names.
+	// The weights add up to at most 100 and the rest of the traffic goes to ModelID.
+	Routes []ModelAliasRoute
+	// PreviousModelID, when set, makes the change conditional: it fails with a conflict unless
+	// the alias still points to this model
+	PreviousModelID string
+	// Actor is recorded in the alias history
+	Actor string
+}
+
+// ModelAliasFilter selects aliases
+type ModelAliasFilter struct {
+	TenantID string
+	// ModelID selects the aliases pointing or routing traffic to a model
+	ModelID string
+}
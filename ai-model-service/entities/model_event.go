@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// ModelEventType describes what changed about a model
+type ModelEventType string
+
+const (
+	ModelEventUpdated            ModelEventType = "updated"
+	ModelEventDeleted            ModelEventType = "deleted"
+	ModelEventCredentialsChanged ModelEventType = "credentials_changed"
+	// ModelEventAliasChanged announces an alias that was re-pointed or deleted. ModelName is
+	// the alias, so entries cached under it are dropped, and ModelID the model it now points to.
+	ModelEventAliasChanged ModelEventType = "alias_changed"
+)
+
+// ModelEvent announces a model change so callers caching the model or its credentials can drop them
+type ModelEvent struct {
+	Type       ModelEventType `json:"type"`
+	ModelID    string         `json:"model_id"`
+	ModelName  string         `json:"model_name"`
+	TenantID   string         `json:"tenant_id,omitempty"`
+	OccurredAt time.Time      `json:"occurred_at"`
+}
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ModelPricing represents one effective-dated price version for a model.
+// Versions are immutable; a price change adds a new version so historical costs stay correct.
+type ModelPricing struct {
+	ID                    string
+	ModelID               string
+	InputPer1kTokens      decimal.Decimal
+	OutputPer1kTokens     decimal.Decimal
+	CachedReadPer1kTokens decimal.Decimal
+	CacheWritePer1kTokens decimal.Decimal
+	PerImage              decimal.Decimal
+	PerRequest            decimal.Decimal
+	EffectiveFrom         time.Time
+	CreatedAt             time.Time
+}
+
+// Cost computes the price of a single request under this pricing version
+func (p *ModelPricing) Cost(usage *LogUsagePayload) decimal.Decimal {
+	thousand := decimal.NewFromInt(1000)
+
+	cost := p.InputPer1kTokens.Mul(decimal.NewFromInt(usage.PromptTokens)).
+		Add(p.OutputPer1kTokens.Mul(decimal.NewFromInt(usage.CompletionTokens))).
+		Add(p.CachedReadPer1kTokens.Mul(decimal.NewFromInt(usage.CachedReadTokens))).
+		Add(p.CacheWritePer1kTokens.Mul(decimal.NewFromInt(usage.CacheWriteTokens))).
+		Div(thousand)
+
+	return cost.
+		Add(p.PerImage.Mul(decimal.NewFromInt(usage.ImageCount))).
+		Add(p.PerRequest)
+}
+
+// SetModelPricingPayload represents the payload for adding a pricing version
+type SetModelPricingPayload struct {
+	ModelID               string
+	InputPer1kTokens      decimal.Decimal
+	OutputPer1kTokens     decimal.Decimal
+	CachedReadPer1kTokens decimal.Decimal
+	CacheWritePer1kTokens decimal.Decimal
+	PerImage              decimal.Decimal
+	PerRequest            decimal.Decimal
+	EffectiveFrom         *time.Time
+}
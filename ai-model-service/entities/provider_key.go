@@ -0,0 +1,74 @@
+package entities
+
+import "time"
+
+// ProviderKeyStatus represents the status of a provider API key in a model's key pool
+type ProviderKeyStatus string
+
+const (
+	ProviderKeyStatusActive   ProviderKeyStatus = "active"
+	ProviderKeyStatusDisabled ProviderKeyStatus = "disabled"
+	ProviderKeyStatusRevoked  ProviderKeyStatus = "revoked"
+)
+
+// ProviderKey is one of the upstream provider API keys a model can be served with.
+// Only the encrypted key is stored; KeyHint keeps the last characters for identification.
+type ProviderKey struct {
+	ID                string
+	ModelID           string
+	Label             string
+	KeyHint           string
+	EncryptedKey      string
+	Weight            int32
+	Status            ProviderKeyStatus
+	QuarantinedUntil  *time.Time
+	FailureCount      int32
+	LastFailureAt     *time.Time
+	LastRateLimitedAt *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// IsAvailable reports whether the key can be handed out at the given time
+func (k *ProviderKey) IsAvailable(now time.Time) bool {
+	if k.Status != ProviderKeyStatusActive {
+		return false
+	}
+	return k.QuarantinedUntil == nil || !k.QuarantinedUntil.After(now)
+}
+
+// providerKeyHintLength is the number of trailing characters kept to identify a key
+const providerKeyHintLength = 4
+
+// ProviderKeyHint returns the identifying suffix stored alongside an encrypted key
+func ProviderKeyHint(apiKey string) string {
+	if len(apiKey) <= providerKeyHintLength*2 {
+		return ""
+	}
+	return "..." + apiKey[len(apiKey)-providerKeyHintLength:]
+}
+
+// AddProviderKeyPayload represents the payload for adding a key to a model's pool
+type AddProviderKeyPayload struct {
+	ModelID string
+	Label   string
+	APIKey  string
+	Weight  int32
+}
+
+// UpdateProviderKeyPayload represents the payload for reweighting, enabling or disabling a key.
+// Zero values leave the field unchanged.
+type UpdateProviderKeyPayload struct {
+	ID      string
+	ModelID string
+	Weight  int32
+	Status  ProviderKeyStatus
+}
+
+// ProviderKeyFailure reports an upstream rejection of a key handed out by GetCredentials
+type ProviderKeyFailure struct {
+	ModelID    string
+	KeyID      string
+	StatusCode int32
+	RetryAfter time.Duration
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "github.com/shopspring/decimal"
+
+// RoutingCandidate is a model of a tier the proxy may route a request to, with its current price
+// and the quota left for the caller
+type RoutingCandidate struct {
+	Model             *AIModel
+	InputPer1kTokens  decimal.Decimal
+	OutputPer1kTokens decimal.Decimal
+	// QuotaRemaining is the fewest tokens left under any quota applying to the caller, -1 when unlimited
+	QuotaRemaining int64
+}
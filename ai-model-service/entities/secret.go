@@ -0,0 +1,50 @@
+package entities
+
+// SecretBackend identifies where a model's provider API keys are kept
+type SecretBackend string
+
+const (
+	// SecretBackendLocal stores keys in the database with envelope encryption
+	SecretBackendLocal SecretBackend = "local"
+	// SecretBackendVaultKV stores keys in Vault KV v2 under a per-model path
+	SecretBackendVaultKV SecretBackend = "vault_kv"
+	// SecretBackendVaultTransit stores Vault transit ciphertexts in the database
+	SecretBackendVaultTransit SecretBackend = "vault_transit"
+)
+
+// EncryptedSecretKind identifies which table an encrypted secret is stored in
+type EncryptedSecretKind string
+
+const (
+	EncryptedSecretModelKey    EncryptedSecretKind = "model_key"
+	EncryptedSecretProviderKey EncryptedSecretKind = "provider_key"
+)
+
+// EncryptedSecret is a provider API key ciphertext stored at rest
+type EncryptedSecret struct {
+	Kind       EncryptedSecretKind
+	ID         string
+	ModelID    string
+	Ciphertext string
+}
+
+// SecretReplacement is the value a secret is stored as in another backend
+type SecretReplacement struct {
+	Secret *EncryptedSecret
+	Stored string
+}
+
+// SecretMigrationResult summarises a secret backend migration
+type SecretMigrationResult struct {
+	Models  int
+	Secrets int
+	Failed  int
+}
+
+// RekeyResult summarises a rekey run
+type RekeyResult struct {
+	Scanned int
+	Rekeyed int
+	Current int
+	Failed  int
+}
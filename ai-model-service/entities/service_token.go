@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"slices"
+	"time"
+)
+
+// ServiceRole grants access to a class of RPCs
+type ServiceRole string
+
+const (
+	// ServiceRoleInternal may call the internal RPCs used by proxies (credentials, usage, quota)
+	ServiceRoleInternal ServiceRole = "internal"
+	// ServiceRoleAdmin may create, update and delete models, their pricing and key pools
+	ServiceRoleAdmin ServiceRole = "admin"
+)
+
+// ServiceToken are the claims of a signed service token. A token bound to a tenant is only
+// accepted on requests scoped to that tenant.
+type ServiceToken struct {
+	Subject   string        `json:"sub"`
+	Roles     []ServiceRole `json:"roles"`
+	TenantID  string        `json:"tid,omitempty"`
+	ExpiresAt time.Time     `json:"exp"`
+}
+
+// Principal is an authenticated caller: a verified mTLS identity or the subject of a service token
+type Principal struct {
+	Identity string
+	Roles    []ServiceRole
+	TenantID string
+}
+
+// HasRole reports whether the principal was granted role
+func (p *Principal) HasRole(role ServiceRole) bool {
+	return p != nil && slices.Contains(p.Roles, role)
+}
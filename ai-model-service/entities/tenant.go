@@ -0,0 +1,72 @@
+package entities
+
+import "time"
+
+// TenantStatus represents the status of a tenant
+type TenantStatus string
+
+const (
+	TenantStatusActive    TenantStatus = "active"
+	TenantStatusSuspended TenantStatus = "suspended"
+)
+
+// Tenant represents an organization using the AI platform
+type Tenant struct {
+	ID           string
+	Name         string
+	QuotaDaily   int64
+	QuotaMonthly int64
+	Status       TenantStatus
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Project represents a project that groups usage within a tenant
+type Project struct {
+	ID        string
+	TenantID  string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TenantScope identifies the tenant and project a request is made on behalf of
+type TenantScope struct {
+	TenantID  string
+	ProjectID string
+	// Platform is set for callers that are not restricted to a tenant: admins and internal
+	// services acting without a tenant, and background jobs
+	Platform bool
+	// Actor is who made the request, recorded in audit events
+	Actor *Actor
+}
+
+// IsPlatform reports whether the scope is not restricted to a tenant
+func (s *TenantScope) IsPlatform() bool {
+	return s != nil && s.Platform
+}
+
+// CanAccessTenant reports whether the scope may see resources owned by tenantID.
+// Global resources (empty tenantID) are visible to everyone.
+func (s *TenantScope) CanAccessTenant(tenantID string) bool {
+	return s.IsPlatform() || tenantID == "" || tenantID == s.TenantID
+}
+
+// CanManageTenant reports whether the scope may modify resources owned by tenantID.
+// Global resources can only be modified by platform-level callers.
+func (s *TenantScope) CanManageTenant(tenantID string) bool {
+	return s.IsPlatform() || (tenantID != "" && tenantID == s.TenantID)
+}
+
+// CreateTenantPayload represents the payload for creating a tenant
+type CreateTenantPayload struct {
+	Name         string
+	QuotaDaily   int64
+	QuotaMonthly int64
+}
+
+// CreateProjectPayload represents the payload for creating a project
+type CreateProjectPayload struct {
+	TenantID string
+	Name     string
+}
@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// UsageGroupBy represents a dimension usage statistics can be grouped by
+type UsageGroupBy string
+
+const (
+	UsageGroupByModel   UsageGroupBy = "model"
+	UsageGroupByUser    UsageGroupBy = "user"
+	UsageGroupBySession UsageGroupBy = "session"
+	UsageGroupByStatus  UsageGroupBy = "status"
+	UsageGroupByAlias   UsageGroupBy = "alias"
+)
+
+// UsageBucket represents the time bucket usage statistics are aggregated into
+type UsageBucket string
+
+const (
+	UsageBucketNone  UsageBucket = ""
+	UsageBucketHour  UsageBucket = "hour"
+	UsageBucketDay   UsageBucket = "day"
+	UsageBucketMonth UsageBucket = "month"
+)
+
+// UsageStatsFilter represents the filters and grouping of a usage statistics query
+type UsageStatsFilter struct {
+	TenantID  string
+	ModelID   string
+	UserID    string
+	SessionID string
+	Alias     string
+	Status    UsageStatus
+	StartTime time.Time
+	EndTime   time.Time
+	GroupBy   []UsageGroupBy
+	Bucket    UsageBucket
+}
+
+// UsageStats represents aggregated usage for one group and time bucket.
+// Dimensions that are not grouped by are left empty.
+type UsageStats struct {
+	ModelID          string
+	UserID           string
+	SessionID        string
+	Alias            string
+	Status           UsageStatus
+	BucketStart      *time.Time
+	RequestCount     int64
+	ErrorCount       int64
+	ErrorRate        float64
+	TokensUsed       int64
+	PromptTokens     int64
+	CompletionTokens int64
+	Cost             decimal.Decimal
+	LatencyAvgMs     float64
+	LatencyP50Ms     float64
+	LatencyP95Ms     float64
+	LatencyP99Ms     float64
+}
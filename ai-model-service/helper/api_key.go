@@ -0,0 +1,32 @@
+package helper
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// APIKeyPrefix marks plaintext virtual API keys issued by this service
+const APIKeyPrefix = "sk-vk-"
+
+// apiKeyDisplayLength is the number of leading characters kept for display
+const apiKeyDisplayLength = 12
+
+// GenerateAPIKey creates a new random virtual API key and returns the plaintext,
+// its display prefix and the SHA-256 hash stored at rest
+func GenerateAPIKey() (plaintext, prefix, hash string, err error) {
+	raw := make([]byte, 32)
+	if _, err = rand.Read(raw); err != nil {
+		return "", "", "", err
+	}
+
+	plaintext = APIKeyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	return plaintext, plaintext[:apiKeyDisplayLength], HashAPIKey(plaintext), nil
+}
+
+// HashAPIKey returns the hex-encoded SHA-256 hash of a plaintext virtual API key
+func HashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
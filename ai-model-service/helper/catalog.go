@@ -0,0 +1,25 @@
+package helper
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"go.yaml.in/yaml/v2"
+)
+
+// LoadModelCatalog reads a model catalog file. JSON is accepted as well as YAML, and unknown
+// fields are rejected so typos do not silently leave a field unmanaged.
+func LoadModelCatalog(path string) (*entities.ModelCatalog, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var catalog entities.ModelCatalog
+	if err := yaml.UnmarshalStrict(content, &catalog); err != nil {
+		return nil, fmt.Errorf(constants.ErrInvalidCatalog, err)
+	}
+	return &catalog, nil
+}
@@ -0,0 +1,71 @@
+package helper
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+)
+
+// credentialLeasePrefix marks lease tokens so they are never mistaken for provider keys
+const credentialLeasePrefix = "aml."
+
+// LeaseSigner signs and verifies credential leases with HMAC-SHA256
+type LeaseSigner struct {
+	secret []byte
+}
+
+// NewLeaseSigner creates a lease signer. The secret is only shared by AI Model Service
+// instances, since they both issue leases and redeem them at the egress.
+func NewLeaseSigner(secret string) *LeaseSigner {
+	return &LeaseSigner{secret: []byte(secret)}
+}
+
+// Sign encodes a lease as "aml.<payload>.<signature>"
+func (s *LeaseSigner) Sign(lease *entities.CredentialLease) (string, error) {
+	payload, err := json.Marshal(lease)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return credentialLeasePrefix + encoded + "." + s.signature(encoded), nil
+}
+
+// Verify checks a lease's signature and expiry and returns its claims
+func (s *LeaseSigner) Verify(token string, now time.Time) (*entities.CredentialLease, error) {
+	body, ok := strings.CutPrefix(token, credentialLeasePrefix)
+	if !ok {
+		return nil, fmt.Errorf("not a credential lease")
+	}
+	encoded, signature, ok := strings.Cut(body, ".")
+	if !ok {
+		return nil, fmt.Errorf("malformed credential lease")
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.signature(encoded))) {
+		return nil, fmt.Errorf("invalid credential lease signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("malformed credential lease: %w", err)
+	}
+	var lease entities.CredentialLease
+	if err := json.Unmarshal(payload, &lease); err != nil {
+		return nil, fmt.Errorf("malformed credential lease: %w", err)
+	}
+	if !now.Before(lease.ExpiresAt) {
+		return nil, fmt.Errorf("credential lease expired")
+	}
+	return &lease, nil
+}
+
+func (s *LeaseSigner) signature(encoded string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
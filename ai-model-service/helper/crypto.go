@@ -8,71 +8,252 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+	"sort"
+	"strconv"
+	"strings"
 )
 
+// DevSecret is the well-known master secret used when none is configured in dev mode.
+// It must never protect real provider keys.
+const DevSecret = "dev-secret-key-must-be-32-bytes-long!"
+
+// dataKeySize is the size of the per-record AES-256 data key
+const dataKeySize = 32
+
+// CryptoHelpers encrypts secrets at rest with envelope encryption.
+// Each value is sealed with a fresh data key, and the data key is wrapped with a
+// versioned master key. Ciphertexts have the form "v<version>:<wrapped key>:<data>".
 type CryptoHelpers interface {
 	Encrypt(text string) (string, error)
 	Decrypt(text string) (string, error)
+	// CurrentKeyVersion returns the master key version new ciphertexts are wrapped with
+	CurrentKeyVersion() int
+	// KeyVersion returns the master key version of a ciphertext, or 0 for pre-envelope ciphertexts
+	KeyVersion(text string) int
+}
+
+// MasterKey is a versioned key-encryption key
+type MasterKey struct {
+	Version int
+	Secret  string
 }
 
 type cryptoHelpers struct {
-	secretKey []byte
+	masterKeys     map[int][]byte
+	currentVersion int
 }
 
-func NewCryptoHelpers(secretKey string) CryptoHelpers {
-	// Hash the secret key to ensure it's exactly 32 bytes (SHA-256)
-	hash := sha256.Sum256([]byte(secretKey))
-	return &cryptoHelpers{
-		secretKey: hash[:],
+// NewCryptoHelpers creates the envelope encryption helper. The highest version is the current master key;
+// older versions are kept so existing ciphertexts stay readable until they are rekeyed.
+func NewCryptoHelpers(masterKeys []MasterKey) (CryptoHelpers, error) {
+	if len(masterKeys) == 0 {
+		return nil, fmt.Errorf("at least one master key is required")
 	}
+
+	c := &cryptoHelpers{masterKeys: make(map[int][]byte, len(masterKeys))}
+	for _, key := range masterKeys {
+		if key.Version <= 0 {
+			return nil, fmt.Errorf("master key version must be positive: %d", key.Version)
+		}
+		if key.Secret == "" {
+			return nil, fmt.Errorf("master key version %d is empty", key.Version)
+		}
+		if _, exists := c.masterKeys[key.Version]; exists {
+			return nil, fmt.Errorf("duplicate master key version: %d", key.Version)
+		}
+		// Hash the secret to ensure it's exactly 32 bytes (SHA-256)
+		hash := sha256.Sum256([]byte(key.Secret))
+		c.masterKeys[key.Version] = hash[:]
+		if key.Version > c.currentVersion {
+			c.currentVersion = key.Version
+		}
+	}
+	return c, nil
 }
 
-func (c *cryptoHelpers) Encrypt(text string) (string, error) {
-	block, err := aes.NewCipher(c.secretKey)
+// ParseMasterKeys parses a "<version>:<secret>,<version>:<secret>" list of master keys
+func ParseMasterKeys(spec string) ([]MasterKey, error) {
+	var keys []MasterKey
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		versionStr, secret, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("master key must be <version>:<secret>")
+		}
+		version, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(versionStr), "v"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid master key version %q", versionStr)
+		}
+		keys = append(keys, MasterKey{Version: version, Secret: secret})
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Version < keys[j].Version })
+	return keys, nil
+}
+
+// DevSecretUse reports whether the well-known dev secret is among the master keys, and whether
+// it is the current (highest) version used for new values
+func DevSecretUse(masterKeys []MasterKey) (used, current bool) {
+	currentVersion := 0
+	for _, key := range masterKeys {
+		currentVersion = max(currentVersion, key.Version)
+	}
+	for _, key := range masterKeys {
+		if key.Secret == DevSecret {
+			used = true
+			current = current || key.Version == currentVersion
+		}
+	}
+	return used, current
+}
+
+func (c *cryptoHelpers) CurrentKeyVersion() int {
+	return c.currentVersion
+}
+
+func (c *cryptoHelpers) KeyVersion(text string) int {
+	version, _, _, err := parseEnvelope(text)
 	if err != nil {
+		return 0
+	}
+	return version
+}
+
+func (c *cryptoHelpers) Encrypt(text string) (string, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
 		return "", err
 	}
 
-	gcm, err := cipher.NewGCM(block)
+	header := envelopeHeader(c.currentVersion)
+	wrappedKey, err := seal(c.masterKeys[c.currentVersion], dataKey, []byte(header))
 	if err != nil {
 		return "", err
 	}
-
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+	data, err := seal(dataKey, []byte(text), []byte(header))
+	if err != nil {
 		return "", err
 	}
 
-	ciphertext := gcm.Seal(nonce, nonce, []byte(text), nil)
-	return base64.StdEncoding.EncodeToString(ciphertext), nil
+	return header + base64.StdEncoding.EncodeToString(wrappedKey) + ":" + base64.StdEncoding.EncodeToString(data), nil
 }
 
 func (c *cryptoHelpers) Decrypt(text string) (string, error) {
-	ciphertext, err := base64.StdEncoding.DecodeString(text)
+	// Legacy ciphertexts are plain base64, which never contains ':'
+	if !strings.Contains(text, ":") {
+		return c.decryptLegacy(text)
+	}
+
+	version, wrappedKey, data, err := parseEnvelope(text)
 	if err != nil {
 		return "", err
 	}
+	masterKey, ok := c.masterKeys[version]
+	if !ok {
+		return "", fmt.Errorf("master key version %d is not configured", version)
+	}
 
-	block, err := aes.NewCipher(c.secretKey)
+	header := envelopeHeader(version)
+	dataKey, err := open(masterKey, wrappedKey, []byte(header))
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	plaintext, err := open(dataKey, data, []byte(header))
 	if err != nil {
 		return "", err
 	}
 
-	gcm, err := cipher.NewGCM(block)
+	return string(plaintext), nil
+}
+
+// decryptLegacy reads ciphertexts written before envelope encryption, which were sealed
+// directly with a master key and carry no version header
+func (c *cryptoHelpers) decryptLegacy(text string) (string, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(text)
 	if err != nil {
 		return "", err
 	}
 
+	versions := make([]int, 0, len(c.masterKeys))
+	for version := range c.masterKeys {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+
+	lastErr := fmt.Errorf("no master keys configured")
+	for _, version := range versions {
+		plaintext, err := open(c.masterKeys[version], ciphertext, nil)
+		if err == nil {
+			return string(plaintext), nil
+		}
+		lastErr = err
+	}
+	return "", lastErr
+}
+
+func envelopeHeader(version int) string {
+	return "v" + strconv.Itoa(version) + ":"
+}
+
+// parseEnvelope splits "v<version>:<wrapped key>:<data>" into its decoded parts
+func parseEnvelope(text string) (int, []byte, []byte, error) {
+	parts := strings.Split(text, ":")
+	if len(parts) != 3 || !strings.HasPrefix(parts[0], "v") {
+		return 0, nil, nil, fmt.Errorf("malformed ciphertext")
+	}
+	version, err := strconv.Atoi(parts[0][1:])
+	if err != nil || version <= 0 {
+		return 0, nil, nil, fmt.Errorf("malformed ciphertext key version")
+	}
+	wrappedKey, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return version, wrappedKey, data, nil
+}
+
+// seal encrypts plaintext with AES-GCM, prefixing the random nonce
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open decrypts a nonce-prefixed AES-GCM ciphertext
+func open(key, ciphertext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
 	nonceSize := gcm.NonceSize()
 	if len(ciphertext) < nonceSize {
-		return "", fmt.Errorf("ciphertext too short")
+		return nil, fmt.Errorf("ciphertext too short")
 	}
 
 	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	return gcm.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-
-	return string(plaintext), nil
+	return cipher.NewGCM(block)
 }
@@ -0,0 +1,88 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+)
+
+// SecretStore keeps provider API keys for a secret backend. The value returned by Put is
+// what the database stores (a ciphertext or a reference) and is handed back to Get and Delete.
+type SecretStore interface {
+	Put(ctx context.Context, modelID, apiKey string) (string, errors.BaseError)
+	Get(ctx context.Context, stored string) (string, errors.BaseError)
+	// Delete removes the key from the backend; a no-op for backends that keep it in the database
+	Delete(ctx context.Context, stored string) errors.BaseError
+}
+
+// SecretStores resolves the secret store for each configured backend
+type SecretStores struct {
+	stores         map[entities.SecretBackend]SecretStore
+	defaultBackend entities.SecretBackend
+}
+
+// NewSecretStores creates the backend registry. defaultBackend applies to models created
+// without an explicit backend and must be one of the registered stores.
+func NewSecretStores(defaultBackend entities.SecretBackend, stores map[entities.SecretBackend]SecretStore) (*SecretStores, error) {
+	if _, ok := stores[defaultBackend]; !ok {
+		return nil, fmt.Errorf(constants.ErrSecretBackendUnavailable, defaultBackend)
+	}
+	return &SecretStores{stores: stores, defaultBackend: defaultBackend}, nil
+}
+
+// DefaultBackend returns the backend for new models
+func (s *SecretStores) DefaultBackend() entities.SecretBackend {
+	return s.defaultBackend
+}
+
+// Store returns the store for a backend. Models created before backends existed have none
+// recorded and use the local store.
+func (s *SecretStores) Store(backend entities.SecretBackend) (SecretStore, errors.BaseError) {
+	if backend == "" {
+		backend = entities.SecretBackendLocal
+	}
+	switch backend {
+	case entities.SecretBackendLocal, entities.SecretBackendVaultKV, entities.SecretBackendVaultTransit:
+	default:
+		return nil, errors.BadRequest(fmt.Sprintf(constants.ErrUnknownSecretBackend, backend))
+	}
+
+	store, ok := s.stores[backend]
+	if !ok {
+		return nil, errors.BadRequest(fmt.Sprintf(constants.ErrSecretBackendUnavailable, backend))
+	}
+	return store, nil
+}
+
+// localSecretStore keeps keys in the database, sealed with envelope encryption
+type localSecretStore struct {
+	crypto CryptoHelpers
+}
+
+// NewLocalSecretStore creates the database-backed secret store
+func NewLocalSecretStore(crypto CryptoHelpers) SecretStore {
+	return &localSecretStore{crypto: crypto}
+}
+
+func (s *localSecretStore) Put(ctx context.Context, modelID, apiKey string) (string, errors.BaseError) {
+	ciphertext, err := s.crypto.Encrypt(apiKey)
+	if err != nil {
+		return "", errors.Internal(fmt.Errorf(constants.ErrFailedToEncryptAPIKey, err))
+	}
+	return ciphertext, nil
+}
+
+func (s *localSecretStore) Get(ctx context.Context, stored string) (string, errors.BaseError) {
+	apiKey, err := s.crypto.Decrypt(stored)
+	if err != nil {
+		return "", errors.Internal(fmt.Errorf(constants.ErrFailedToDecryptAPIKey, err))
+	}
+	return apiKey, nil
+}
+
+func (s *localSecretStore) Delete(ctx context.Context, stored string) errors.BaseError {
+	return nil
+}
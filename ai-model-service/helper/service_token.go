@@ -0,0 +1,71 @@
+package helper
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+)
+
+// serviceTokenPrefix marks service tokens so they are never mistaken for API keys or leases
+const serviceTokenPrefix = "ams."
+
+// ServiceTokenSigner signs and verifies service tokens with HMAC-SHA256
+type ServiceTokenSigner struct {
+	secret []byte
+}
+
+// NewServiceTokenSigner creates a service token signer. The secret is only known to AI Model
+// Service instances; callers are handed tokens issued with `ai-model-service token issue`.
+func NewServiceTokenSigner(secret string) *ServiceTokenSigner {
+	return &ServiceTokenSigner{secret: []byte(secret)}
+}
+
+// Sign encodes a token as "ams.<payload>.<signature>"
+func (s *ServiceTokenSigner) Sign(token *entities.ServiceToken) (string, error) {
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return serviceTokenPrefix + encoded + "." + s.signature(encoded), nil
+}
+
+// VerifyServiceToken checks a token's signature and expiry and returns its claims
+func (s *ServiceTokenSigner) VerifyServiceToken(token string, now time.Time) (*entities.ServiceToken, error) {
+	body, ok := strings.CutPrefix(token, serviceTokenPrefix)
+	if !ok {
+		return nil, fmt.Errorf("not a service token")
+	}
+	encoded, signature, ok := strings.Cut(body, ".")
+	if !ok {
+		return nil, fmt.Errorf("malformed service token")
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.signature(encoded))) {
+		return nil, fmt.Errorf("invalid service token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("malformed service token: %w", err)
+	}
+	var claims entities.ServiceToken
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("malformed service token: %w", err)
+	}
+	if !now.Before(claims.ExpiresAt) {
+		return nil, fmt.Errorf("service token expired")
+	}
+	return &claims, nil
+}
+
+func (s *ServiceTokenSigner) signature(encoded string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -2,7 +2,11 @@ package helper
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
 	"github.com/blcvn/backend/services/ai-model-service/entities"
 	pb "github.com/blcvn/kratos-proto/go/ai-model"
 	"github.com/shopspring/decimal"
@@ -24,13 +28,15 @@ func (t *Transform) Model2Pb(model *entities.AIModel) (*pb.AIModel, error) {
 
 	costFloat, _ := model.CostPer1kTokens.Float64()
 
-	return &pb.AIModel{
+	modelPb := &pb.AIModel{
 		Id:       model.ID,
+		TenantId: model.TenantID,
 		Name:     model.Name,
 		Provider: model.Provider,
 		ModelId:  model.ModelID,
 		BaseUrl:  model.BaseURL,
 		// VaultPath removed
+		SecretBackend:    string(model.SecretBackend),
 		Config:           model.Config,
 		QuotaDaily:       model.QuotaDaily,
 		QuotaMonthly:     model.QuotaMonthly,
@@ -38,7 +44,32 @@ func (t *Transform) Model2Pb(model *entities.AIModel) (*pb.AIModel, error) {
 		Status:           pb.ModelStatus(pb.ModelStatus_value[string(model.Status)]),
 		CreatedAt:        timestamppb.New(model.CreatedAt),
 		UpdatedAt:        timestamppb.New(model.UpdatedAt),
-	}, nil
+		Capabilities:     capabilities2Pb(model.Capabilities),
+		Limits: &pb.ModelLimits{
+			ContextWindow:   model.Limits.ContextWindow,
+			MaxOutputTokens: model.Limits.MaxOutputTokens,
+		},
+		FallbackModels: model.FallbackModels,
+		Tier:           string(model.Tier),
+		Version:        model.Version,
+	}
+	if model.Alias != nil {
+		modelPb.Alias = model.Alias.Name
+		modelPb.AliasRoutes = modelAliasRoutes2Pb(model.Alias.Routes)
+	}
+	if deprecation := model.Deprecation; deprecation != nil {
+		modelPb.Deprecation = &pb.ModelDeprecation{
+			AnnouncedAt:        timestamppb.New(deprecation.AnnouncedAt),
+			ReplacementModelId: deprecation.ReplacementID,
+		}
+		if deprecation.SunsetAt != nil {
+			modelPb.Deprecation.SunsetAt = timestamppb.New(*deprecation.SunsetAt)
+		}
+	}
+	if model.DeletedAt != nil {
+		modelPb.DeletedAt = timestamppb.New(*model.DeletedAt)
+	}
+	return modelPb, nil
 }
 
 // Credentials2Pb converts entity to proto
@@ -48,12 +79,42 @@ func (t *Transform) Credentials2Pb(creds *entities.Credentials) (*pb.Credentials
 	}
 
 	return &pb.Credentials{
+		KeyId:   creds.KeyID,
 		ApiKey:  creds.APIKey,
 		BaseUrl: creds.BaseURL,
 		Headers: creds.Headers,
 	}, nil
 }
 
+// CredentialLease2Pb converts entity to proto
+func (t *Transform) CredentialLease2Pb(lease *entities.IssuedCredentialLease) (*pb.CredentialLease, error) {
+	if lease == nil {
+		return nil, fmt.Errorf("credential lease is nil")
+	}
+
+	return &pb.CredentialLease{
+		Token:     lease.Token,
+		BaseUrl:   lease.BaseURL,
+		KeyId:     lease.KeyID,
+		ExpiresAt: timestamppb.New(lease.ExpiresAt),
+	}, nil
+}
+
+// ModelEvent2Pb converts entity to proto
+func (t *Transform) ModelEvent2Pb(event *entities.ModelEvent) (*pb.ModelEvent, error) {
+	if event == nil {
+		return nil, fmt.Errorf("model event is nil")
+	}
+
+	return &pb.ModelEvent{
+		Type:       string(event.Type),
+		ModelId:    event.ModelID,
+		ModelName:  event.ModelName,
+		TenantId:   event.TenantID,
+		OccurredAt: timestamppb.New(event.OccurredAt),
+	}, nil
+}
+
 // QuotaStatus2Pb converts entity to proto
 func (t *Transform) QuotaStatus2Pb(quota *entities.QuotaStatus) (*pb.QuotaStatus, error) {
 	if quota == nil {
@@ -61,12 +122,17 @@ func (t *Transform) QuotaStatus2Pb(quota *entities.QuotaStatus) (*pb.QuotaStatus
 	}
 
 	return &pb.QuotaStatus{
-		Exceeded:     quota.Exceeded,
-		DailyUsed:    quota.DailyUsed,
-		DailyLimit:   quota.DailyLimit,
-		MonthlyUsed:  quota.MonthlyUsed,
-		MonthlyLimit: quota.MonthlyLimit,
-		ResetTime:    quota.ResetTime,
+		Exceeded:           quota.Exceeded,
+		ExceededScope:      quota.ExceededScope,
+		DailyUsed:          quota.DailyUsed,
+		DailyLimit:         quota.DailyLimit,
+		MonthlyUsed:        quota.MonthlyUsed,
+		MonthlyLimit:       quota.MonthlyLimit,
+		TenantDailyUsed:    quota.TenantDailyUsed,
+		TenantDailyLimit:   quota.TenantDailyLimit,
+		TenantMonthlyUsed:  quota.TenantMonthlyUsed,
+		TenantMonthlyLimit: quota.TenantMonthlyLimit,
+		ResetTime:          quota.ResetTime,
 	}, nil
 }
 
@@ -77,15 +143,22 @@ func (t *Transform) Pb2CreateModelPayload(pb *pb.CreateModelPayload) (*entities.
 	}
 
 	return &entities.CreateModelPayload{
+		TenantID:        pb.TenantId,
 		Name:            pb.Name,
 		Provider:        pb.Provider,
 		ModelID:         pb.ModelId,
 		BaseURL:         pb.BaseUrl,
 		APIKey:          pb.ApiKey,
+		SecretBackend:   entities.SecretBackend(pb.SecretBackend),
 		Config:          pb.Config,
 		QuotaDaily:      pb.QuotaDaily,
 		QuotaMonthly:    pb.QuotaMonthly,
 		CostPer1kTokens: decimal.NewFromFloat(pb.CostPer_1KTokens),
+		// Models created without capabilities stream, like models that predate them
+		Capabilities:   valueOr(pb2Capabilities(pb.Capabilities), entities.ModelCapabilities{Streaming: true}),
+		Limits:         valueOr(pb2Limits(pb.Limits), entities.ModelLimits{}),
+		FallbackModels: pb.FallbackModels,
+		Tier:           entities.ModelTier(pb.Tier),
 	}, nil
 }
 
@@ -95,14 +168,125 @@ func (t *Transform) Pb2UpdateModelPayload(pb *pb.UpdateModelPayload) (*entities.
 		return nil, fmt.Errorf("payload is nil")
 	}
 
-	return &entities.UpdateModelPayload{
-		ID:           pb.Id,
-		Name:         pb.Name,
-		Config:       pb.Config,
-		QuotaDaily:   pb.QuotaDaily,
-		QuotaMonthly: pb.QuotaMonthly,
-		Status:       entities.ModelStatus(pb.Status.String()),
-	}, nil
+	payload := &entities.UpdateModelPayload{
+		ID:              pb.Id,
+		Name:            pb.Name,
+		Provider:        pb.Provider,
+		ModelID:         pb.ModelId,
+		BaseURL:         pb.BaseUrl,
+		APIKey:          pb.ApiKey,
+		Config:          pb.Config,
+		QuotaDaily:      pb.QuotaDaily,
+		QuotaMonthly:    pb.QuotaMonthly,
+		CostPer1kTokens: decimal.NewFromFloat(pb.CostPer_1KTokens),
+		Status:          entities.ModelStatus(pb.Status.String()),
+		FallbackModels:  pb.FallbackModels,
+		Version:         pb.Version,
+	}
+	if capabilities := pb2Capabilities(pb.Capabilities); capabilities != nil {
+		payload.Capabilities = *capabilities
+	}
+	if limits := pb2Limits(pb.Limits); limits != nil {
+		payload.Limits = *limits
+	}
+	// "none" takes the model out of tier routing
+	if pb.Tier != modelTierNone {
+		payload.Tier = entities.ModelTier(pb.Tier)
+	}
+
+	if mask := pb.UpdateMask; mask != nil && len(mask.Paths) > 0 {
+		payload.Masked = true
+		for _, path := range mask.Paths {
+			field, ok := modelUpdateField(path)
+			if !ok {
+				return nil, fmt.Errorf(constants.ErrUnknownModelField, path)
+			}
+			if !slices.Contains(payload.Fields, field) {
+				payload.Fields = append(payload.Fields, field)
+			}
+		}
+		return payload, nil
+	}
+
+	// Without a field mask only the fields that were set are written
+	set := map[string]bool{
+		entities.ModelFieldName:            pb.Name != "",
+		entities.ModelFieldProvider:        pb.Provider != "",
+		entities.ModelFieldModelID:         pb.ModelId != "",
+		entities.ModelFieldBaseURL:         pb.BaseUrl != "",
+		entities.ModelFieldAPIKey:          pb.ApiKey != "",
+		entities.ModelFieldConfig:          pb.Config != nil,
+		entities.ModelFieldQuotaDaily:      pb.QuotaDaily > 0,
+		entities.ModelFieldQuotaMonthly:    pb.QuotaMonthly > 0,
+		entities.ModelFieldCostPer1kTokens: pb.CostPer_1KTokens > 0,
+		entities.ModelFieldStatus:          payload.Status != "",
+		entities.ModelFieldCapabilities:    pb.Capabilities != nil,
+		entities.ModelFieldLimits:          pb.Limits != nil,
+		entities.ModelFieldFallbackModels:  pb.FallbackModels != nil,
+		entities.ModelFieldTier:            pb.Tier != "",
+	}
+	for _, field := range entities.ModelUpdateFields {
+		if set[field] {
+			payload.Fields = append(payload.Fields, field)
+		}
+	}
+	return payload, nil
+}
+
+// modelUpdateField returns the model field an update mask path names. Paths are matched without
+// underscores, since the JSON gateway turns "costPer1kTokens" into "cost_per1k_tokens".
+func modelUpdateField(path string) (string, bool) {
+	for _, field := range entities.ModelUpdateFields {
+		if strings.ReplaceAll(field, "_", "") == strings.ReplaceAll(path, "_", "") {
+			return field, true
+		}
+	}
+	return "", false
+}
+
+// modelTierNone is how an update clears a model's tier
+const modelTierNone = "none"
+
+// capabilities2Pb converts model capabilities to proto
+func capabilities2Pb(capabilities entities.ModelCapabilities) *pb.ModelCapabilities {
+	return &pb.ModelCapabilities{
+		Tools:     capabilities.Tools,
+		Vision:    capabilities.Vision,
+		JsonMode:  capabilities.JSONMode,
+		Streaming: capabilities.Streaming,
+	}
+}
+
+// pb2Capabilities converts proto capabilities; nil when they were not sent
+func pb2Capabilities(capabilities *pb.ModelCapabilities) *entities.ModelCapabilities {
+	if capabilities == nil {
+		return nil
+	}
+	return &entities.ModelCapabilities{
+		Tools:     capabilities.Tools,
+		Vision:    capabilities.Vision,
+		JSONMode:  capabilities.JsonMode,
+		Streaming: capabilities.Streaming,
+	}
+}
+
+// pb2Limits converts proto limits; nil when they were not sent
+func pb2Limits(limits *pb.ModelLimits) *entities.ModelLimits {
+	if limits == nil {
+		return nil
+	}
+	return &entities.ModelLimits{
+		ContextWindow:   limits.ContextWindow,
+		MaxOutputTokens: limits.MaxOutputTokens,
+	}
+}
+
+// valueOr dereferences value, or returns fallback when it is nil
+func valueOr[T any](value *T, fallback T) T {
+	if value == nil {
+		return fallback
+	}
+	return *value
 }
 
 // Pb2LogUsagePayload converts proto to entity
@@ -111,24 +295,593 @@ func (t *Transform) Pb2LogUsagePayload(pb *pb.LogUsagePayload) (*entities.LogUsa
 		return nil, fmt.Errorf("payload is nil")
 	}
 
+	var occurredAt *time.Time
+	if pb.OccurredAt != nil {
+		t := pb.OccurredAt.AsTime()
+		occurredAt = &t
+	}
+
 	return &entities.LogUsagePayload{
-		ModelID:      pb.ModelId,
-		UserID:       pb.UserId,
-		SessionID:    pb.SessionId,
-		PromptHash:   pb.PromptHash,
-		TokensUsed:   pb.TokensUsed,
-		LatencyMs:    pb.LatencyMs,
-		Status:       entities.UsageStatus(pb.Status.String()),
-		ErrorMessage: pb.ErrorMessage,
+		EventID:          pb.EventId,
+		OccurredAt:       occurredAt,
+		ModelID:          pb.ModelId,
+		TenantID:         pb.TenantId,
+		ProjectID:        pb.ProjectId,
+		UserID:           pb.UserId,
+		SessionID:        pb.SessionId,
+		Alias:            pb.Alias,
+		RequestedModelID: pb.RequestedModelId,
+		PromptHash:       pb.PromptHash,
+		TokensUsed:       pb.TokensUsed,
+		LatencyMs:        pb.LatencyMs,
+		Status:           entities.UsageStatus(pb.Status.String()),
+		ErrorMessage:     pb.ErrorMessage,
+		APIKeyID:         pb.ApiKeyId,
+		PromptTokens:     pb.PromptTokens,
+		CompletionTokens: pb.CompletionTokens,
+		CachedReadTokens: pb.CachedReadTokens,
+		CacheWriteTokens: pb.CacheWriteTokens,
+		ImageCount:       pb.ImageCount,
 	}, nil
 }
 
 // Pb2ModelFilter converts proto to entity
-func (t *Transform) Pb2ModelFilter(provider string, status pb.ModelStatus, page, pageSize int32) (*entities.ModelFilter, error) {
+func (t *Transform) Pb2ModelFilter(provider string, status pb.ModelStatus, tier string, includeDeleted bool, page, pageSize int32) (*entities.ModelFilter, error) {
 	return &entities.ModelFilter{
-		Provider: provider,
-		Status:   entities.ModelStatus(status.String()),
-		Page:     page,
-		PageSize: pageSize,
+		Provider:       provider,
+		Status:         entities.ModelStatus(status.String()),
+		Tier:           entities.ModelTier(tier),
+		Page:           page,
+		PageSize:       pageSize,
+		IncludeDeleted: includeDeleted,
+	}, nil
+}
+
+// APIKey2Pb converts entity to proto
+func (t *Transform) APIKey2Pb(key *entities.APIKey) (*pb.APIKey, error) {
+	if key == nil {
+		return nil, fmt.Errorf("api key is nil")
+	}
+
+	budgetFloat, _ := key.BudgetUSD.Float64()
+	spentFloat, _ := key.SpentUSD.Float64()
+
+	keyPb := &pb.APIKey{
+		Id:            key.ID,
+		Name:          key.Name,
+		Prefix:        key.Prefix,
+		TenantId:      key.TenantID,
+		ProjectId:     key.ProjectID,
+		UserId:        key.UserID,
+		AllowedModels: key.AllowedModels,
+		BudgetUsd:     budgetFloat,
+		SpentUsd:      spentFloat,
+		Status:        string(key.Status),
+		CreatedAt:     timestamppb.New(key.CreatedAt),
+	}
+	if key.ExpiresAt != nil {
+		keyPb.ExpiresAt = timestamppb.New(*key.ExpiresAt)
+	}
+	if key.LastUsedAt != nil {
+		keyPb.LastUsedAt = timestamppb.New(*key.LastUsedAt)
+	}
+
+	return keyPb, nil
+}
+
+// CallerIdentity2Pb converts entity to proto
+func (t *Transform) CallerIdentity2Pb(identity *entities.CallerIdentity) (*pb.CallerIdentity, error) {
+	if identity == nil {
+		return nil, fmt.Errorf("identity is nil")
+	}
+
+	return &pb.CallerIdentity{
+		ApiKeyId:      identity.APIKeyID,
+		TenantId:      identity.TenantID,
+		ProjectId:     identity.ProjectID,
+		UserId:        identity.UserID,
+		AllowedModels: identity.AllowedModels,
+	}, nil
+}
+
+// Pb2CreateAPIKeyPayload converts proto to entity
+func (t *Transform) Pb2CreateAPIKeyPayload(pb *pb.CreateAPIKeyPayload) (*entities.CreateAPIKeyPayload, error) {
+	if pb == nil {
+		return nil, fmt.Errorf("payload is nil")
+	}
+
+	payload := &entities.CreateAPIKeyPayload{
+		Name:          pb.Name,
+		TenantID:      pb.TenantId,
+		ProjectID:     pb.ProjectId,
+		UserID:        pb.UserId,
+		AllowedModels: pb.AllowedModels,
+		BudgetUSD:     decimal.NewFromFloat(pb.BudgetUsd),
+	}
+	if pb.ExpiresAt != nil {
+		expiresAt := pb.ExpiresAt.AsTime()
+		payload.ExpiresAt = &expiresAt
+	}
+
+	return payload, nil
+}
+
+// Tenant2Pb converts entity to proto
+func (t *Transform) Tenant2Pb(tenant *entities.Tenant) (*pb.Tenant, error) {
+	if tenant == nil {
+		return nil, fmt.Errorf("tenant is nil")
+	}
+
+	return &pb.Tenant{
+		Id:           tenant.ID,
+		Name:         tenant.Name,
+		QuotaDaily:   tenant.QuotaDaily,
+		QuotaMonthly: tenant.QuotaMonthly,
+		Status:       string(tenant.Status),
+		CreatedAt:    timestamppb.New(tenant.CreatedAt),
+		UpdatedAt:    timestamppb.New(tenant.UpdatedAt),
+	}, nil
+}
+
+// Project2Pb converts entity to proto
+func (t *Transform) Project2Pb(project *entities.Project) (*pb.Project, error) {
+	if project == nil {
+		return nil, fmt.Errorf("project is nil")
+	}
+
+	return &pb.Project{
+		Id:        project.ID,
+		TenantId:  project.TenantID,
+		Name:      project.Name,
+		CreatedAt: timestamppb.New(project.CreatedAt),
+		UpdatedAt: timestamppb.New(project.UpdatedAt),
+	}, nil
+}
+
+// Pb2CreateTenantPayload converts proto to entity
+func (t *Transform) Pb2CreateTenantPayload(pb *pb.CreateTenantPayload) (*entities.CreateTenantPayload, error) {
+	if pb == nil {
+		return nil, fmt.Errorf("payload is nil")
+	}
+
+	return &entities.CreateTenantPayload{
+		Name:         pb.Name,
+		QuotaDaily:   pb.QuotaDaily,
+		QuotaMonthly: pb.QuotaMonthly,
+	}, nil
+}
+
+// Pb2CreateProjectPayload converts proto to entity
+func (t *Transform) Pb2CreateProjectPayload(pb *pb.CreateProjectPayload) (*entities.CreateProjectPayload, error) {
+	if pb == nil {
+		return nil, fmt.Errorf("payload is nil")
+	}
+
+	return &entities.CreateProjectPayload{
+		TenantID: pb.TenantId,
+		Name:     pb.Name,
+	}, nil
+}
+
+// Budget2Pb converts entity to proto
+func (t *Transform) Budget2Pb(budget *entities.Budget) (*pb.Budget, error) {
+	if budget == nil {
+		return nil, fmt.Errorf("budget is nil")
+	}
+
+	limitFloat, _ := budget.LimitUSD.Float64()
+
+	return &pb.Budget{
+		Id:         budget.ID,
+		TenantId:   budget.TenantID,
+		Scope:      string(budget.Scope),
+		ScopeId:    budget.ScopeID,
+		Period:     string(budget.Period),
+		LimitUsd:   limitFloat,
+		Thresholds: budget.Thresholds,
+		WebhookUrl: budget.WebhookURL,
+		HardStop:   budget.HardStop,
+		CreatedAt:  timestamppb.New(budget.CreatedAt),
+	}, nil
+}
+
+// BudgetStatus2Pb converts entity to proto
+func (t *Transform) BudgetStatus2Pb(status *entities.BudgetStatus) (*pb.BudgetStatus, error) {
+	if status == nil {
+		return nil, fmt.Errorf("budget status is nil")
+	}
+
+	budgetPb, err := t.Budget2Pb(status.Budget)
+	if err != nil {
+		return nil, err
+	}
+
+	spentFloat, _ := status.SpentUSD.Float64()
+	remainingFloat, _ := status.RemainingUSD.Float64()
+
+	return &pb.BudgetStatus{
+		Budget:       budgetPb,
+		SpentUsd:     spentFloat,
+		RemainingUsd: remainingFloat,
+		PercentUsed:  status.PercentUsed,
+		Exceeded:     status.Exceeded,
+		Blocked:      status.Blocked,
+		PeriodStart:  timestamppb.New(status.PeriodStart),
+		PeriodEnd:    timestamppb.New(status.PeriodEnd),
 	}, nil
 }
+
+// Pb2CreateBudgetPayload converts proto to entity
+func (t *Transform) Pb2CreateBudgetPayload(pb *pb.CreateBudgetPayload) (*entities.CreateBudgetPayload, error) {
+	if pb == nil {
+		return nil, fmt.Errorf("payload is nil")
+	}
+
+	return &entities.CreateBudgetPayload{
+		TenantID:   pb.TenantId,
+		Scope:      entities.BudgetScope(pb.Scope),
+		ScopeID:    pb.ScopeId,
+		Period:     entities.BudgetPeriod(pb.Period),
+		LimitUSD:   decimal.NewFromFloat(pb.LimitUsd),
+		Thresholds: pb.Thresholds,
+		WebhookURL: pb.WebhookUrl,
+		HardStop:   pb.HardStop,
+	}, nil
+}
+
+// ModelPricing2Pb converts entity to proto
+func (t *Transform) ModelPricing2Pb(pricing *entities.ModelPricing) (*pb.ModelPricing, error) {
+	if pricing == nil {
+		return nil, fmt.Errorf("pricing is nil")
+	}
+
+	inputFloat, _ := pricing.InputPer1kTokens.Float64()
+	outputFloat, _ := pricing.OutputPer1kTokens.Float64()
+	cachedReadFloat, _ := pricing.CachedReadPer1kTokens.Float64()
+	cacheWriteFloat, _ := pricing.CacheWritePer1kTokens.Float64()
+	perImageFloat, _ := pricing.PerImage.Float64()
+	perRequestFloat, _ := pricing.PerRequest.Float64()
+
+	return &pb.ModelPricing{
+		Id:                     pricing.ID,
+		ModelId:                pricing.ModelID,
+		InputPer_1KTokens:      inputFloat,
+		OutputPer_1KTokens:     outputFloat,
+		CachedReadPer_1KTokens: cachedReadFloat,
+		CacheWritePer_1KTokens: cacheWriteFloat,
+		PerImage:               perImageFloat,
+		PerRequest:             perRequestFloat,
+		EffectiveFrom:          timestamppb.New(pricing.EffectiveFrom),
+		CreatedAt:              timestamppb.New(pricing.CreatedAt),
+	}, nil
+}
+
+// Pb2SetModelPricingPayload converts proto to entity
+func (t *Transform) Pb2SetModelPricingPayload(pb *pb.SetModelPricingPayload) (*entities.SetModelPricingPayload, error) {
+	if pb == nil {
+		return nil, fmt.Errorf("payload is nil")
+	}
+
+	payload := &entities.SetModelPricingPayload{
+		ModelID:               pb.ModelId,
+		InputPer1kTokens:      decimal.NewFromFloat(pb.InputPer_1KTokens),
+		OutputPer1kTokens:     decimal.NewFromFloat(pb.OutputPer_1KTokens),
+		CachedReadPer1kTokens: decimal.NewFromFloat(pb.CachedReadPer_1KTokens),
+		CacheWritePer1kTokens: decimal.NewFromFloat(pb.CacheWritePer_1KTokens),
+		PerImage:              decimal.NewFromFloat(pb.PerImage),
+		PerRequest:            decimal.NewFromFloat(pb.PerRequest),
+	}
+	if pb.EffectiveFrom != nil {
+		effectiveFrom := pb.EffectiveFrom.AsTime()
+		payload.EffectiveFrom = &effectiveFrom
+	}
+
+	return payload, nil
+}
+
+// ModelAlias2Pb converts entity to proto
+func (t *Transform) ModelAlias2Pb(alias *entities.ModelAlias) (*pb.ModelAlias, error) {
+	if alias == nil {
+		return nil, fmt.Errorf("model alias is nil")
+	}
+
+	return &pb.ModelAlias{
+		Id:        alias.ID,
+		Name:      alias.Name,
+		TenantId:  alias.TenantID,
+		ModelId:   alias.ModelID,
+		ModelName: alias.ModelName,
+		Routes:    modelAliasRoutes2Pb(alias.Routes),
+		CreatedAt: timestamppb.New(alias.CreatedAt),
+		UpdatedAt: timestamppb.New(alias.UpdatedAt),
+	}, nil
+}
+
+// ModelAliasRevision2Pb converts entity to proto
+func (t *Transform) ModelAliasRevision2Pb(revision *entities.ModelAliasRevision) (*pb.ModelAliasRevision, error) {
+	if revision == nil {
+		return nil, fmt.Errorf("model alias revision is nil")
+	}
+
+	return &pb.ModelAliasRevision{
+		Id:              revision.ID,
+		AliasName:       revision.AliasName,
+		TenantId:        revision.TenantID,
+		PreviousModelId: revision.PreviousModelID,
+		ModelId:         revision.ModelID,
+		Routes:          modelAliasRoutes2Pb(revision.Routes),
+		Actor:           revision.Actor,
+		CreatedAt:       timestamppb.New(revision.CreatedAt),
+	}, nil
+}
+
+// RoutingCandidate2Pb converts entity to proto
+func (t *Transform) RoutingCandidate2Pb(candidate *entities.RoutingCandidate) (*pb.RoutingCandidate, error) {
+	if candidate == nil {
+		return nil, fmt.Errorf("routing candidate is nil")
+	}
+
+	model, err := t.Model2Pb(candidate.Model)
+	if err != nil {
+		return nil, err
+	}
+	inputFloat, _ := candidate.InputPer1kTokens.Float64()
+	outputFloat, _ := candidate.OutputPer1kTokens.Float64()
+
+	return &pb.RoutingCandidate{
+		Model:              model,
+		InputPer_1KTokens:  inputFloat,
+		OutputPer_1KTokens: outputFloat,
+		QuotaRemaining:     candidate.QuotaRemaining,
+	}, nil
+}
+
+// Pb2SetModelAliasPayload converts proto to entity
+func (t *Transform) Pb2SetModelAliasPayload(pb *pb.SetModelAliasPayload) (*entities.SetModelAliasPayload, error) {
+	if pb == nil {
+		return nil, fmt.Errorf("payload is nil")
+	}
+
+	payload := &entities.SetModelAliasPayload{
+		Name:            pb.Name,
+		TenantID:        pb.TenantId,
+		ModelID:         pb.ModelId,
+		PreviousModelID: pb.PreviousModelId,
+	}
+	for _, route := range pb.Routes {
+		if route == nil {
+			continue
+		}
+		payload.Routes = append(payload.Routes, entities.ModelAliasRoute{ModelID: route.ModelId, Weight: route.Weight})
+	}
+	return payload, nil
+}
+
+// modelAliasRoutes2Pb converts alias routes to proto
+func modelAliasRoutes2Pb(routes []entities.ModelAliasRoute) []*pb.ModelAliasRoute {
+	routesPb := make([]*pb.ModelAliasRoute, len(routes))
+	for i, route := range routes {
+		routesPb[i] = &pb.ModelAliasRoute{
+			ModelId:   route.ModelID,
+			ModelName: route.ModelName,
+			Weight:    route.Weight,
+		}
+	}
+	return routesPb
+}
+
+// UsageStats2Pb converts entity to proto
+func (t *Transform) UsageStats2Pb(stats *entities.UsageStats) (*pb.UsageStats, error) {
+	if stats == nil {
+		return nil, fmt.Errorf("usage stats is nil")
+	}
+
+	costFloat, _ := stats.Cost.Float64()
+
+	statsPb := &pb.UsageStats{
+		ModelId:          stats.ModelID,
+		UserId:           stats.UserID,
+		SessionId:        stats.SessionID,
+		Alias:            stats.Alias,
+		Status:           string(stats.Status),
+		RequestCount:     stats.RequestCount,
+		ErrorCount:       stats.ErrorCount,
+		ErrorRate:        stats.ErrorRate,
+		TokensUsed:       stats.TokensUsed,
+		PromptTokens:     stats.PromptTokens,
+		CompletionTokens: stats.CompletionTokens,
+		Cost:             costFloat,
+		LatencyAvgMs:     stats.LatencyAvgMs,
+		LatencyP50Ms:     stats.LatencyP50Ms,
+		LatencyP95Ms:     stats.LatencyP95Ms,
+		LatencyP99Ms:     stats.LatencyP99Ms,
+	}
+	if stats.BucketStart != nil {
+		statsPb.BucketStart = timestamppb.New(*stats.BucketStart)
+	}
+
+	return statsPb, nil
+}
+
+// Pb2DeprecateModelPayload converts proto to entity
+func (t *Transform) Pb2DeprecateModelPayload(pb *pb.DeprecateModelPayload) (*entities.DeprecateModelPayload, error) {
+	if pb == nil {
+		return nil, fmt.Errorf("payload is nil")
+	}
+
+	payload := &entities.DeprecateModelPayload{
+		ModelID:       pb.ModelId,
+		ReplacementID: pb.ReplacementModelId,
+	}
+	if pb.AnnouncedAt != nil {
+		payload.AnnouncedAt = pb.AnnouncedAt.AsTime()
+	}
+	if pb.SunsetAt != nil {
+		sunsetAt := pb.SunsetAt.AsTime()
+		payload.SunsetAt = &sunsetAt
+	}
+	return payload, nil
+}
+
+// DeprecatedModelCaller2Pb converts entity to proto
+func (t *Transform) DeprecatedModelCaller2Pb(caller *entities.DeprecatedModelCaller) (*pb.DeprecatedModelCaller, error) {
+	if caller == nil {
+		return nil, fmt.Errorf("caller is nil")
+	}
+
+	callerPb := &pb.DeprecatedModelCaller{
+		ModelId:            caller.ModelID,
+		ModelName:          caller.ModelName,
+		ReplacementModelId: caller.ReplacementID,
+		TenantId:           caller.TenantID,
+		ProjectId:          caller.ProjectID,
+		UserId:             caller.UserID,
+		ApiKeyId:           caller.APIKeyID,
+		RequestCount:       caller.RequestCount,
+		RedirectedCount:    caller.RedirectedCount,
+		LastCalledAt:       timestamppb.New(caller.LastCalledAt),
+	}
+	if caller.SunsetAt != nil {
+		callerPb.SunsetAt = timestamppb.New(*caller.SunsetAt)
+	}
+	return callerPb, nil
+}
+
+// Pb2UsageStatsFilter converts proto to entity
+func (t *Transform) Pb2UsageStatsFilter(req *pb.GetUsageStatsRequest) (*entities.UsageStatsFilter, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request is nil")
+	}
+
+	filter := &entities.UsageStatsFilter{
+		ModelID:   req.ModelId,
+		UserID:    req.UserId,
+		SessionID: req.SessionId,
+		Alias:     req.Alias,
+		Status:    entities.UsageStatus(req.Status),
+		Bucket:    entities.UsageBucket(req.Bucket),
+	}
+	for _, dimension := range req.GroupBy {
+		filter.GroupBy = append(filter.GroupBy, entities.UsageGroupBy(dimension))
+	}
+	if req.StartTime != nil {
+		filter.StartTime = req.StartTime.AsTime()
+	}
+	if req.EndTime != nil {
+		filter.EndTime = req.EndTime.AsTime()
+	}
+
+	return filter, nil
+}
+
+// ProviderKey2Pb converts entity to proto. The encrypted key is never exposed.
+func (t *Transform) ProviderKey2Pb(key *entities.ProviderKey) (*pb.ProviderKey, error) {
+	if key == nil {
+		return nil, fmt.Errorf("provider key is nil")
+	}
+
+	keyPb := &pb.ProviderKey{
+		Id:           key.ID,
+		ModelId:      key.ModelID,
+		Label:        key.Label,
+		KeyHint:      key.KeyHint,
+		Weight:       key.Weight,
+		Status:       string(key.Status),
+		FailureCount: key.FailureCount,
+		CreatedAt:    timestamppb.New(key.CreatedAt),
+		UpdatedAt:    timestamppb.New(key.UpdatedAt),
+	}
+	if key.QuarantinedUntil != nil && key.QuarantinedUntil.After(time.Now()) {
+		keyPb.QuarantinedUntil = timestamppb.New(*key.QuarantinedUntil)
+	}
+	if key.LastRateLimitedAt != nil {
+		keyPb.LastRateLimitedAt = timestamppb.New(*key.LastRateLimitedAt)
+	}
+
+	return keyPb, nil
+}
+
+// Pb2AddProviderKeyPayload converts proto to entity
+func (t *Transform) Pb2AddProviderKeyPayload(pb *pb.AddProviderKeyPayload) (*entities.AddProviderKeyPayload, error) {
+	if pb == nil {
+		return nil, fmt.Errorf("payload is nil")
+	}
+
+	return &entities.AddProviderKeyPayload{
+		ModelID: pb.ModelId,
+		Label:   pb.Label,
+		APIKey:  pb.ApiKey,
+		Weight:  pb.Weight,
+	}, nil
+}
+
+// Pb2UpdateProviderKeyPayload converts proto to entity
+func (t *Transform) Pb2UpdateProviderKeyPayload(pb *pb.UpdateProviderKeyPayload) (*entities.UpdateProviderKeyPayload, error) {
+	if pb == nil {
+		return nil, fmt.Errorf("payload is nil")
+	}
+
+	return &entities.UpdateProviderKeyPayload{
+		ID:      pb.Id,
+		ModelID: pb.ModelId,
+		Weight:  pb.Weight,
+		Status:  entities.ProviderKeyStatus(pb.Status),
+	}, nil
+}
+
+// AuditEvent2Pb converts entity to proto
+func (t *Transform) AuditEvent2Pb(event *entities.AuditEvent) (*pb.AuditEvent, error) {
+	if event == nil {
+		return nil, fmt.Errorf("audit event is nil")
+	}
+
+	changes := make([]*pb.AuditChange, 0, len(event.Changes))
+	for _, change := range event.Changes {
+		changes = append(changes, &pb.AuditChange{
+			Field:    change.Field,
+			OldValue: change.OldValue,
+			NewValue: change.NewValue,
+		})
+	}
+
+	return &pb.AuditEvent{
+		Id:              event.ID,
+		Sequence:        event.Sequence,
+		TenantId:        event.TenantID,
+		Actor:           event.Actor,
+		Action:          string(event.Action),
+		ResourceType:    event.ResourceType,
+		ResourceId:      event.ResourceID,
+		Changes:         changes,
+		SourceIp:        event.SourceIP,
+		RequestMetadata: event.RequestMetadata,
+		OccurredAt:      timestamppb.New(event.OccurredAt),
+		PrevHash:        event.PrevHash,
+		Hash:            event.Hash,
+	}, nil
+}
+
+// Pb2AuditEventFilter converts proto to entity
+func (t *Transform) Pb2AuditEventFilter(req *pb.ListAuditEventsRequest) (*entities.AuditEventFilter, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request is nil")
+	}
+
+	filter := &entities.AuditEventFilter{
+		TenantID:     req.TenantId,
+		Actor:        req.Actor,
+		Action:       entities.AuditAction(req.Action),
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceId,
+		Page:         req.Page,
+		PageSize:     req.PageSize,
+	}
+	if req.StartTime != nil {
+		filter.StartTime = req.StartTime.AsTime()
+	}
+	if req.EndTime != nil {
+		filter.EndTime = req.EndTime.AsTime()
+	}
+
+	return filter, nil
+}
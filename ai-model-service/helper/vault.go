@@ -59,17 +59,23 @@ func (v *VaultClient) GetCredentials(ctx context.Context, vaultPath string) (*en
 		return nil, errors.NotFound(fmt.Sprintf(constants.ErrVaultPathNotFound, vaultPath))
 	}
 
+	// KV v2 reads ("<mount>/data/<path>") nest the secret under "data"
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+
 	// Extract credentials
-	apiKey, ok := secret.Data["api_key"].(string)
+	apiKey, ok := data["api_key"].(string)
 	if !ok {
 		return nil, errors.Internal(fmt.Errorf(constants.ErrInvalidCredentials))
 	}
 
-	baseURL, _ := secret.Data["base_url"].(string)
+	baseURL, _ := data["base_url"].(string)
 
 	// Parse headers if present
 	headers := make(map[string]string)
-	if headersData, ok := secret.Data["headers"].(map[string]interface{}); ok {
+	if headersData, ok := data["headers"].(map[string]interface{}); ok {
 		for k, v := range headersData {
 			if strVal, ok := v.(string); ok {
 				headers[k] = strVal
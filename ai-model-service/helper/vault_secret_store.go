@@ -0,0 +1,116 @@
+package helper
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/google/uuid"
+)
+
+// vaultKVSecretStore keeps each key in Vault KV v2 at <mount>/<prefix>/<model id>/<secret id>.
+// The database stores the secret's logical read path.
+type vaultKVSecretStore struct {
+	vault  *VaultClient
+	mount  string
+	prefix string
+}
+
+// NewVaultKVSecretStore creates a secret store backed by a Vault KV v2 mount
+func NewVaultKVSecretStore(vault *VaultClient, mount, prefix string) SecretStore {
+	return &vaultKVSecretStore{
+		vault:  vault,
+		mount:  strings.Trim(mount, "/"),
+		prefix: strings.Trim(prefix, "/"),
+	}
+}
+
+func (s *vaultKVSecretStore) Put(ctx context.Context, modelID, apiKey string) (string, errors.BaseError) {
+	path := strings.TrimPrefix(fmt.Sprintf("%s/%s/%s", s.prefix, modelID, uuid.New().String()), "/")
+	if _, err := s.vault.client.KVv2(s.mount).Put(ctx, path, map[string]interface{}{"api_key": apiKey}); err != nil {
+		return "", errors.Internal(fmt.Errorf(constants.ErrVaultWriteFailed, err))
+	}
+	return s.mount + "/data/" + path, nil
+}
+
+func (s *vaultKVSecretStore) Get(ctx context.Context, stored string) (string, errors.BaseError) {
+	creds, err := s.vault.GetCredentials(ctx, stored)
+	if err != nil {
+		return "", err
+	}
+	return creds.APIKey, nil
+}
+
+// Delete destroys every version of the secret
+func (s *vaultKVSecretStore) Delete(ctx context.Context, stored string) errors.BaseError {
+	path, ok := strings.CutPrefix(stored, s.mount+"/data/")
+	if !ok {
+		return errors.BadRequest(constants.ErrInvalidVaultPath)
+	}
+	if err := s.vault.client.KVv2(s.mount).DeleteMetadata(ctx, path); err != nil {
+		return errors.Internal(fmt.Errorf(constants.ErrVaultDeleteFailed, err))
+	}
+	s.vault.cache.Delete(stored)
+	return nil
+}
+
+// vaultTransitSecretStore encrypts keys with a Vault transit key. The master key never leaves
+// Vault; the database stores the transit ciphertext ("vault:v<n>:...").
+type vaultTransitSecretStore struct {
+	vault   *VaultClient
+	mount   string
+	keyName string
+}
+
+// NewVaultTransitSecretStore creates a secret store that encrypts with Vault transit
+func NewVaultTransitSecretStore(vault *VaultClient, mount, keyName string) SecretStore {
+	return &vaultTransitSecretStore{
+		vault:   vault,
+		mount:   strings.Trim(mount, "/"),
+		keyName: keyName,
+	}
+}
+
+func (s *vaultTransitSecretStore) Put(ctx context.Context, modelID, apiKey string) (string, errors.BaseError) {
+	secret, err := s.vault.client.Logical().WriteWithContext(ctx, s.mount+"/encrypt/"+s.keyName, map[string]interface{}{
+		"plaintext": base64.StdEncoding.EncodeToString([]byte(apiKey)),
+	})
+	if err != nil {
+		return "", errors.Internal(fmt.Errorf(constants.ErrVaultTransitFailed, "encrypt", err))
+	}
+	if secret == nil || secret.Data == nil {
+		return "", errors.Internal(fmt.Errorf(constants.ErrVaultTransitFailed, "encrypt", "empty response"))
+	}
+
+	ciphertext, ok := secret.Data["ciphertext"].(string)
+	if !ok || ciphertext == "" {
+		return "", errors.Internal(fmt.Errorf(constants.ErrVaultTransitFailed, "encrypt", "missing ciphertext"))
+	}
+	return ciphertext, nil
+}
+
+func (s *vaultTransitSecretStore) Get(ctx context.Context, stored string) (string, errors.BaseError) {
+	secret, err := s.vault.client.Logical().WriteWithContext(ctx, s.mount+"/decrypt/"+s.keyName, map[string]interface{}{
+		"ciphertext": stored,
+	})
+	if err != nil {
+		return "", errors.Internal(fmt.Errorf(constants.ErrVaultTransitFailed, "decrypt", err))
+	}
+	if secret == nil || secret.Data == nil {
+		return "", errors.Internal(fmt.Errorf(constants.ErrVaultTransitFailed, "decrypt", "empty response"))
+	}
+
+	encoded, _ := secret.Data["plaintext"].(string)
+	plaintext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", errors.Internal(fmt.Errorf(constants.ErrVaultTransitFailed, "decrypt", err))
+	}
+	return string(plaintext), nil
+}
+
+func (s *vaultTransitSecretStore) Delete(ctx context.Context, stored string) errors.BaseError {
+	return nil
+}
@@ -0,0 +1,92 @@
+package helper
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/helper/vaulttest"
+)
+
+func newTestVaultClient(t *testing.T) (*VaultClient, *vaulttest.Server) {
+	t.Helper()
+	server := vaulttest.NewServer()
+	t.Cleanup(server.Close)
+
+	client, err := NewVaultClient(server.URL, vaulttest.Token)
+	if err != nil {
+		t.Fatalf("NewVaultClient: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client, server
+}
+
+func TestVaultKVSecretStore(t *testing.T) {
+	ctx := context.Background()
+	client, server := newTestVaultClient(t)
+	store := NewVaultKVSecretStore(client, "/secret/", "/ai-models/")
+
+	stored, err := store.Put(ctx, "model-1", "sk-kv-secret")
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if !strings.HasPrefix(stored, "secret/data/ai-models/model-1/") {
+		t.Fatalf("Put stored %q, want a path under secret/data/ai-models/model-1/", stored)
+	}
+	if data := server.KV(stored); data == nil || data["api_key"] != "sk-kv-secret" {
+		t.Fatalf("vault holds %v at %s, want api_key sk-kv-secret", data, stored)
+	}
+
+	apiKey, err := store.Get(ctx, stored)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if apiKey != "sk-kv-secret" {
+		t.Fatalf("Get = %q, want sk-kv-secret", apiKey)
+	}
+
+	if err := store.Delete(ctx, stored); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if server.KVCount() != 0 {
+		t.Fatalf("vault still holds %d secrets after Delete", server.KVCount())
+	}
+	// Delete also evicts the cached copy
+	if _, err := store.Get(ctx, stored); err == nil || err.GetCode() != errors.NOT_FOUND {
+		t.Fatalf("Get after Delete = %v, want NOT_FOUND", err)
+	}
+
+	if err := store.Delete(ctx, "other/data/ai-models/model-1/x"); err == nil || err.GetCode() != errors.BAD_REQUEST {
+		t.Fatalf("Delete outside the mount = %v, want BAD_REQUEST", err)
+	}
+}
+
+func TestVaultTransitSecretStore(t *testing.T) {
+	ctx := context.Background()
+	client, server := newTestVaultClient(t)
+	store := NewVaultTransitSecretStore(client, "/transit/", "ai-models")
+
+	stored, err := store.Put(ctx, "model-1", "sk-transit-secret")
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if !strings.HasPrefix(stored, "vault:v1:") || strings.Contains(stored, "sk-transit-secret") {
+		t.Fatalf("Put stored %q, want a transit ciphertext", stored)
+	}
+	if server.KVCount() != 0 {
+		t.Fatalf("transit store wrote %d KV secrets", server.KVCount())
+	}
+
+	apiKey, err := store.Get(ctx, stored)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if apiKey != "sk-transit-secret" {
+		t.Fatalf("Get = %q, want sk-transit-secret", apiKey)
+	}
+
+	if _, err := store.Get(ctx, "not-a-ciphertext"); err == nil || err.GetCode() != errors.INTERNAL_ERROR {
+		t.Fatalf("Get of an invalid ciphertext = %v, want INTERNAL_ERROR", err)
+	}
+}
@@ -0,0 +1,168 @@
+// Package vaulttest provides an in-memory Vault HTTP server for tests. It implements the
+// subset of the KV v2 and transit APIs the secret stores use.
+package vaulttest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token is the token the server accepts
+const Token = "test-token"
+
+// transitPrefix marks ciphertexts produced by the fake transit engine
+const transitPrefix = "vault:v1:"
+
+// Server is a fake Vault server. KV secrets are kept per "<mount>/data/<path>".
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+	kv map[string]map[string]interface{}
+}
+
+// NewServer starts a fake Vault server; callers must Close it
+func NewServer() *Server {
+	s := &Server{kv: make(map[string]map[string]interface{})}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// KV returns the data stored at a KV v2 read path, or nil when there is none
+func (s *Server) KV(path string) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.kv[path]
+}
+
+// KVCount returns the number of KV secrets stored
+func (s *Server) KVCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.kv)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Vault-Token") != Token {
+		writeError(w, http.StatusForbidden, "permission denied")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+	switch {
+	case strings.Contains(path, "/encrypt/"):
+		s.encrypt(w, r)
+	case strings.Contains(path, "/decrypt/"):
+		s.decrypt(w, r)
+	case strings.Contains(path, "/data/"):
+		s.data(w, r, path)
+	case strings.Contains(path, "/metadata/"):
+		s.metadata(w, r, path)
+	default:
+		writeError(w, http.StatusNotFound, "no handler for route")
+	}
+}
+
+func (s *Server) data(w http.ResponseWriter, r *http.Request, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut, http.MethodPost:
+		var body struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Data == nil {
+			writeError(w, http.StatusBadRequest, "no data provided")
+			return
+		}
+		s.kv[path] = body.Data
+		writeJSON(w, map[string]interface{}{"data": versionMetadata()})
+	case http.MethodGet:
+		data, ok := s.kv[path]
+		if !ok {
+			writeError(w, http.StatusNotFound, "")
+			return
+		}
+		writeJSON(w, map[string]interface{}{"data": map[string]interface{}{
+			"data":     data,
+			"metadata": versionMetadata(),
+		}})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "unsupported operation")
+	}
+}
+
+func (s *Server) metadata(w http.ResponseWriter, r *http.Request, path string) {
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "unsupported operation")
+		return
+	}
+	s.mu.Lock()
+	delete(s.kv, strings.Replace(path, "/metadata/", "/data/", 1))
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// encrypt wraps the plaintext so it round-trips but is never stored as is
+func (s *Server) encrypt(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Plaintext string `json:"plaintext"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Plaintext == "" {
+		writeError(w, http.StatusBadRequest, "missing plaintext to encrypt")
+		return
+	}
+	ciphertext := transitPrefix + base64.StdEncoding.EncodeToString([]byte(body.Plaintext))
+	writeJSON(w, map[string]interface{}{"data": map[string]interface{}{"ciphertext": ciphertext}})
+}
+
+func (s *Server) decrypt(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	encoded, ok := strings.CutPrefix(body.Ciphertext, transitPrefix)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "invalid ciphertext: no prefix")
+		return
+	}
+	plaintext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid ciphertext: could not decode")
+		return
+	}
+	writeJSON(w, map[string]interface{}{"data": map[string]interface{}{"plaintext": string(plaintext)}})
+}
+
+func versionMetadata() map[string]interface{} {
+	return map[string]interface{}{
+		"version":       1,
+		"created_time":  time.Now().UTC().Format(time.RFC3339),
+		"deletion_time": "",
+		"destroyed":     false,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	errs := []string{}
+	if message != "" {
+		errs = append(errs, message)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"errors": errs})
+}
@@ -0,0 +1,61 @@
+package helper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+)
+
+// WebhookNotifier delivers budget alerts as JSON POST requests
+type WebhookNotifier struct {
+	client     *http.Client
+	defaultURL string
+}
+
+// NewWebhookNotifier creates a webhook notifier. defaultURL receives alerts for budgets without their own webhook.
+func NewWebhookNotifier(defaultURL string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		client:     &http.Client{Timeout: timeout},
+		defaultURL: defaultURL,
+	}
+}
+
+// NotifyBudgetAlert posts the alert to the budget webhook, falling back to the default URL
+func (n *WebhookNotifier) NotifyBudgetAlert(ctx context.Context, webhookURL string, alert *entities.BudgetAlert) errors.BaseError {
+	if webhookURL == "" {
+		webhookURL = n.defaultURL
+	}
+	if webhookURL == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToSendAlert, err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToSendAlert, err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToSendAlert, err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToSendAlert, fmt.Sprintf("webhook returned %s", resp.Status)))
+	}
+
+	return nil
+}
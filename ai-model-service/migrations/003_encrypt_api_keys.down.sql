@@ -0,0 +1,3 @@
+-- Restore vault_path; encrypted keys cannot be moved back to Vault automatically
+ALTER TABLE ai_models DROP COLUMN IF EXISTS encrypted_api_key;
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS vault_path VARCHAR(255);
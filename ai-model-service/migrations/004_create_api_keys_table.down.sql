@@ -0,0 +1,4 @@
+-- Drop ai_api_keys table
+DROP INDEX IF EXISTS idx_usage_api_key;
+ALTER TABLE ai_usage_logs DROP COLUMN IF EXISTS api_key_id;
+DROP TABLE IF EXISTS ai_api_keys CASCADE;
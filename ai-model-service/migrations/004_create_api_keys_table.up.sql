@@ -0,0 +1,26 @@
+-- Create virtual API keys table
+CREATE TABLE IF NOT EXISTS ai_api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(32) NOT NULL,
+    key_hash VARCHAR(64) UNIQUE NOT NULL,
+    user_id UUID,
+    allowed_models JSONB DEFAULT '[]',
+    expires_at TIMESTAMP,
+    budget_usd DECIMAL(12,4) DEFAULT 0,
+    status VARCHAR(50) DEFAULT 'active',
+    last_used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Attribute usage to the key that made the call
+ALTER TABLE ai_usage_logs ADD COLUMN IF NOT EXISTS api_key_id UUID;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON ai_api_keys(user_id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_status ON ai_api_keys(status);
+CREATE INDEX IF NOT EXISTS idx_usage_api_key ON ai_usage_logs(api_key_id);
+
+-- Add comment
+COMMENT ON TABLE ai_api_keys IS 'Virtual API keys for proxy callers (SHA-256 hashed at rest)';
@@ -0,0 +1,8 @@
+-- Drop tenant columns and tables
+ALTER TABLE ai_api_keys DROP COLUMN IF EXISTS project_id;
+ALTER TABLE ai_api_keys DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE ai_usage_logs DROP COLUMN IF EXISTS project_id;
+ALTER TABLE ai_usage_logs DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE ai_models DROP COLUMN IF EXISTS tenant_id;
+DROP TABLE IF EXISTS ai_projects CASCADE;
+DROP TABLE IF EXISTS ai_tenants CASCADE;
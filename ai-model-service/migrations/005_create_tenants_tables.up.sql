@@ -0,0 +1,39 @@
+-- Create tenants table
+CREATE TABLE IF NOT EXISTS ai_tenants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) UNIQUE NOT NULL,
+    quota_daily BIGINT DEFAULT 0,
+    quota_monthly BIGINT DEFAULT 0,
+    status VARCHAR(50) DEFAULT 'active',
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Create projects table
+CREATE TABLE IF NOT EXISTS ai_projects (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID NOT NULL REFERENCES ai_tenants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (tenant_id, name)
+);
+
+-- Tenant-private models (NULL tenant_id = shared global model)
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES ai_tenants(id);
+
+-- Tenant attribution for usage and virtual keys
+ALTER TABLE ai_usage_logs ADD COLUMN IF NOT EXISTS tenant_id UUID;
+ALTER TABLE ai_usage_logs ADD COLUMN IF NOT EXISTS project_id UUID;
+ALTER TABLE ai_api_keys ADD COLUMN IF NOT EXISTS tenant_id UUID REFERENCES ai_tenants(id);
+ALTER TABLE ai_api_keys ADD COLUMN IF NOT EXISTS project_id UUID REFERENCES ai_projects(id);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_tenants_status ON ai_tenants(status);
+CREATE INDEX IF NOT EXISTS idx_models_tenant ON ai_models(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_usage_tenant_created ON ai_usage_logs(tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_api_keys_tenant ON ai_api_keys(tenant_id);
+
+-- Add comment
+COMMENT ON TABLE ai_tenants IS 'Organizations with tenant-scoped models, quotas and usage';
+COMMENT ON TABLE ai_projects IS 'Projects grouping usage within a tenant';
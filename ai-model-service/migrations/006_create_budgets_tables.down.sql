@@ -0,0 +1,4 @@
+-- Drop budget tables
+DROP INDEX IF EXISTS idx_usage_project_created;
+DROP TABLE IF EXISTS ai_budget_alerts CASCADE;
+DROP TABLE IF EXISTS ai_budgets CASCADE;
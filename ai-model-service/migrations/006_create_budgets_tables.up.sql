@@ -0,0 +1,34 @@
+-- Create budgets table
+CREATE TABLE IF NOT EXISTS ai_budgets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id UUID REFERENCES ai_tenants(id) ON DELETE CASCADE,
+    scope VARCHAR(50) NOT NULL,
+    scope_id VARCHAR(255) NOT NULL,
+    period VARCHAR(50) NOT NULL DEFAULT 'monthly',
+    limit_usd DECIMAL(12, 4) NOT NULL,
+    thresholds JSONB DEFAULT '[50,80,100]',
+    webhook_url TEXT,
+    hard_stop BOOLEAN DEFAULT false,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Alerts already sent, one row per budget, period and threshold
+CREATE TABLE IF NOT EXISTS ai_budget_alerts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    budget_id UUID NOT NULL REFERENCES ai_budgets(id) ON DELETE CASCADE,
+    period_start TIMESTAMP NOT NULL,
+    threshold INTEGER NOT NULL,
+    spent_usd DECIMAL(12, 4),
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (budget_id, period_start, threshold)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_budgets_scope ON ai_budgets(scope, scope_id);
+CREATE INDEX IF NOT EXISTS idx_budgets_tenant ON ai_budgets(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_usage_project_created ON ai_usage_logs(project_id, created_at DESC);
+
+-- Add comment
+COMMENT ON TABLE ai_budgets IS 'USD spend budgets per tenant, project, user or model';
+COMMENT ON TABLE ai_budget_alerts IS 'Budget threshold alerts already delivered per period';
@@ -0,0 +1,9 @@
+-- Drop pricing columns and table
+ALTER TABLE ai_usage_logs ALTER COLUMN cost TYPE DECIMAL(10, 4);
+ALTER TABLE ai_usage_logs DROP COLUMN IF EXISTS pricing_id;
+ALTER TABLE ai_usage_logs DROP COLUMN IF EXISTS image_count;
+ALTER TABLE ai_usage_logs DROP COLUMN IF EXISTS cache_write_tokens;
+ALTER TABLE ai_usage_logs DROP COLUMN IF EXISTS cached_read_tokens;
+ALTER TABLE ai_usage_logs DROP COLUMN IF EXISTS completion_tokens;
+ALTER TABLE ai_usage_logs DROP COLUMN IF EXISTS prompt_tokens;
+DROP TABLE IF EXISTS ai_model_pricing CASCADE;
@@ -0,0 +1,35 @@
+-- Create effective-dated model pricing table
+CREATE TABLE IF NOT EXISTS ai_model_pricing (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    model_id UUID NOT NULL REFERENCES ai_models(id) ON DELETE CASCADE,
+    input_per_1k_tokens DECIMAL(14, 8) DEFAULT 0,
+    output_per_1k_tokens DECIMAL(14, 8) DEFAULT 0,
+    cached_read_per_1k_tokens DECIMAL(14, 8) DEFAULT 0,
+    cache_write_per_1k_tokens DECIMAL(14, 8) DEFAULT 0,
+    per_image DECIMAL(14, 8) DEFAULT 0,
+    per_request DECIMAL(14, 8) DEFAULT 0,
+    effective_from TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (model_id, effective_from)
+);
+
+-- Seed an initial version from the flat per-1k rate so existing models keep their price
+INSERT INTO ai_model_pricing (model_id, input_per_1k_tokens, output_per_1k_tokens, effective_from)
+SELECT id, cost_per_1k_tokens, cost_per_1k_tokens, created_at
+FROM ai_models
+WHERE cost_per_1k_tokens IS NOT NULL
+ON CONFLICT (model_id, effective_from) DO NOTHING;
+
+-- Token breakdown and pricing version on usage logs
+ALTER TABLE ai_usage_logs ADD COLUMN IF NOT EXISTS prompt_tokens BIGINT DEFAULT 0;
+ALTER TABLE ai_usage_logs ADD COLUMN IF NOT EXISTS completion_tokens BIGINT DEFAULT 0;
+ALTER TABLE ai_usage_logs ADD COLUMN IF NOT EXISTS cached_read_tokens BIGINT DEFAULT 0;
+ALTER TABLE ai_usage_logs ADD COLUMN IF NOT EXISTS cache_write_tokens BIGINT DEFAULT 0;
+ALTER TABLE ai_usage_logs ADD COLUMN IF NOT EXISTS image_count BIGINT DEFAULT 0;
+ALTER TABLE ai_usage_logs ADD COLUMN IF NOT EXISTS pricing_id UUID REFERENCES ai_model_pricing(id);
+
+-- Sub-cent request costs need more precision than decimal(10,4)
+ALTER TABLE ai_usage_logs ALTER COLUMN cost TYPE DECIMAL(14, 8);
+
+-- Add comment
+COMMENT ON TABLE ai_model_pricing IS 'Immutable, effective-dated price versions per model';
@@ -0,0 +1,23 @@
+-- Convert usage logs back to a regular table
+ALTER TABLE ai_usage_logs RENAME TO ai_usage_logs_partitioned;
+
+CREATE TABLE ai_usage_logs (LIKE ai_usage_logs_partitioned INCLUDING DEFAULTS);
+ALTER TABLE ai_usage_logs ADD PRIMARY KEY (id);
+
+INSERT INTO ai_usage_logs SELECT * FROM ai_usage_logs_partitioned;
+DROP TABLE ai_usage_logs_partitioned CASCADE;
+
+ALTER TABLE ai_usage_logs ADD FOREIGN KEY (model_id) REFERENCES ai_models(id) ON DELETE CASCADE;
+ALTER TABLE ai_usage_logs ADD FOREIGN KEY (pricing_id) REFERENCES ai_model_pricing(id);
+
+CREATE INDEX IF NOT EXISTS idx_usage_model_created ON ai_usage_logs(model_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_usage_user_created ON ai_usage_logs(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_usage_tenant_created ON ai_usage_logs(tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_usage_project_created ON ai_usage_logs(project_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_usage_prompt_hash ON ai_usage_logs(prompt_hash);
+CREATE INDEX IF NOT EXISTS idx_usage_created_date ON ai_usage_logs(DATE(created_at));
+CREATE INDEX IF NOT EXISTS idx_usage_api_key ON ai_usage_logs(api_key_id);
+
+-- Drop rollups
+DROP TABLE IF EXISTS ai_usage_daily CASCADE;
+DROP TABLE IF EXISTS ai_usage_hourly CASCADE;
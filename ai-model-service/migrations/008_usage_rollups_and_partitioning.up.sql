@@ -0,0 +1,96 @@
+-- Create hourly and daily usage rollups.
+-- tenant_id is the nil UUID for usage without a tenant so it can be part of the primary key.
+CREATE TABLE IF NOT EXISTS ai_usage_hourly (
+    bucket_start TIMESTAMP NOT NULL,
+    model_id UUID NOT NULL,
+    tenant_id UUID NOT NULL,
+    request_count BIGINT NOT NULL DEFAULT 0,
+    error_count BIGINT NOT NULL DEFAULT 0,
+    tokens_used BIGINT NOT NULL DEFAULT 0,
+    success_tokens BIGINT NOT NULL DEFAULT 0,
+    prompt_tokens BIGINT NOT NULL DEFAULT 0,
+    completion_tokens BIGINT NOT NULL DEFAULT 0,
+    cost DECIMAL(14, 8) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (bucket_start, model_id, tenant_id)
+);
+
+CREATE TABLE IF NOT EXISTS ai_usage_daily (
+    bucket_start TIMESTAMP NOT NULL,
+    model_id UUID NOT NULL,
+    tenant_id UUID NOT NULL,
+    request_count BIGINT NOT NULL DEFAULT 0,
+    error_count BIGINT NOT NULL DEFAULT 0,
+    tokens_used BIGINT NOT NULL DEFAULT 0,
+    success_tokens BIGINT NOT NULL DEFAULT 0,
+    prompt_tokens BIGINT NOT NULL DEFAULT 0,
+    completion_tokens BIGINT NOT NULL DEFAULT 0,
+    cost DECIMAL(14, 8) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (bucket_start, model_id, tenant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_hourly_tenant ON ai_usage_hourly(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_usage_daily_tenant ON ai_usage_daily(tenant_id);
+
+-- Backfill rollups from existing logs
+INSERT INTO ai_usage_hourly (bucket_start, model_id, tenant_id, request_count, error_count, tokens_used, success_tokens, prompt_tokens, completion_tokens, cost)
+SELECT date_trunc('hour', created_at), model_id, COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'),
+       COUNT(*), COUNT(*) FILTER (WHERE status <> 'success'),
+       COALESCE(SUM(tokens_used), 0), COALESCE(SUM(tokens_used) FILTER (WHERE status = 'success'), 0),
+       COALESCE(SUM(prompt_tokens), 0), COALESCE(SUM(completion_tokens), 0), COALESCE(SUM(cost), 0)
+FROM ai_usage_logs
+GROUP BY 1, 2, 3
+ON CONFLICT DO NOTHING;
+
+INSERT INTO ai_usage_daily (bucket_start, model_id, tenant_id, request_count, error_count, tokens_used, success_tokens, prompt_tokens, completion_tokens, cost)
+SELECT date_trunc('day', bucket_start), model_id, tenant_id,
+       SUM(request_count), SUM(error_count), SUM(tokens_used), SUM(success_tokens),
+       SUM(prompt_tokens), SUM(completion_tokens), SUM(cost)
+FROM ai_usage_hourly
+GROUP BY 1, 2, 3
+ON CONFLICT DO NOTHING;
+
+-- Partition raw usage logs by month so retention can drop whole partitions
+ALTER TABLE ai_usage_logs RENAME TO ai_usage_logs_unpartitioned;
+
+CREATE TABLE ai_usage_logs (LIKE ai_usage_logs_unpartitioned INCLUDING DEFAULTS)
+    PARTITION BY RANGE (created_at);
+ALTER TABLE ai_usage_logs ADD PRIMARY KEY (id, created_at);
+
+-- Monthly partitions from the oldest log through two months ahead; the service creates later ones
+DO $$
+DECLARE
+    month_start TIMESTAMP := date_trunc('month', COALESCE((SELECT MIN(created_at) FROM ai_usage_logs_unpartitioned), NOW()));
+    last_month TIMESTAMP := date_trunc('month', NOW()) + INTERVAL '2 months';
+BEGIN
+    WHILE month_start <= last_month LOOP
+        EXECUTE format(
+            'CREATE TABLE IF NOT EXISTS %I PARTITION OF ai_usage_logs FOR VALUES FROM (%L) TO (%L)',
+            'ai_usage_logs_' || to_char(month_start, 'YYYY_MM'),
+            month_start,
+            month_start + INTERVAL '1 month'
+        );
+        month_start := month_start + INTERVAL '1 month';
+    END LOOP;
+END $$;
+
+INSERT INTO ai_usage_logs SELECT * FROM ai_usage_logs_unpartitioned WHERE created_at IS NOT NULL;
+DROP TABLE ai_usage_logs_unpartitioned;
+
+ALTER TABLE ai_usage_logs ADD FOREIGN KEY (model_id) REFERENCES ai_models(id) ON DELETE CASCADE;
+ALTER TABLE ai_usage_logs ADD FOREIGN KEY (pricing_id) REFERENCES ai_model_pricing(id);
+
+-- Recreate indexes on the partitioned table
+CREATE INDEX IF NOT EXISTS idx_usage_model_created ON ai_usage_logs(model_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_usage_user_created ON ai_usage_logs(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_usage_tenant_created ON ai_usage_logs(tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_usage_project_created ON ai_usage_logs(project_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_usage_prompt_hash ON ai_usage_logs(prompt_hash);
+CREATE INDEX IF NOT EXISTS idx_usage_created_date ON ai_usage_logs(DATE(created_at));
+CREATE INDEX IF NOT EXISTS idx_usage_api_key ON ai_usage_logs(api_key_id);
+
+-- Add comment
+COMMENT ON TABLE ai_usage_logs IS 'AI usage tracking for quota management and analytics, partitioned by month';
+COMMENT ON TABLE ai_usage_hourly IS 'Hourly usage rollup per model and tenant';
+COMMENT ON TABLE ai_usage_daily IS 'Daily (UTC) usage rollup per model and tenant, used for quota checks';
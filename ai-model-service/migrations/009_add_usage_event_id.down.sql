@@ -0,0 +1,3 @@
+-- Drop usage event idempotency key
+DROP INDEX IF EXISTS idx_usage_event;
+ALTER TABLE ai_usage_logs DROP COLUMN IF EXISTS event_id;
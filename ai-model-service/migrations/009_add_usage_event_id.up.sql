@@ -0,0 +1,5 @@
+-- Add an idempotency key for usage events delivered by the proxy outbox.
+-- The unique index includes created_at because ai_usage_logs is partitioned by it;
+-- redelivered events carry the same occurrence time, so duplicates still collide.
+ALTER TABLE ai_usage_logs ADD COLUMN IF NOT EXISTS event_id VARCHAR(64);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_usage_event ON ai_usage_logs(event_id, created_at);
@@ -0,0 +1,2 @@
+-- Drop provider key pool table
+DROP TABLE IF EXISTS ai_model_keys CASCADE;
@@ -0,0 +1,25 @@
+-- Create provider key pool table
+CREATE TABLE IF NOT EXISTS ai_model_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    model_id UUID NOT NULL REFERENCES ai_models(id) ON DELETE CASCADE,
+    label VARCHAR(255),
+    key_hint VARCHAR(16),
+    encrypted_key TEXT,
+    weight INTEGER DEFAULT 1,
+    status VARCHAR(50) DEFAULT 'active',
+    quarantined_until TIMESTAMP,
+    failure_count INTEGER DEFAULT 0,
+    last_failure_at TIMESTAMP,
+    last_rate_limited_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai_model_keys_model_id ON ai_model_keys(model_id);
+
+-- Seed each model's pool with its existing key, unless it already has a pool
+INSERT INTO ai_model_keys (model_id, label, encrypted_key, weight, status, created_at, updated_at)
+SELECT id, 'primary', encrypted_api_key, 1, 'active', created_at, NOW()
+FROM ai_models
+WHERE encrypted_api_key IS NOT NULL AND encrypted_api_key <> ''
+  AND NOT EXISTS (SELECT 1 FROM ai_model_keys WHERE ai_model_keys.model_id = ai_models.id);
@@ -0,0 +1,2 @@
+-- Drop model secret backend
+ALTER TABLE ai_models DROP COLUMN IF EXISTS secret_backend;
@@ -0,0 +1,3 @@
+-- Where each model's provider keys are stored: local (encrypted in this database),
+-- vault_kv (Vault KV v2 path in the key columns) or vault_transit (Vault transit ciphertext)
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS secret_backend VARCHAR(50) NOT NULL DEFAULT 'local';
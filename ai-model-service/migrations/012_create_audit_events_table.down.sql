@@ -0,0 +1,3 @@
+-- Drop the audit log
+DROP TABLE IF EXISTS ai_audit_events CASCADE;
+DROP FUNCTION IF EXISTS ai_audit_events_append_only();
@@ -0,0 +1,38 @@
+-- Append-only audit log of model configuration changes and credential access.
+-- Each row's hash covers its content and the previous row's hash (see entities.AuditEvent).
+CREATE TABLE IF NOT EXISTS ai_audit_events (
+    id UUID PRIMARY KEY,
+    sequence BIGSERIAL NOT NULL UNIQUE,
+    tenant_id VARCHAR(64),
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    resource_type VARCHAR(64) NOT NULL,
+    resource_id VARCHAR(255) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '[]',
+    source_ip VARCHAR(64),
+    metadata JSONB NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMPTZ NOT NULL,
+    prev_hash VARCHAR(64) NOT NULL DEFAULT '',
+    hash VARCHAR(64) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai_audit_events_tenant_occurred ON ai_audit_events(tenant_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_ai_audit_events_resource ON ai_audit_events(resource_type, resource_id);
+CREATE INDEX IF NOT EXISTS idx_ai_audit_events_actor ON ai_audit_events(actor);
+
+-- Rows can be added but never changed or removed
+CREATE OR REPLACE FUNCTION ai_audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'ai_audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS ai_audit_events_no_update ON ai_audit_events;
+CREATE TRIGGER ai_audit_events_no_update
+    BEFORE UPDATE OR DELETE ON ai_audit_events
+    FOR EACH ROW EXECUTE FUNCTION ai_audit_events_append_only();
+
+DROP TRIGGER IF EXISTS ai_audit_events_no_truncate ON ai_audit_events;
+CREATE TRIGGER ai_audit_events_no_truncate
+    BEFORE TRUNCATE ON ai_audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION ai_audit_events_append_only();
@@ -0,0 +1,2 @@
+-- gen_random_uuid() is the default every earlier migration declares, so there is nothing to revert
+SELECT 1;
@@ -0,0 +1,27 @@
+-- Generate IDs with the built-in gen_random_uuid(). Tables created by GORM AutoMigrate
+-- defaulted to uuid_generate_v4(), which needs the uuid-ossp extension.
+ALTER TABLE ai_models ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE ai_usage_logs ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE ai_api_keys ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE ai_tenants ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE ai_projects ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE ai_budgets ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE ai_budget_alerts ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE ai_model_pricing ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE ai_model_keys ALTER COLUMN id SET DEFAULT gen_random_uuid();
+
+-- vault_path was replaced by encrypted_api_key (003) but survives in databases
+-- created before that migration was applied
+ALTER TABLE ai_models DROP COLUMN IF EXISTS vault_path;
+
+-- AutoMigrate added cost_per1k_tokens next to the cost_per_1k_tokens column from 001;
+-- keep whichever value was written and drop the duplicate
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'ai_models' AND column_name = 'cost_per1k_tokens') THEN
+        UPDATE ai_models SET cost_per_1k_tokens = cost_per1k_tokens
+        WHERE cost_per_1k_tokens IS NULL AND cost_per1k_tokens IS NOT NULL;
+        ALTER TABLE ai_models DROP COLUMN cost_per1k_tokens;
+    END IF;
+END $$;
@@ -0,0 +1,2 @@
+-- Drop model source
+ALTER TABLE ai_models DROP COLUMN IF EXISTS source;
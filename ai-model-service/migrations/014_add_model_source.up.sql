@@ -0,0 +1,3 @@
+-- Record whether a model is managed through the API or by the model catalog.
+-- Only catalog models are deprecated when they are removed from the catalog.
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS source VARCHAR(50) NOT NULL DEFAULT 'api';
@@ -0,0 +1,8 @@
+-- Drop model capabilities and limits
+ALTER TABLE ai_models DROP COLUMN IF EXISTS fallback_models;
+ALTER TABLE ai_models DROP COLUMN IF EXISTS max_output_tokens;
+ALTER TABLE ai_models DROP COLUMN IF EXISTS context_window;
+ALTER TABLE ai_models DROP COLUMN IF EXISTS supports_streaming;
+ALTER TABLE ai_models DROP COLUMN IF EXISTS supports_json_mode;
+ALTER TABLE ai_models DROP COLUMN IF EXISTS supports_vision;
+ALTER TABLE ai_models DROP COLUMN IF EXISTS supports_tools;
@@ -0,0 +1,18 @@
+-- Typed capabilities and token limits per model, and the models to fall back to
+-- when a request needs more than a model supports. A limit of 0 is not enforced.
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS supports_tools BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS supports_vision BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS supports_json_mode BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS supports_streaming BOOLEAN NOT NULL DEFAULT true;
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS context_window INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS max_output_tokens INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS fallback_models JSONB NOT NULL DEFAULT '[]';
+
+-- Move the capabilities the model catalog kept in config into the typed columns
+UPDATE ai_models SET
+    supports_tools = 'tools' = ANY(string_to_array(config->>'capabilities', ',')),
+    supports_vision = 'vision' = ANY(string_to_array(config->>'capabilities', ',')),
+    supports_json_mode = 'json_mode' = ANY(string_to_array(config->>'capabilities', ',')),
+    supports_streaming = 'streaming' = ANY(string_to_array(config->>'capabilities', ',')),
+    config = config - 'capabilities'
+WHERE config ? 'capabilities';
@@ -0,0 +1,3 @@
+-- Drop model aliases and their history
+DROP TABLE IF EXISTS ai_model_alias_history CASCADE;
+DROP TABLE IF EXISTS ai_model_aliases CASCADE;
@@ -0,0 +1,26 @@
+-- Create model aliases: stable names resolving to a concrete model. A model cannot be
+-- deleted while an alias points to it.
+CREATE TABLE IF NOT EXISTS ai_model_aliases (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    tenant_id UUID REFERENCES ai_tenants(id) ON DELETE CASCADE,
+    model_id UUID NOT NULL REFERENCES ai_models(id),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai_model_aliases_tenant_id ON ai_model_aliases(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_ai_model_aliases_model_id ON ai_model_aliases(model_id);
+
+-- Every change of the model an alias points to. Entries outlive the alias and its models.
+CREATE TABLE IF NOT EXISTS ai_model_alias_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    alias_name VARCHAR(255) NOT NULL,
+    tenant_id UUID,
+    previous_model_id UUID,
+    model_id UUID,
+    actor VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai_model_alias_history_alias_name ON ai_model_alias_history(alias_name, created_at);
@@ -0,0 +1,4 @@
+-- Drop model alias routes
+ALTER TABLE ai_usage_logs DROP COLUMN IF EXISTS alias;
+ALTER TABLE ai_model_alias_history DROP COLUMN IF EXISTS routes;
+ALTER TABLE ai_model_aliases DROP COLUMN IF EXISTS routes;
@@ -0,0 +1,7 @@
+-- Weighted routes split an alias's traffic between models, e.g. for a canary rollout.
+-- Each route is {"model_id": ..., "weight": percent}; the rest goes to the alias's model.
+ALTER TABLE ai_model_aliases ADD COLUMN IF NOT EXISTS routes JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE ai_model_alias_history ADD COLUMN IF NOT EXISTS routes JSONB NOT NULL DEFAULT '[]';
+
+-- The alias a request named, so the models behind its routes can be compared
+ALTER TABLE ai_usage_logs ADD COLUMN IF NOT EXISTS alias VARCHAR(255);
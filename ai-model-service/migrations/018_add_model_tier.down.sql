@@ -0,0 +1,3 @@
+-- Drop model tiers
+DROP INDEX IF EXISTS idx_ai_models_tier;
+ALTER TABLE ai_models DROP COLUMN IF EXISTS tier;
@@ -0,0 +1,5 @@
+-- Capability tier of a model (cheap, balanced or best). Callers can ask the proxy for a tier
+-- instead of a model; models without a tier are never picked that way.
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS tier VARCHAR(20) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_ai_models_tier ON ai_models(tier);
@@ -0,0 +1,7 @@
+-- Drop model deprecation
+DROP INDEX IF EXISTS idx_ai_usage_logs_requested_model_id;
+ALTER TABLE ai_usage_logs DROP COLUMN IF EXISTS requested_model_id;
+
+ALTER TABLE ai_models DROP COLUMN IF EXISTS replacement_id;
+ALTER TABLE ai_models DROP COLUMN IF EXISTS sunset_at;
+ALTER TABLE ai_models DROP COLUMN IF EXISTS deprecated_at;
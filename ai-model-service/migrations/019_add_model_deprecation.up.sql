@@ -0,0 +1,10 @@
+-- Deprecation of a model: when it was announced, when the model is sunset and the model
+-- callers are moved to from then on
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS deprecated_at TIMESTAMPTZ;
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS sunset_at TIMESTAMPTZ;
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS replacement_id UUID REFERENCES ai_models(id) ON DELETE SET NULL;
+
+-- The deprecated model a request named when the proxy redirected it to the replacement
+ALTER TABLE ai_usage_logs ADD COLUMN IF NOT EXISTS requested_model_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_ai_usage_logs_requested_model_id ON ai_usage_logs(requested_model_id);
@@ -0,0 +1,13 @@
+-- Drop model soft deletion. Deleted models are removed for good, along with their usage.
+DROP TABLE IF EXISTS ai_usage_logs_archive;
+
+ALTER TABLE ai_usage_logs DROP CONSTRAINT IF EXISTS ai_usage_logs_model_id_fkey;
+ALTER TABLE ai_usage_logs ADD CONSTRAINT ai_usage_logs_model_id_fkey FOREIGN KEY (model_id) REFERENCES ai_models(id) ON DELETE CASCADE;
+
+DELETE FROM ai_models WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_ai_models_name_active;
+ALTER TABLE ai_models ADD CONSTRAINT ai_models_name_key UNIQUE (name);
+
+DROP INDEX IF EXISTS idx_ai_models_deleted_at;
+ALTER TABLE ai_models DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,20 @@
+-- Soft deletion: a deleted model keeps its row, and its usage, until it is purged
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_ai_models_deleted_at ON ai_models(deleted_at);
+
+-- Names only have to be unique among models that are not deleted
+ALTER TABLE ai_models DROP CONSTRAINT IF EXISTS ai_models_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ai_models_name_active ON ai_models(name) WHERE deleted_at IS NULL;
+
+-- Removing a model must never remove its billing history; the purge archives it first
+ALTER TABLE ai_usage_logs DROP CONSTRAINT IF EXISTS ai_usage_logs_model_id_fkey;
+ALTER TABLE ai_usage_logs ADD CONSTRAINT ai_usage_logs_model_id_fkey FOREIGN KEY (model_id) REFERENCES ai_models(id);
+
+-- Usage logs of purged models. Rows are copied as is, so columns added to ai_usage_logs
+-- must be added here too.
+CREATE TABLE IF NOT EXISTS ai_usage_logs_archive (LIKE ai_usage_logs INCLUDING DEFAULTS);
+
+CREATE INDEX IF NOT EXISTS idx_ai_usage_logs_archive_model_created ON ai_usage_logs_archive(model_id, created_at DESC);
+
+COMMENT ON TABLE ai_usage_logs_archive IS 'Usage logs of purged models, kept for billing';
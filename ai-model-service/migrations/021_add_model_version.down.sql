@@ -0,0 +1,2 @@
+-- Drop model versions
+ALTER TABLE ai_models DROP COLUMN IF EXISTS version;
@@ -0,0 +1,2 @@
+-- Version of a model, incremented by every change, so updates can compare-and-swap on it
+ALTER TABLE ai_models ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
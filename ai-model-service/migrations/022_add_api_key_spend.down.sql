@@ -0,0 +1,2 @@
+-- Drop the running spend of virtual API keys
+ALTER TABLE ai_api_keys DROP COLUMN IF EXISTS spent_usd;
@@ -0,0 +1,15 @@
+-- Running spend of each virtual API key, kept by usage logging so it survives log retention
+ALTER TABLE ai_api_keys ADD COLUMN IF NOT EXISTS spent_usd DECIMAL(14, 8) NOT NULL DEFAULT 0;
+
+UPDATE ai_api_keys k SET spent_usd = s.total
+FROM (
+    SELECT api_key_id, SUM(cost) AS total
+    FROM (
+        SELECT api_key_id, cost FROM ai_usage_logs
+        UNION ALL
+        SELECT api_key_id, cost FROM ai_usage_logs_archive
+    ) logs
+    WHERE api_key_id IS NOT NULL
+    GROUP BY api_key_id
+) s
+WHERE k.id = s.api_key_id;
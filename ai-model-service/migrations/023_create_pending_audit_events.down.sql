@@ -0,0 +1,2 @@
+-- Drop the pending audit events; any not yet chained are lost
+DROP TABLE IF EXISTS ai_audit_events_pending;
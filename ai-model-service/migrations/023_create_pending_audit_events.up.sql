@@ -0,0 +1,16 @@
+-- Credential reads are recorded here first, without taking the audit chain lock, and moved
+-- into ai_audit_events in batches by the audit chainer
+CREATE TABLE IF NOT EXISTS ai_audit_events_pending (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(64),
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    resource_type VARCHAR(64) NOT NULL,
+    resource_id VARCHAR(255) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '[]',
+    source_ip VARCHAR(64),
+    metadata JSONB NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai_audit_events_pending_occurred ON ai_audit_events_pending(occurred_at);
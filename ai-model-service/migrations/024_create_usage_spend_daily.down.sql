@@ -0,0 +1,2 @@
+-- Drop the daily project and user spend rollup
+DROP TABLE IF EXISTS ai_usage_spend_daily;
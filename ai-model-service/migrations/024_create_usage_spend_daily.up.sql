@@ -0,0 +1,39 @@
+-- Daily (UTC) spend per project and per user, so project and user budgets are served from a
+-- rollup rather than by scanning raw usage logs. tenant_id is the nil UUID for usage without
+-- a tenant so it can be part of the primary key.
+CREATE TABLE IF NOT EXISTS ai_usage_spend_daily (
+    bucket_start TIMESTAMP NOT NULL,
+    scope VARCHAR(16) NOT NULL,
+    scope_id UUID NOT NULL,
+    tenant_id UUID NOT NULL,
+    cost DECIMAL(14, 8) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (bucket_start, scope, scope_id, tenant_id)
+);
+
+-- Backfill from existing logs, including those archived when their model was purged
+INSERT INTO ai_usage_spend_daily (bucket_start, scope, scope_id, tenant_id, cost)
+SELECT date_trunc('day', created_at), 'project', project_id,
+       COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'), SUM(cost)
+FROM (
+    SELECT created_at, project_id, tenant_id, cost FROM ai_usage_logs
+    UNION ALL
+    SELECT created_at, project_id, tenant_id, cost FROM ai_usage_logs_archive
+) logs
+WHERE project_id IS NOT NULL AND cost <> 0
+GROUP BY 1, 2, 3, 4
+ON CONFLICT DO NOTHING;
+
+INSERT INTO ai_usage_spend_daily (bucket_start, scope, scope_id, tenant_id, cost)
+SELECT date_trunc('day', created_at), 'user', user_id,
+       COALESCE(tenant_id, '00000000-0000-0000-0000-000000000000'), SUM(cost)
+FROM (
+    SELECT created_at, user_id, tenant_id, cost FROM ai_usage_logs
+    UNION ALL
+    SELECT created_at, user_id, tenant_id, cost FROM ai_usage_logs_archive
+) logs
+WHERE user_id IS NOT NULL AND cost <> 0
+GROUP BY 1, 2, 3, 4
+ON CONFLICT DO NOTHING;
+
+COMMENT ON TABLE ai_usage_spend_daily IS 'Daily (UTC) spend per project and user, used for budgets';
@@ -0,0 +1,10 @@
+// Package migrations embeds the versioned SQL migrations, named
+// <version>_<name>.up.sql and <version>_<name>.down.sql
+package migrations
+
+import "embed"
+
+// Files holds every migration file
+//
+//go:embed *.sql
+var Files embed.FS
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/dto"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new virtual API key repository
+func NewAPIKeyRepository(db *gorm.DB) *apiKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// CreateAPIKey stores a new virtual API key by its hash
+func (r *apiKeyRepository) CreateAPIKey(ctx context.Context, payload *entities.CreateAPIKeyPayload, prefix, keyHash string) (*entities.APIKey, errors.BaseError) {
+	allowedJSON, _ := json.Marshal(payload.AllowedModels)
+
+	dtoKey := &dto.APIKey{
+		ID:            uuid.New(),
+		Name:          payload.Name,
+		Prefix:        prefix,
+		KeyHash:       keyHash,
+		TenantID:      parseOptionalUUID(payload.TenantID),
+		ProjectID:     parseOptionalUUID(payload.ProjectID),
+		UserID:        parseOptionalUUID(payload.UserID),
+		AllowedModels: string(allowedJSON),
+		ExpiresAt:     payload.ExpiresAt,
+		BudgetUSD:     payload.BudgetUSD,
+		Status:        string(entities.APIKeyStatusActive),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	if err := r.db.WithContext(ctx).Create(dtoKey).Error; err != nil {
+		return nil, errors.Internal(fmt.Errorf(constants.ErrFailedToCreateAPIKey, err))
+	}
+
+	return r.dtoToEntity(dtoKey), nil
+}
+
+// GetAPIKeyByHash retrieves a virtual API key by the hash of its plaintext
+func (r *apiKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*entities.APIKey, errors.BaseError) {
+	var dtoKey dto.APIKey
+	if err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&dtoKey).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound(constants.ErrAPIKeyNotFound)
+		}
+		return nil, errors.Internal(err)
+	}
+
+	return r.dtoToEntity(&dtoKey), nil
+}
+
+// ListAPIKeys lists virtual API keys with filtering
+func (r *apiKeyRepository) ListAPIKeys(ctx context.Context, filter *entities.APIKeyFilter) ([]*entities.APIKey, errors.BaseError) {
+	query := r.db.WithContext(ctx).Model(&dto.APIKey{})
+
+	if filter.TenantID != "" {
+		query = query.Where("tenant_id = ?", filter.TenantID)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+
+	var dtoKeys []dto.APIKey
+	if err := query.Order("created_at DESC").Find(&dtoKeys).Error; err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	keys := make([]*entities.APIKey, 0, len(dtoKeys))
+	for i := range dtoKeys {
+		keys = append(keys, r.dtoToEntity(&dtoKeys[i]))
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey marks a virtual API key as revoked, optionally restricted to a tenant
+func (r *apiKeyRepository) RevokeAPIKey(ctx context.Context, id, tenantID string) errors.BaseError {
+	keyUUID, err := uuid.Parse(id)
+	if err != nil {
+		return errors.BadRequest(constants.ErrInvalidAPIKeyID)
+	}
+
+	query := r.db.WithContext(ctx).Model(&dto.APIKey{}).Where("id = ?", keyUUID)
+	if tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+
+	result := query.Updates(map[string]interface{}{
+		"status":     string(entities.APIKeyStatusRevoked),
+		"updated_at": time.Now(),
+	})
+	if result.Error != nil {
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToRevokeAPIKey, result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.NotFound(constants.ErrAPIKeyNotFound)
+	}
+
+	return nil
+}
+
+// TouchAPIKey records the last time a virtual API key was used
+func (r *apiKeyRepository) TouchAPIKey(ctx context.Context, id string) errors.BaseError {
+	if err := r.db.WithContext(ctx).Model(&dto.APIKey{}).
+		Where("id = ?", id).
+		Update("last_used_at", time.Now()).Error; err != nil {
+		return errors.Internal(err)
+	}
+	return nil
+}
+
+// Helper: Convert DTO to Entity
+func (r *apiKeyRepository) dtoToEntity(dtoKey *dto.APIKey) *entities.APIKey {
+	var allowedModels []string
+	if err := json.Unmarshal([]byte(dtoKey.AllowedModels), &allowedModels); err != nil {
+		allowedModels = []string{}
+	}
+
+	return &entities.APIKey{
+		ID:            dtoKey.ID.String(),
+		Name:          dtoKey.Name,
+		Prefix:        dtoKey.Prefix,
+		KeyHash:       dtoKey.KeyHash,
+		TenantID:      uuidString(dtoKey.TenantID),
+		ProjectID:     uuidString(dtoKey.ProjectID),
+		UserID:        uuidString(dtoKey.UserID),
+		AllowedModels: allowedModels,
+		ExpiresAt:     dtoKey.ExpiresAt,
+		BudgetUSD:     dtoKey.BudgetUSD,
+		SpentUSD:      dtoKey.SpentUSD,
+		Status:        entities.APIKeyStatus(dtoKey.Status),
+		LastUsedAt:    dtoKey.LastUsedAt,
+		CreatedAt:     dtoKey.CreatedAt,
+		UpdatedAt:     dtoKey.UpdatedAt,
+	}
+}
@@ -0,0 +1,270 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/dto"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// auditChainLock is the advisory lock that serializes appends, so every event links to the one
+// before it even with several replicas writing
+const auditChainLock = 7_041_001
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new audit log repository
+func NewAuditRepository(db *gorm.DB) *auditRepository {
+	return &auditRepository{db: db}
+}
+
+// AppendAuditEvent chains an event to the last one and stores it, filling in its hashes
+func (r *auditRepository) AppendAuditEvent(ctx context.Context, event *entities.AuditEvent) errors.BaseError {
+	eventUUID, err := uuid.Parse(event.ID)
+	if err != nil {
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToRecordAudit, err))
+	}
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToRecordAudit, err))
+	}
+	metadata, err := json.Marshal(event.RequestMetadata)
+	if err != nil {
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToRecordAudit, err))
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLock).Error; err != nil {
+			return err
+		}
+
+		var last dto.AuditEvent
+		if err := tx.Select("hash").Order("sequence DESC").Limit(1).Find(&last).Error; err != nil {
+			return err
+		}
+		hash, err := event.ComputeHash(last.Hash)
+		if err != nil {
+			return err
+		}
+
+		row := &dto.AuditEvent{
+			ID:           eventUUID,
+			TenantID:     event.TenantID,
+			Actor:        event.Actor,
+			Action:       string(event.Action),
+			ResourceType: event.ResourceType,
+			ResourceID:   event.ResourceID,
+			Changes:      string(changes),
+			SourceIP:     event.SourceIP,
+			Metadata:     string(metadata),
+			OccurredAt:   event.OccurredAt,
+			PrevHash:     last.Hash,
+			Hash:         hash,
+		}
+		if err := tx.Omit("Sequence").Create(row).Error; err != nil {
+			return err
+		}
+		event.PrevHash = last.Hash
+		event.Hash = hash
+		return nil
+	})
+	if err != nil {
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToRecordAudit, err))
+	}
+	return nil
+}
+
+// QueueAuditEvent stores an event without chaining it, so the write never waits on the chain
+// lock. ChainQueuedAuditEvents appends it to the chain later.
+func (r *auditRepository) QueueAuditEvent(ctx context.Context, event *entities.AuditEvent) errors.BaseError {
+	eventUUID, err := uuid.Parse(event.ID)
+	if err != nil {
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToRecordAudit, err))
+	}
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToRecordAudit, err))
+	}
+	metadata, err := json.Marshal(event.RequestMetadata)
+	if err != nil {
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToRecordAudit, err))
+	}
+
+	row := &dto.PendingAuditEvent{
+		ID:           eventUUID,
+		TenantID:     event.TenantID,
+		Actor:        event.Actor,
+		Action:       string(event.Action),
+		ResourceType: event.ResourceType,
+		ResourceID:   event.ResourceID,
+		Changes:      string(changes),
+		SourceIP:     event.SourceIP,
+		Metadata:     string(metadata),
+		OccurredAt:   event.OccurredAt,
+	}
+	if err := r.db.WithContext(ctx).Create(row).Error; err != nil {
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToRecordAudit, err))
+	}
+	return nil
+}
+
+// ChainQueuedAuditEvents appends up to limit queued events to the chain, oldest first, under a
+// single hold of the chain lock, and returns how many it appended
+func (r *auditRepository) ChainQueuedAuditEvents(ctx context.Context, limit int) (int, errors.BaseError) {
+	var chained int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLock).Error; err != nil {
+			return err
+		}
+
+		var pending []dto.PendingAuditEvent
+		if err := tx.Order("occurred_at ASC, id ASC").Limit(limit).Find(&pending).Error; err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		var last dto.AuditEvent
+		if err := tx.Select("hash").Order("sequence DESC").Limit(1).Find(&last).Error; err != nil {
+			return err
+		}
+
+		rows := make([]*dto.AuditEvent, 0, len(pending))
+		ids := make([]uuid.UUID, 0, len(pending))
+		prevHash := last.Hash
+		for i := range pending {
+			row := &dto.AuditEvent{
+				ID:           pending[i].ID,
+				TenantID:     pending[i].TenantID,
+				Actor:        pending[i].Actor,
+				Action:       pending[i].Action,
+				ResourceType: pending[i].ResourceType,
+				ResourceID:   pending[i].ResourceID,
+				Changes:      pending[i].Changes,
+				SourceIP:     pending[i].SourceIP,
+				Metadata:     pending[i].Metadata,
+				OccurredAt:   pending[i].OccurredAt,
+				PrevHash:     prevHash,
+			}
+			// Hash the event as VerifyAuditChain will read it back
+			hash, err := auditEventToEntity(row).ComputeHash(prevHash)
+			if err != nil {
+				return err
+			}
+			row.Hash = hash
+			prevHash = hash
+			rows = append(rows, row)
+			ids = append(ids, pending[i].ID)
+		}
+
+		if err := tx.Omit("Sequence").Create(rows).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id IN ?", ids).Delete(&dto.PendingAuditEvent{}).Error; err != nil {
+			return err
+		}
+		chained = len(rows)
+		return nil
+	})
+	if err != nil {
+		return 0, errors.Internal(fmt.Errorf(constants.ErrFailedToRecordAudit, err))
+	}
+	return chained, nil
+}
+
+// ListAuditEvents lists audit events matching the filter, newest first
+func (r *auditRepository) ListAuditEvents(ctx context.Context, filter *entities.AuditEventFilter) ([]*entities.AuditEvent, int64, errors.BaseError) {
+	query := r.db.WithContext(ctx).Model(&dto.AuditEvent{})
+
+	// Apply filters
+	if filter.TenantID != "" {
+		query = query.Where("tenant_id = ?", filter.TenantID)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", string(filter.Action))
+	}
+	if filter.ResourceType != "" {
+		query = query.Where("resource_type = ?", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		query = query.Where("resource_id = ?", filter.ResourceID)
+	}
+	if !filter.StartTime.IsZero() {
+		query = query.Where("occurred_at >= ?", filter.StartTime)
+	}
+	if !filter.EndTime.IsZero() {
+		query = query.Where("occurred_at < ?", filter.EndTime)
+	}
+
+	// Count total
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, errors.Internal(err)
+	}
+
+	// Apply pagination
+	if filter.Page > 0 && filter.PageSize > 0 {
+		offset := (filter.Page - 1) * filter.PageSize
+		query = query.Offset(int(offset)).Limit(int(filter.PageSize))
+	}
+
+	var rows []dto.AuditEvent
+	if err := query.Order("sequence DESC").Find(&rows).Error; err != nil {
+		return nil, 0, errors.Internal(err)
+	}
+	return auditEventsToEntities(rows), total, nil
+}
+
+// ListAuditChain returns up to limit events after the given sequence, in chain order
+func (r *auditRepository) ListAuditChain(ctx context.Context, afterSequence int64, limit int) ([]*entities.AuditEvent, errors.BaseError) {
+	var rows []dto.AuditEvent
+	if err := r.db.WithContext(ctx).
+		Where("sequence > ?", afterSequence).
+		Order("sequence ASC").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, errors.Internal(err)
+	}
+	return auditEventsToEntities(rows), nil
+}
+
+func auditEventsToEntities(rows []dto.AuditEvent) []*entities.AuditEvent {
+	events := make([]*entities.AuditEvent, 0, len(rows))
+	for i := range rows {
+		events = append(events, auditEventToEntity(&rows[i]))
+	}
+	return events
+}
+
+// auditEventToEntity converts a row; malformed JSON columns are left empty so the hash check
+// reports the event rather than the listing failing
+func auditEventToEntity(row *dto.AuditEvent) *entities.AuditEvent {
+	event := &entities.AuditEvent{
+		ID:           row.ID.String(),
+		Sequence:     row.Sequence,
+		TenantID:     row.TenantID,
+		Actor:        row.Actor,
+		Action:       entities.AuditAction(row.Action),
+		ResourceType: row.ResourceType,
+		ResourceID:   row.ResourceID,
+		SourceIP:     row.SourceIP,
+		OccurredAt:   row.OccurredAt,
+		PrevHash:     row.PrevHash,
+		Hash:         row.Hash,
+	}
+	_ = json.Unmarshal([]byte(row.Changes), &event.Changes)
+	_ = json.Unmarshal([]byte(row.Metadata), &event.RequestMetadata)
+	return event
+}
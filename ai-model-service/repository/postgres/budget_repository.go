@@ -0,0 +1,229 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/dto"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type budgetRepository struct {
+	db *gorm.DB
+}
+
+// NewBudgetRepository creates a new budget repository
+func NewBudgetRepository(db *gorm.DB) *budgetRepository {
+	return &budgetRepository{db: db}
+}
+
+// CreateBudget stores a new USD budget
+func (r *budgetRepository) CreateBudget(ctx context.Context, payload *entities.CreateBudgetPayload) (*entities.Budget, errors.BaseError) {
+	if parseOptionalUUID(payload.ScopeID) == nil {
+		return nil, errors.BadRequest(constants.ErrInvalidBudgetScopeID)
+	}
+
+	thresholdsJSON, _ := json.Marshal(payload.Thresholds)
+
+	dtoBudget := &dto.Budget{
+		ID:         uuid.New(),
+		TenantID:   parseOptionalUUID(payload.TenantID),
+		Scope:      string(payload.Scope),
+		ScopeID:    payload.ScopeID,
+		Period:     string(payload.Period),
+		LimitUSD:   payload.LimitUSD,
+		Thresholds: string(thresholdsJSON),
+		WebhookURL: payload.WebhookURL,
+		HardStop:   payload.HardStop,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	if err := r.db.WithContext(ctx).Create(dtoBudget).Error; err != nil {
+		return nil, errors.Internal(fmt.Errorf(constants.ErrFailedToCreateBudget, err))
+	}
+
+	return r.dtoToEntity(dtoBudget), nil
+}
+
+// ListBudgets lists budgets, optionally restricted to a tenant
+func (r *budgetRepository) ListBudgets(ctx context.Context, tenantID string) ([]*entities.Budget, errors.BaseError) {
+	query := r.db.WithContext(ctx).Model(&dto.Budget{})
+	if tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+
+	var dtoBudgets []dto.Budget
+	if err := query.Order("created_at DESC").Find(&dtoBudgets).Error; err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	budgets := make([]*entities.Budget, len(dtoBudgets))
+	for i := range dtoBudgets {
+		budgets[i] = r.dtoToEntity(&dtoBudgets[i])
+	}
+
+	return budgets, nil
+}
+
+// DeleteBudget deletes a budget. A non-empty tenantID restricts the delete to that tenant's budgets.
+func (r *budgetRepository) DeleteBudget(ctx context.Context, id, tenantID string) errors.BaseError {
+	budgetUUID, err := uuid.Parse(id)
+	if err != nil {
+		return errors.BadRequest(constants.ErrInvalidBudgetID)
+	}
+
+	query := r.db.WithContext(ctx).Where("id = ?", budgetUUID)
+	if tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+
+	result := query.Delete(&dto.Budget{})
+	if result.Error != nil {
+		return errors.Internal(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.NotFound(constants.ErrBudgetNotFound)
+	}
+
+	return nil
+}
+
+// FindApplicableBudgets returns every budget that limits spend for the given request context.
+// User and model budgets owned by a tenant only apply within that tenant; platform ones apply globally.
+func (r *budgetRepository) FindApplicableBudgets(ctx context.Context, query *entities.BudgetQuery) ([]*entities.Budget, errors.BaseError) {
+	var conditions []string
+	var args []interface{}
+
+	if query.TenantID != "" {
+		conditions = append(conditions, "(scope = ? AND scope_id = ?)")
+		args = append(args, string(entities.BudgetScopeTenant), query.TenantID)
+	}
+	if query.ProjectID != "" {
+		conditions = append(conditions, "(scope = ? AND scope_id = ?)")
+		args = append(args, string(entities.BudgetScopeProject), query.ProjectID)
+	}
+	scoped := []struct {
+		scope   entities.BudgetScope
+		scopeID string
+	}{
+		{entities.BudgetScopeUser, query.UserID},
+		{entities.BudgetScopeModel, query.ModelID},
+	}
+	for _, s := range scoped {
+		if s.scopeID == "" {
+			continue
+		}
+		if query.TenantID != "" {
+			conditions = append(conditions, "(scope = ? AND scope_id = ? AND (tenant_id IS NULL OR tenant_id = ?))")
+			args = append(args, string(s.scope), s.scopeID, query.TenantID)
+		} else {
+			conditions = append(conditions, "(scope = ? AND scope_id = ? AND tenant_id IS NULL)")
+			args = append(args, string(s.scope), s.scopeID)
+		}
+	}
+
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	var dtoBudgets []dto.Budget
+	if err := r.db.WithContext(ctx).
+		Where(strings.Join(conditions, " OR "), args...).
+		Order("created_at").
+		Find(&dtoBudgets).Error; err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	budgets := make([]*entities.Budget, len(dtoBudgets))
+	for i := range dtoBudgets {
+		budgets[i] = r.dtoToEntity(&dtoBudgets[i])
+	}
+
+	return budgets, nil
+}
+
+// GetBudgetSpend sums the usage cost counted against a budget in [start, end), which must be
+// whole UTC days. Tenant and model spend comes from the daily usage rollup, project and user
+// spend from the daily spend rollup, so neither depends on raw log retention.
+func (r *budgetRepository) GetBudgetSpend(ctx context.Context, budget *entities.Budget, start, end time.Time) (decimal.Decimal, errors.BaseError) {
+	var query *gorm.DB
+	switch budget.Scope {
+	case entities.BudgetScopeTenant:
+		query = r.db.WithContext(ctx).Model(&dto.DailyUsage{}).Where("tenant_id = ?", budget.ScopeID)
+	case entities.BudgetScopeModel:
+		query = r.db.WithContext(ctx).Model(&dto.DailyUsage{}).Where("model_id = ?", budget.ScopeID)
+	case entities.BudgetScopeProject, entities.BudgetScopeUser:
+		query = r.db.WithContext(ctx).Model(&dto.DailySpend{}).
+			Where("scope = ? AND scope_id = ?", string(budget.Scope), budget.ScopeID)
+	default:
+		return decimal.Zero, errors.BadRequest(constants.ErrInvalidBudgetScope)
+	}
+	query = query.Where("bucket_start >= ? AND bucket_start < ?", start, end)
+	if budget.TenantID != "" && budget.Scope != entities.BudgetScopeTenant {
+		query = query.Where("tenant_id = ?", budget.TenantID)
+	}
+
+	var spent decimal.Decimal
+	if err := query.Select("COALESCE(SUM(cost), 0)").Scan(&spent).Error; err != nil {
+		return decimal.Zero, errors.Internal(fmt.Errorf(constants.ErrFailedToGetUsage, err))
+	}
+
+	return spent, nil
+}
+
+// RecordBudgetAlert marks a threshold as alerted for a budget period.
+// It returns false when the alert was already recorded, so each threshold fires once per period.
+func (r *budgetRepository) RecordBudgetAlert(ctx context.Context, budgetID string, periodStart time.Time, threshold int32, spent decimal.Decimal) (bool, errors.BaseError) {
+	budgetUUID, err := uuid.Parse(budgetID)
+	if err != nil {
+		return false, errors.BadRequest(constants.ErrInvalidBudgetID)
+	}
+
+	alert := &dto.BudgetAlert{
+		ID:          uuid.New(),
+		BudgetID:    budgetUUID,
+		PeriodStart: periodStart,
+		Threshold:   threshold,
+		SpentUSD:    spent,
+		CreatedAt:   time.Now(),
+	}
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(alert)
+	if result.Error != nil {
+		return false, errors.Internal(result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Helper: Convert DTO to Entity
+func (r *budgetRepository) dtoToEntity(dtoBudget *dto.Budget) *entities.Budget {
+	var thresholds []int32
+	if err := json.Unmarshal([]byte(dtoBudget.Thresholds), &thresholds); err != nil || len(thresholds) == 0 {
+		thresholds = entities.DefaultBudgetThresholds
+	}
+
+	return &entities.Budget{
+		ID:         dtoBudget.ID.String(),
+		TenantID:   uuidString(dtoBudget.TenantID),
+		Scope:      entities.BudgetScope(dtoBudget.Scope),
+		ScopeID:    dtoBudget.ScopeID,
+		Period:     entities.BudgetPeriod(dtoBudget.Period),
+		LimitUSD:   dtoBudget.LimitUSD,
+		Thresholds: thresholds,
+		WebhookURL: dtoBudget.WebhookURL,
+		HardStop:   dtoBudget.HardStop,
+		CreatedAt:  dtoBudget.CreatedAt,
+		UpdatedAt:  dtoBudget.UpdatedAt,
+	}
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/google/uuid"
+)
+
+// deprecatedModelCallerRow is the scan target of the deprecated model callers report
+type deprecatedModelCallerRow struct {
+	ModelID         uuid.UUID
+	ModelName       string
+	SunsetAt        *time.Time
+	ReplacementID   *uuid.UUID
+	TenantID        *uuid.UUID
+	ProjectID       *uuid.UUID
+	UserID          *uuid.UUID
+	APIKeyID        *uuid.UUID `gorm:"column:api_key_id"`
+	RequestCount    int64
+	RedirectedCount int64
+	LastCalledAt    time.Time
+}
+
+// GetDeprecatedModelCallers reports who called deprecated models since the filter's start time,
+// per model, tenant, project, user and API key, most active first. Calls redirected to the
+// replacement after the sunset count for the deprecated model they named.
+func (r *modelRepository) GetDeprecatedModelCallers(ctx context.Context, filter *entities.DeprecatedModelCallerFilter) ([]*entities.DeprecatedModelCaller, errors.BaseError) {
+	query := r.db.WithContext(ctx).
+		Table("ai_usage_logs").
+		Select(`ai_models.id AS model_id, ai_models.name AS model_name, ai_models.sunset_at, ai_models.replacement_id,
+			ai_usage_logs.tenant_id, ai_usage_logs.project_id, ai_usage_logs.user_id, ai_usage_logs.api_key_id,
+			COUNT(*) AS request_count,
+			COUNT(*) FILTER (WHERE ai_usage_logs.requested_model_id IS NOT NULL) AS redirected_count,
+			MAX(ai_usage_logs.created_at) AS last_called_at`).
+		Joins("JOIN ai_models ON ai_models.id = COALESCE(ai_usage_logs.requested_model_id, ai_usage_logs.model_id)").
+		Where("ai_models.status = ? AND ai_usage_logs.created_at >= ?", string(entities.ModelStatusDeprecated), filter.StartTime)
+
+	if filter.TenantID != "" {
+		query = query.Where("ai_usage_logs.tenant_id = ?", filter.TenantID)
+	}
+	if filter.ModelID != "" {
+		modelUUID, err := uuid.Parse(filter.ModelID)
+		if err != nil {
+			return nil, errors.BadRequest(constants.ErrInvalidModelID)
+		}
+		query = query.Where("ai_models.id = ?", modelUUID)
+	}
+
+	var rows []deprecatedModelCallerRow
+	if err := query.
+		Group("ai_models.id, ai_models.name, ai_models.sunset_at, ai_models.replacement_id, " +
+			"ai_usage_logs.tenant_id, ai_usage_logs.project_id, ai_usage_logs.user_id, ai_usage_logs.api_key_id").
+		Order("ai_models.name, request_count DESC").
+		Limit(maxUsageStatsRows).
+		Scan(&rows).Error; err != nil {
+		return nil, errors.Internal(fmt.Errorf(constants.ErrFailedToGetUsage, err))
+	}
+
+	callers := make([]*entities.DeprecatedModelCaller, len(rows))
+	for i, row := range rows {
+		callers[i] = &entities.DeprecatedModelCaller{
+			ModelID:         row.ModelID.String(),
+			ModelName:       row.ModelName,
+			SunsetAt:        row.SunsetAt,
+			ReplacementID:   uuidString(row.ReplacementID),
+			TenantID:        uuidString(row.TenantID),
+			ProjectID:       uuidString(row.ProjectID),
+			UserID:          uuidString(row.UserID),
+			APIKeyID:        uuidString(row.APIKeyID),
+			RequestCount:    row.RequestCount,
+			RedirectedCount: row.RedirectedCount,
+			LastCalledAt:    row.LastCalledAt,
+		}
+	}
+	return callers, nil
+}
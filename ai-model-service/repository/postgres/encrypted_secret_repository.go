@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/dto"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type encryptedSecretRepository struct {
+	db *gorm.DB
+}
+
+// NewEncryptedSecretRepository creates a repository over every stored provider key column
+func NewEncryptedSecretRepository(db *gorm.DB) *encryptedSecretRepository {
+	return &encryptedSecretRepository{db: db}
+}
+
+// ListEncryptedSecrets lists model keys and pooled provider keys of models on the given backend.
+// Deleted models are included, so their keys still decrypt if they are restored.
+func (r *encryptedSecretRepository) ListEncryptedSecrets(ctx context.Context, backend entities.SecretBackend) ([]*entities.EncryptedSecret, errors.BaseError) {
+	return r.listSecrets(ctx, r.db.WithContext(ctx).Unscoped().Where("ai_models.secret_backend = ?", string(backend)))
+}
+
+// ListModelSecrets lists the stored keys of one model
+func (r *encryptedSecretRepository) ListModelSecrets(ctx context.Context, modelID string) ([]*entities.EncryptedSecret, errors.BaseError) {
+	modelUUID, err := uuid.Parse(modelID)
+	if err != nil {
+		return nil, errors.BadRequest(constants.ErrInvalidModelID)
+	}
+	return r.listSecrets(ctx, r.db.WithContext(ctx).Unscoped().Where("ai_models.id = ?", modelUUID))
+}
+
+// ListModelIDsBySecretBackend lists the models whose keys are kept in the given backend
+func (r *encryptedSecretRepository) ListModelIDsBySecretBackend(ctx context.Context, backend entities.SecretBackend) ([]string, errors.BaseError) {
+	var ids []uuid.UUID
+	if err := r.db.WithContext(ctx).Unscoped().Model(&dto.AIModel{}).
+		Where("secret_backend = ?", string(backend)).
+		Order("created_at").
+		Pluck("id", &ids).Error; err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	modelIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		modelIDs = append(modelIDs, id.String())
+	}
+	return modelIDs, nil
+}
+
+// ReplaceCiphertext swaps a secret's ciphertext if it has not changed since it was read.
+// It returns false when the row was updated concurrently (e.g. the key was rotated).
+func (r *encryptedSecretRepository) ReplaceCiphertext(ctx context.Context, secret *entities.EncryptedSecret, ciphertext string) (bool, errors.BaseError) {
+	replaced, err := replaceSecret(r.db.WithContext(ctx), secret, ciphertext)
+	if err != nil {
+		return false, errors.Internal(fmt.Errorf(constants.ErrFailedToRekey, err))
+	}
+	return replaced, nil
+}
+
+// SwitchSecretBackend moves a model to another secret backend, replacing every stored key in
+// one transaction. It fails with a conflict if the model's keys changed since they were listed.
+func (r *encryptedSecretRepository) SwitchSecretBackend(ctx context.Context, modelID string, from, to entities.SecretBackend, replacements []*entities.SecretReplacement) errors.BaseError {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&dto.AIModel{}).
+			Where("id = ? AND secret_backend = ?", modelID, string(from)).
+			Updates(map[string]interface{}{"secret_backend": string(to), "updated_at": time.Now(), "version": gorm.Expr("version + 1")})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.Conflict(constants.ErrSecretsChanged)
+		}
+
+		replacedKeys := int64(0)
+		for _, replacement := range replacements {
+			replaced, err := replaceSecret(tx, replacement.Secret, replacement.Stored)
+			if err != nil {
+				return err
+			}
+			if !replaced {
+				return errors.Conflict(constants.ErrSecretsChanged)
+			}
+			if replacement.Secret.Kind == entities.EncryptedSecretProviderKey {
+				replacedKeys++
+			}
+		}
+
+		// A key added while the migration ran would be left on the old backend
+		var storedKeys int64
+		if err := tx.Model(&dto.ProviderKey{}).
+			Where("model_id = ? AND encrypted_key IS NOT NULL AND encrypted_key <> ''", modelID).
+			Count(&storedKeys).Error; err != nil {
+			return err
+		}
+		if storedKeys != replacedKeys {
+			return errors.Conflict(constants.ErrSecretsChanged)
+		}
+		return nil
+	})
+	if err != nil {
+		if baseErr, ok := err.(errors.BaseError); ok {
+			return baseErr
+		}
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToUpdateModel, err))
+	}
+	return nil
+}
+
+func (r *encryptedSecretRepository) listSecrets(ctx context.Context, models *gorm.DB) ([]*entities.EncryptedSecret, errors.BaseError) {
+	var dtoModels []dto.AIModel
+	if err := models.Session(&gorm.Session{}).
+		Select("ai_models.id", "ai_models.encrypted_api_key").
+		Where("ai_models.encrypted_api_key IS NOT NULL AND ai_models.encrypted_api_key <> ''").
+		Find(&dtoModels).Error; err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	var keys []dto.ProviderKey
+	if err := models.Session(&gorm.Session{}).
+		Table("ai_model_keys").
+		Select("ai_model_keys.id", "ai_model_keys.model_id", "ai_model_keys.encrypted_key").
+		Joins("JOIN ai_models ON ai_models.id = ai_model_keys.model_id").
+		Where("ai_model_keys.encrypted_key IS NOT NULL AND ai_model_keys.encrypted_key <> ''").
+		Find(&keys).Error; err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	secrets := make([]*entities.EncryptedSecret, 0, len(dtoModels)+len(keys))
+	for _, model := range dtoModels {
+		secrets = append(secrets, &entities.EncryptedSecret{
+			Kind:       entities.EncryptedSecretModelKey,
+			ID:         model.ID.String(),
+			ModelID:    model.ID.String(),
+			Ciphertext: model.EncryptedAPIKey,
+		})
+	}
+	for _, key := range keys {
+		secrets = append(secrets, &entities.EncryptedSecret{
+			Kind:       entities.EncryptedSecretProviderKey,
+			ID:         key.ID.String(),
+			ModelID:    key.ModelID.String(),
+			Ciphertext: key.EncryptedKey,
+		})
+	}
+	return secrets, nil
+}
+
+// replaceSecret compare-and-swaps the stored value of a model or pool key
+func replaceSecret(db *gorm.DB, secret *entities.EncryptedSecret, stored string) (bool, error) {
+	var query *gorm.DB
+	switch secret.Kind {
+	case entities.EncryptedSecretModelKey:
+		query = db.Unscoped().Model(&dto.AIModel{}).
+			Where("id = ? AND encrypted_api_key = ?", secret.ID, secret.Ciphertext).
+			Updates(map[string]interface{}{"encrypted_api_key": stored, "updated_at": time.Now(), "version": gorm.Expr("version + 1")})
+	case entities.EncryptedSecretProviderKey:
+		query = db.Model(&dto.ProviderKey{}).
+			Where("id = ? AND encrypted_key = ?", secret.ID, secret.Ciphertext).
+			Updates(map[string]interface{}{"encrypted_key": stored, "updated_at": time.Now()})
+	default:
+		return false, fmt.Errorf("unknown secret kind: %s", secret.Kind)
+	}
+
+	if query.Error != nil {
+		return false, query.Error
+	}
+	return query.RowsAffected > 0, nil
+}
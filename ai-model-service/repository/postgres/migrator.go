@@ -0,0 +1,253 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"gorm.io/gorm"
+)
+
+// migrationLock is the advisory lock held while migrating, so replicas starting together
+// never apply the same migration twice
+const migrationLock = 7_042_001
+
+// migrationFilePattern matches "<version>_<name>.<up|down>.sql"
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// The schema version is kept in golang-migrate's table format, so databases migrated with the
+// migrate CLI carry on from their current version
+const createSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`
+
+type migrator struct {
+	db         *gorm.DB
+	migrations []*entities.Migration
+}
+
+// NewMigrator loads the migrations in files. Every version needs both an up and a down file.
+func NewMigrator(db *gorm.DB, files fs.FS) (*migrator, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*entities.Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s is not named <version>_<name>.up.sql or .down.sql", entry.Name())
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := fs.ReadFile(files, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &entities.Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	m := &migrator{db: db}
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		m.migrations = append(m.migrations, migration)
+	}
+	slices.SortFunc(m.migrations, func(a, b *entities.Migration) int {
+		return int(a.Version - b.Version)
+	})
+	return m, nil
+}
+
+// Status reports the applied version and which migrations are pending
+func (m *migrator) Status(ctx context.Context) (*entities.MigrationStatus, error) {
+	current, dirty, err := m.version(m.db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	status := &entities.MigrationStatus{Current: current, Dirty: dirty}
+	for _, migration := range m.migrations {
+		status.Latest = migration.Version
+		status.Migrations = append(status.Migrations, entities.MigrationState{
+			Version: migration.Version,
+			Name:    migration.Name,
+			Applied: migration.Version <= current,
+		})
+	}
+	return status, nil
+}
+
+// Up applies up to steps pending migrations, or all of them when steps <= 0, and returns
+// the ones applied. Each migration runs in its own transaction together with the version update.
+func (m *migrator) Up(ctx context.Context, steps int) ([]*entities.Migration, error) {
+	var applied []*entities.Migration
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		current, err := m.cleanVersion(conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if migration.Version <= current {
+				continue
+			}
+			if steps > 0 && len(applied) == steps {
+				break
+			}
+			if err := m.apply(conn, migration.Up, migration.Version); err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts up to steps applied migrations, newest first, and returns the ones reverted
+func (m *migrator) Down(ctx context.Context, steps int) ([]*entities.Migration, error) {
+	var reverted []*entities.Migration
+	err := m.locked(ctx, func(conn *gorm.DB) error {
+		current, err := m.cleanVersion(conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if migration.Version > current {
+				continue
+			}
+			var previous int64
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := m.apply(conn, migration.Down, previous); err != nil {
+				return fmt.Errorf("reverting migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Force records version as applied and clean without running anything, to adopt a database
+// whose schema was brought to that version by other means or to clear a dirty flag
+func (m *migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && !slices.ContainsFunc(m.migrations, func(migration *entities.Migration) bool {
+		return migration.Version == version
+	}) {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+	return m.locked(ctx, func(conn *gorm.DB) error {
+		return conn.Transaction(func(tx *gorm.DB) error {
+			return setVersion(tx, version)
+		})
+	})
+}
+
+// CheckSchema reports the columns of models that are missing from the database
+func (m *migrator) CheckSchema(ctx context.Context, models ...interface{}) ([]string, error) {
+	db := m.db.WithContext(ctx)
+	var missing []string
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, err
+		}
+		if !db.Migrator().HasTable(model) {
+			missing = append(missing, stmt.Schema.Table)
+			continue
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && !db.Migrator().HasColumn(model, field.DBName) {
+				missing = append(missing, stmt.Schema.Table+"."+field.DBName)
+			}
+		}
+	}
+	return missing, nil
+}
+
+// locked runs fn on a single connection holding the migration lock
+func (m *migrator) locked(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLock).Error; err != nil {
+			return err
+		}
+		defer conn.WithContext(context.WithoutCancel(ctx)).Exec("SELECT pg_advisory_unlock(?)", migrationLock)
+
+		if err := conn.Exec(createSchemaMigrations).Error; err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+// apply runs a migration script and moves the schema to version in one transaction.
+// The script goes straight to the driver: gorm would treat "?" (jsonb operators) and "@"
+// in it as placeholders.
+func (m *migrator) apply(conn *gorm.DB, script string, version int64) error {
+	return conn.Transaction(func(tx *gorm.DB) error {
+		if _, err := tx.Statement.ConnPool.ExecContext(tx.Statement.Context, script); err != nil {
+			return err
+		}
+		return setVersion(tx, version)
+	})
+}
+
+// cleanVersion returns the applied version, refusing to migrate a dirty database
+func (m *migrator) cleanVersion(conn *gorm.DB) (int64, error) {
+	current, dirty, err := m.version(conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("database is dirty at version %d: fix the schema by hand, then run migrate force", current)
+	}
+	return current, nil
+}
+
+// version reads the applied version; a database without schema_migrations is at version 0
+func (m *migrator) version(db *gorm.DB) (int64, bool, error) {
+	if !db.Migrator().HasTable("schema_migrations") {
+		return 0, false, nil
+	}
+	var row struct {
+		Version int64
+		Dirty   bool
+	}
+	if err := db.Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&row).Error; err != nil {
+		return 0, false, err
+	}
+	return row.Version, row.Dirty, nil
+}
+
+// setVersion stores the applied version; version 0 leaves the table empty
+func setVersion(tx *gorm.DB, version int64) error {
+	if err := tx.Exec("DELETE FROM schema_migrations").Error; err != nil {
+		return err
+	}
+	if version == 0 {
+		return nil
+	}
+	return tx.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (?, false)", version).Error
+}
@@ -0,0 +1,315 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/dto"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type modelAliasRepository struct {
+	db *gorm.DB
+}
+
+// NewModelAliasRepository creates a new model alias repository
+func NewModelAliasRepository(db *gorm.DB) *modelAliasRepository {
+	return &modelAliasRepository{db: db}
+}
+
+// modelAliasRow is an alias joined with the name of the model it points to
+type modelAliasRow struct {
+	dto.ModelAlias `gorm:"embedded"`
+	ModelName      string
+}
+
+// modelAliasRouteRow is the stored form of a weighted route
+type modelAliasRouteRow struct {
+	ModelID string `json:"model_id"`
+	Weight  int32  `json:"weight"`
+}
+
+// GetModelAlias retrieves an alias by name
+func (r *modelAliasRepository) GetModelAlias(ctx context.Context, name string) (*entities.ModelAlias, errors.BaseError) {
+	var rows []modelAliasRow
+	if err := r.aliases(ctx).Where("ai_model_aliases.name = ?", name).Limit(1).Scan(&rows).Error; err != nil {
+		return nil, errors.Internal(err)
+	}
+	if len(rows) == 0 {
+		return nil, errors.NotFound(constants.ErrModelAliasNotFound)
+	}
+
+	alias := modelAliasToEntity(&rows[0])
+	if err := r.nameRouteModels(ctx, []*entities.ModelAlias{alias}); err != nil {
+		return nil, err
+	}
+	return alias, nil
+}
+
+// ListModelAliases lists aliases by name. A tenant filter includes global aliases and a model
+// filter matches the aliases pointing or routing traffic to the model.
+func (r *modelAliasRepository) ListModelAliases(ctx context.Context, filter *entities.ModelAliasFilter) ([]*entities.ModelAlias, errors.BaseError) {
+	query := r.aliases(ctx)
+	if filter.TenantID != "" {
+		query = query.Where("ai_model_aliases.tenant_id IS NULL OR ai_model_aliases.tenant_id = ?", filter.TenantID)
+	}
+	if filter.ModelID != "" {
+		modelUUID, err := uuid.Parse(filter.ModelID)
+		if err != nil {
+			return nil, errors.BadRequest(constants.ErrInvalidModelID)
+		}
+		routeJSON, _ := json.Marshal([]map[string]string{{"model_id": modelUUID.String()}})
+		query = query.Where("ai_model_aliases.model_id = ? OR ai_model_aliases.routes @> ?::jsonb", modelUUID, string(routeJSON))
+	}
+
+	var rows []modelAliasRow
+	if err := query.Order("ai_model_aliases.name").Scan(&rows).Error; err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	aliases := make([]*entities.ModelAlias, len(rows))
+	for i := range rows {
+		aliases[i] = modelAliasToEntity(&rows[i])
+	}
+	if err := r.nameRouteModels(ctx, aliases); err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+// SetModelAlias creates an alias or re-points it at payload.ModelID with payload.Routes, and
+// records the change in the alias history in the same transaction. The alias row is locked, so
+// concurrent changes apply one after the other and each history entry names the model it replaced.
+// It returns the alias and the history entry of the change, which is nil when nothing changed.
+func (r *modelAliasRepository) SetModelAlias(ctx context.Context, payload *entities.SetModelAliasPayload) (*entities.ModelAlias, *entities.ModelAliasRevision, errors.BaseError) {
+	modelUUID, err := uuid.Parse(payload.ModelID)
+	if err != nil {
+		return nil, nil, errors.BadRequest(constants.ErrInvalidModelID)
+	}
+	routes := make([]modelAliasRouteRow, len(payload.Routes))
+	for i, route := range payload.Routes {
+		routeUUID, err := uuid.Parse(route.ModelID)
+		if err != nil {
+			return nil, nil, errors.BadRequest(constants.ErrInvalidModelID)
+		}
+		routes[i] = modelAliasRouteRow{ModelID: routeUUID.String(), Weight: route.Weight}
+	}
+	routesJSON, _ := json.Marshal(routes)
+
+	var revision *dto.ModelAliasRevision
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current dto.ModelAlias
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("name = ?", payload.Name).Limit(1).Find(&current)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		now := time.Now()
+		var previousModelID *uuid.UUID
+		if result.RowsAffected == 0 {
+			if payload.PreviousModelID != "" {
+				return errors.Conflict(fmt.Sprintf(constants.ErrModelAliasChanged, payload.PreviousModelID))
+			}
+			current = dto.ModelAlias{
+				ID:        uuid.New(),
+				Name:      payload.Name,
+				TenantID:  parseOptionalUUID(payload.TenantID),
+				ModelID:   modelUUID,
+				Routes:    string(routesJSON),
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			if err := tx.Create(&current).Error; err != nil {
+				return err
+			}
+		} else {
+			if uuidString(current.TenantID) != payload.TenantID {
+				return errors.NotFound(constants.ErrModelAliasNotFound)
+			}
+			if payload.PreviousModelID != "" && current.ModelID.String() != payload.PreviousModelID {
+				return errors.Conflict(fmt.Sprintf(constants.ErrModelAliasChanged, payload.PreviousModelID))
+			}
+			if current.ModelID == modelUUID && slices.Equal(parseModelAliasRoutes(current.Routes), routes) {
+				return nil
+			}
+			previous := current.ModelID
+			previousModelID = &previous
+			updates := map[string]interface{}{"model_id": modelUUID, "routes": string(routesJSON), "updated_at": now}
+			if err := tx.Model(&current).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		revision = &dto.ModelAliasRevision{
+			ID:              uuid.New(),
+			AliasName:       payload.Name,
+			TenantID:        current.TenantID,
+			PreviousModelID: previousModelID,
+			ModelID:         &modelUUID,
+			Routes:          string(routesJSON),
+			Actor:           payload.Actor,
+			CreatedAt:       now,
+		}
+		return tx.Create(revision).Error
+	})
+	if err != nil {
+		if baseErr, ok := err.(errors.BaseError); ok {
+			return nil, nil, baseErr
+		}
+		return nil, nil, errors.Internal(fmt.Errorf(constants.ErrFailedToSetModelAlias, err))
+	}
+
+	alias, getErr := r.GetModelAlias(ctx, payload.Name)
+	if getErr != nil {
+		return nil, nil, getErr
+	}
+	if revision == nil {
+		return alias, nil, nil
+	}
+	return alias, modelAliasRevisionToEntity(revision), nil
+}
+
+// DeleteModelAlias removes an alias and records its deletion in the alias history
+func (r *modelAliasRepository) DeleteModelAlias(ctx context.Context, alias *entities.ModelAlias, actor string) errors.BaseError {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current dto.ModelAlias
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("name = ?", alias.Name).Limit(1).Find(&current)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.NotFound(constants.ErrModelAliasNotFound)
+		}
+		if err := tx.Delete(&current).Error; err != nil {
+			return err
+		}
+
+		previous := current.ModelID
+		return tx.Create(&dto.ModelAliasRevision{
+			ID:              uuid.New(),
+			AliasName:       current.Name,
+			TenantID:        current.TenantID,
+			PreviousModelID: &previous,
+			Actor:           actor,
+			CreatedAt:       time.Now(),
+		}).Error
+	})
+	if err != nil {
+		if baseErr, ok := err.(errors.BaseError); ok {
+			return baseErr
+		}
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToSetModelAlias, err))
+	}
+	return nil
+}
+
+// ListModelAliasHistory lists the changes of an alias, newest first. Tenant-scoped callers
+// pass their tenant and only see the history of global aliases and their own.
+func (r *modelAliasRepository) ListModelAliasHistory(ctx context.Context, name, tenantID string) ([]*entities.ModelAliasRevision, errors.BaseError) {
+	query := r.db.WithContext(ctx).Where("alias_name = ?", name)
+	if tenantID != "" {
+		query = query.Where("tenant_id IS NULL OR tenant_id = ?", tenantID)
+	}
+
+	var dtoRevisions []dto.ModelAliasRevision
+	if err := query.Order("created_at DESC").Find(&dtoRevisions).Error; err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	revisions := make([]*entities.ModelAliasRevision, len(dtoRevisions))
+	for i := range dtoRevisions {
+		revisions[i] = modelAliasRevisionToEntity(&dtoRevisions[i])
+	}
+	return revisions, nil
+}
+
+// aliases selects aliases with the name of their model
+func (r *modelAliasRepository) aliases(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Table("ai_model_aliases").
+		Select("ai_model_aliases.*, ai_models.name AS model_name").
+		Joins("JOIN ai_models ON ai_models.id = ai_model_aliases.model_id")
+}
+
+// nameRouteModels fills in the model names of the aliases' routes
+func (r *modelAliasRepository) nameRouteModels(ctx context.Context, aliases []*entities.ModelAlias) errors.BaseError {
+	var modelIDs []string
+	for _, alias := range aliases {
+		for _, route := range alias.Routes {
+			modelIDs = append(modelIDs, route.ModelID)
+		}
+	}
+	if len(modelIDs) == 0 {
+		return nil
+	}
+
+	var models []dto.AIModel
+	if err := r.db.WithContext(ctx).Select("id", "name").Where("id IN ?", modelIDs).Find(&models).Error; err != nil {
+		return errors.Internal(err)
+	}
+	names := make(map[string]string, len(models))
+	for _, model := range models {
+		names[model.ID.String()] = model.Name
+	}
+	for _, alias := range aliases {
+		for i := range alias.Routes {
+			alias.Routes[i].ModelName = names[alias.Routes[i].ModelID]
+		}
+	}
+	return nil
+}
+
+func modelAliasToEntity(row *modelAliasRow) *entities.ModelAlias {
+	return &entities.ModelAlias{
+		ID:        row.ID.String(),
+		Name:      row.Name,
+		TenantID:  uuidString(row.TenantID),
+		ModelID:   row.ModelID.String(),
+		ModelName: row.ModelName,
+		Routes:    modelAliasRoutesToEntity(row.Routes),
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
+
+func modelAliasRevisionToEntity(revision *dto.ModelAliasRevision) *entities.ModelAliasRevision {
+	return &entities.ModelAliasRevision{
+		ID:              revision.ID.String(),
+		AliasName:       revision.AliasName,
+		TenantID:        uuidString(revision.TenantID),
+		PreviousModelID: uuidString(revision.PreviousModelID),
+		ModelID:         uuidString(revision.ModelID),
+		Routes:          modelAliasRoutesToEntity(revision.Routes),
+		Actor:           revision.Actor,
+		CreatedAt:       revision.CreatedAt,
+	}
+}
+
+// parseModelAliasRoutes decodes stored routes; an unreadable value is treated as no routes
+func parseModelAliasRoutes(raw string) []modelAliasRouteRow {
+	var routes []modelAliasRouteRow
+	if raw != "" {
+		_ = json.Unmarshal([]byte(raw), &routes)
+	}
+	return routes
+}
+
+func modelAliasRoutesToEntity(raw string) []entities.ModelAliasRoute {
+	rows := parseModelAliasRoutes(raw)
+	if len(rows) == 0 {
+		return nil
+	}
+	routes := make([]entities.ModelAliasRoute, len(rows))
+	for i, row := range rows {
+		routes[i] = entities.ModelAliasRoute{ModelID: row.ModelID, Weight: row.Weight}
+	}
+	return routes
+}
@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// modelEventChannel is the LISTEN/NOTIFY channel model events are broadcast on, so every
+// replica of the service sees changes made through any other replica
+const modelEventChannel = "ai_model_events"
+
+// Subscriber buffering and listener reconnect policy
+const (
+	modelEventBuffer          = 64
+	modelEventListenerBackoff = time.Second
+	maxModelEventBackoff      = 30 * time.Second
+)
+
+type modelEventBus struct {
+	db  *gorm.DB
+	dsn string
+
+	mu          sync.Mutex
+	listening   bool
+	subscribers map[chan *entities.ModelEvent]struct{}
+}
+
+// NewModelEventBus creates the model event bus. Run must be started to deliver events to subscribers.
+func NewModelEventBus(db *gorm.DB, dsn string) *modelEventBus {
+	return &modelEventBus{
+		db:          db,
+		dsn:         dsn,
+		subscribers: make(map[chan *entities.ModelEvent]struct{}),
+	}
+}
+
+// PublishModelEvent broadcasts an event to the subscribers of every replica
+func (b *modelEventBus) PublishModelEvent(ctx context.Context, event *entities.ModelEvent) errors.BaseError {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToPublishModelEvent, err))
+	}
+	if err := b.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", modelEventChannel, string(payload)).Error; err != nil {
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToPublishModelEvent, err))
+	}
+	return nil
+}
+
+// SubscribeModelEvents returns a channel of model events and a function that ends the subscription.
+// The channel is closed when the subscriber falls behind or the listener loses its connection,
+// since events may have been missed; subscribers should then drop anything they cached.
+func (b *modelEventBus) SubscribeModelEvents() (<-chan *entities.ModelEvent, func(), errors.BaseError) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.listening {
+		return nil, nil, errors.ServiceUnavailable(constants.ErrModelEventsUnavailable)
+	}
+
+	ch := make(chan *entities.ModelEvent, modelEventBuffer)
+	b.subscribers[ch] = struct{}{}
+	return ch, func() { b.unsubscribe(ch) }, nil
+}
+
+// Run listens for model events until ctx is cancelled, reconnecting with backoff
+func (b *modelEventBus) Run(ctx context.Context) {
+	backoff := modelEventListenerBackoff
+	for {
+		err := b.listen(ctx)
+		if b.stopListening() {
+			backoff = modelEventListenerBackoff
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Model event listener disconnected, reconnecting in %s: %v", backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxModelEventBackoff)
+	}
+}
+
+// listen holds a dedicated connection on the event channel and fans notifications out to subscribers
+func (b *modelEventBus) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+modelEventChannel); err != nil {
+		return err
+	}
+	b.startListening()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var event entities.ModelEvent
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			log.Printf("Ignoring malformed model event: %v", err)
+			continue
+		}
+		b.broadcast(&event)
+	}
+}
+
+func (b *modelEventBus) broadcast(event *entities.ModelEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// A subscriber that cannot keep up is cut off rather than silently missing events
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (b *modelEventBus) startListening() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listening = true
+}
+
+// stopListening ends every subscription, since events may be missed until the listener
+// reconnects. It returns whether the listener had been connected.
+func (b *modelEventBus) stopListening() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	wasListening := b.listening
+	b.listening = false
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+	return wasListening
+}
+
+func (b *modelEventBus) unsubscribe(ch chan *entities.ModelEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/dto"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type modelPurgeRepository struct {
+	db *gorm.DB
+}
+
+// NewModelPurgeRepository creates a repository purging deleted models
+func NewModelPurgeRepository(db *gorm.DB) *modelPurgeRepository {
+	return &modelPurgeRepository{db: db}
+}
+
+// ListModelsDeletedBefore lists the models deleted before cutoff, oldest first
+func (r *modelPurgeRepository) ListModelsDeletedBefore(ctx context.Context, cutoff time.Time) ([]*entities.AIModel, errors.BaseError) {
+	var dtoModels []dto.AIModel
+	if err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Order("deleted_at").
+		Find(&dtoModels).Error; err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	repository := &modelRepository{db: r.db}
+	models := make([]*entities.AIModel, 0, len(dtoModels))
+	for i := range dtoModels {
+		model, err := repository.dtoToEntity(&dtoModels[i])
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
+// PurgeModel removes a deleted model for good, in one transaction: its usage logs are moved to
+// ai_usage_logs_archive, then the model is deleted along with its keys and pricing. Hourly and
+// daily rollups are kept. It returns how many usage logs were archived.
+func (r *modelPurgeRepository) PurgeModel(ctx context.Context, id string) (int64, errors.BaseError) {
+	modelUUID, err := uuid.Parse(id)
+	if err != nil {
+		return 0, errors.BadRequest(constants.ErrInvalidModelID)
+	}
+
+	var archived int64
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(`INSERT INTO ai_usage_logs_archive SELECT * FROM ai_usage_logs WHERE model_id = ?`, modelUUID)
+		if result.Error != nil {
+			return result.Error
+		}
+		archived = result.RowsAffected
+
+		if err := tx.Exec(`DELETE FROM ai_usage_logs WHERE model_id = ?`, modelUUID).Error; err != nil {
+			return err
+		}
+
+		result = tx.Unscoped().Delete(&dto.AIModel{}, "id = ? AND deleted_at IS NOT NULL", modelUUID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.NotFound(constants.ErrModelNotDeleted)
+		}
+		return nil
+	})
+	if err != nil {
+		if baseErr, ok := err.(errors.BaseError); ok {
+			return 0, baseErr
+		}
+		return 0, errors.Internal(fmt.Errorf(constants.ErrFailedToPurgeModel, err))
+	}
+	return archived, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"time"
 
 	"github.com/blcvn/backend/services/ai-model-service/common/constants"
@@ -13,6 +14,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type modelRepository struct {
@@ -24,7 +26,7 @@ func NewModelRepository(db *gorm.DB) *modelRepository {
 	return &modelRepository{db: db}
 }
 
-// CreateModel creates a new AI model
+// CreateModel creates a new AI model, seeding its provider key pool with the initial key
 func (r *modelRepository) CreateModel(ctx context.Context, payload *entities.CreateModelPayload, encryptedAPIKey string) (*entities.AIModel, errors.BaseError) {
 	// Check if model with same name exists
 	var existing dto.AIModel
@@ -32,28 +34,77 @@ func (r *modelRepository) CreateModel(ctx context.Context, payload *entities.Cre
 		return nil, errors.Conflict(constants.ErrModelAlreadyExists)
 	}
 
-	// Marshal config to JSON
+	// Marshal config and fallbacks to JSON
 	configJSON, _ := json.Marshal(payload.Config)
+	fallbacksJSON := fallbackModelsJSON(payload.FallbackModels)
+
+	modelUUID := uuid.New()
+	if payload.ID != "" {
+		parsed, err := uuid.Parse(payload.ID)
+		if err != nil {
+			return nil, errors.BadRequest(constants.ErrInvalidModelID)
+		}
+		modelUUID = parsed
+	}
+	secretBackend := payload.SecretBackend
+	if secretBackend == "" {
+		secretBackend = entities.SecretBackendLocal
+	}
+	status := payload.Status
+	if status == "" {
+		status = entities.ModelStatusActive
+	}
+	source := payload.Source
+	if source == "" {
+		source = entities.ModelSourceAPI
+	}
 
 	// Create DTO
 	dtoModel := &dto.AIModel{
-		ID:              uuid.New(),
+		ID:              modelUUID,
+		TenantID:        parseOptionalUUID(payload.TenantID),
 		Name:            payload.Name,
 		Provider:        payload.Provider,
 		ModelID:         payload.ModelID,
 		BaseURL:         payload.BaseURL,
 		EncryptedAPIKey: encryptedAPIKey,
+		SecretBackend:   string(secretBackend),
 		Config:          string(configJSON),
 		QuotaDaily:      payload.QuotaDaily,
 		QuotaMonthly:    payload.QuotaMonthly,
 		CostPer1kTokens: payload.CostPer1kTokens,
-		Status:          string(entities.ModelStatusActive),
+		Status:          string(status),
+		Source:          string(source),
+		Tier:            string(payload.Tier),
+		Version:         1,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
+	setCapabilities(dtoModel, payload.Capabilities, payload.Limits)
+	dtoModel.FallbackModels = fallbacksJSON
 
-	// Save to database
-	if err := r.db.WithContext(ctx).Create(dtoModel).Error; err != nil {
+	initialKey := &dto.ProviderKey{
+		ID:           uuid.New(),
+		ModelID:      dtoModel.ID,
+		Label:        "primary",
+		KeyHint:      entities.ProviderKeyHint(payload.APIKey),
+		EncryptedKey: encryptedAPIKey,
+		Weight:       1,
+		Status:       string(entities.ProviderKeyStatusActive),
+		CreatedAt:    dtoModel.CreatedAt,
+		UpdatedAt:    dtoModel.UpdatedAt,
+	}
+
+	// Save to database. Models created without a key start with an empty pool.
+	if err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(dtoModel).Error; err != nil {
+			return err
+		}
+		if encryptedAPIKey == "" {
+			return nil
+		}
+		return tx.Create(initialKey).Error
+	}); err != nil {
 		return nil, errors.Internal(fmt.Errorf(constants.ErrFailedToCreateModel, err))
 	}
 
@@ -93,17 +144,47 @@ func (r *modelRepository) GetModelByName(ctx context.Context, name string) (*ent
 	return r.dtoToEntity(&dtoModel)
 }
 
-// ListModels lists models with filtering
+// GetDeletedModel retrieves a deleted model that has not been purged yet by ID
+func (r *modelRepository) GetDeletedModel(ctx context.Context, id string) (*entities.AIModel, errors.BaseError) {
+	modelUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.BadRequest(constants.ErrInvalidModelID)
+	}
+
+	var dtoModel dto.AIModel
+	if err := r.db.WithContext(ctx).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", modelUUID).First(&dtoModel).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound(constants.ErrModelNotDeleted)
+		}
+		return nil, errors.Internal(err)
+	}
+
+	return r.dtoToEntity(&dtoModel)
+}
+
+// ListModels lists models with filtering. Deleted models are left out unless asked for.
 func (r *modelRepository) ListModels(ctx context.Context, filter *entities.ModelFilter) ([]*entities.AIModel, int64, errors.BaseError) {
 	query := r.db.WithContext(ctx).Model(&dto.AIModel{})
+	if filter.IncludeDeleted {
+		query = query.Unscoped()
+	}
 
 	// Apply filters
+	if filter.TenantID != "" {
+		query = query.Where("tenant_id IS NULL OR tenant_id = ?", filter.TenantID)
+	}
 	if filter.Provider != "" {
 		query = query.Where("provider = ?", filter.Provider)
 	}
 	if filter.Status != "" {
 		query = query.Where("status = ?", string(filter.Status))
 	}
+	if filter.Tier != "" {
+		query = query.Where("tier = ?", string(filter.Tier))
+	}
+	if filter.ReplacementID != "" {
+		query = query.Where("replacement_id = ?", filter.ReplacementID)
+	}
 
 	// Count total
 	var total int64
@@ -136,8 +217,10 @@ func (r *modelRepository) ListModels(ctx context.Context, filter *entities.Model
 	return models, total, nil
 }
 
-// UpdateModel updates an existing model
-func (r *modelRepository) UpdateModel(ctx context.Context, payload *entities.UpdateModelPayload) (*entities.AIModel, errors.BaseError) {
+// UpdateModel writes the fields named in the payload, including zero values. storedKey replaces
+// the model's API key, and the pool key that held the old one, when the key is updated. The write
+// only applies to the version it was read at; a model changed in between fails with a Conflict.
+func (r *modelRepository) UpdateModel(ctx context.Context, payload *entities.UpdateModelPayload, storedKey string) (*entities.AIModel, errors.BaseError) {
 	modelUUID, err := uuid.Parse(payload.ID)
 	if err != nil {
 		return nil, errors.BadRequest(constants.ErrInvalidModelID)
@@ -151,37 +234,211 @@ func (r *modelRepository) UpdateModel(ctx context.Context, payload *entities.Upd
 		}
 		return nil, errors.Internal(err)
 	}
+	if payload.Version != 0 && payload.Version != dtoModel.Version {
+		return nil, errors.Conflict(fmt.Sprintf(constants.ErrModelVersionStale, payload.Version))
+	}
+	if payload.Updates(entities.ModelFieldName) && payload.Name != dtoModel.Name {
+		var existing dto.AIModel
+		if err := r.db.WithContext(ctx).Where("name = ?", payload.Name).First(&existing).Error; err == nil {
+			return nil, errors.Conflict(constants.ErrModelAlreadyExists)
+		}
+	}
 
 	// Prepare updates
 	updates := make(map[string]interface{})
-	if payload.Name != "" {
-		updates["name"] = payload.Name
+	for _, field := range payload.Fields {
+		switch field {
+		case entities.ModelFieldName:
+			updates["name"] = payload.Name
+		case entities.ModelFieldProvider:
+			updates["provider"] = payload.Provider
+		case entities.ModelFieldModelID:
+			updates["model_id"] = payload.ModelID
+		case entities.ModelFieldBaseURL:
+			updates["base_url"] = payload.BaseURL
+		case entities.ModelFieldAPIKey:
+			updates["encrypted_api_key"] = storedKey
+		case entities.ModelFieldConfig:
+			config := payload.Config
+			if config == nil {
+				config = map[string]string{}
+			}
+			configJSON, _ := json.Marshal(config)
+			updates["config"] = string(configJSON)
+		case entities.ModelFieldQuotaDaily:
+			updates["quota_daily"] = payload.QuotaDaily
+		case entities.ModelFieldQuotaMonthly:
+			updates["quota_monthly"] = payload.QuotaMonthly
+		case entities.ModelFieldCostPer1kTokens:
+			updates["cost_per_1k_tokens"] = payload.CostPer1kTokens
+		case entities.ModelFieldStatus:
+			updates["status"] = string(payload.Status)
+			if payload.Status != entities.ModelStatusDeprecated {
+				updates["deprecated_at"] = nil
+				updates["sunset_at"] = nil
+				updates["replacement_id"] = nil
+			} else if dtoModel.DeprecatedAt == nil {
+				updates["deprecated_at"] = time.Now()
+			}
+		case entities.ModelFieldCapabilities:
+			updates["supports_tools"] = payload.Capabilities.Tools
+			updates["supports_vision"] = payload.Capabilities.Vision
+			updates["supports_json_mode"] = payload.Capabilities.JSONMode
+			updates["supports_streaming"] = payload.Capabilities.Streaming
+		case entities.ModelFieldLimits:
+			updates["context_window"] = payload.Limits.ContextWindow
+			updates["max_output_tokens"] = payload.Limits.MaxOutputTokens
+		case entities.ModelFieldFallbackModels:
+			updates["fallback_models"] = fallbackModelsJSON(payload.FallbackModels)
+		case entities.ModelFieldTier:
+			updates["tier"] = string(payload.Tier)
+		}
+	}
+	updates["updated_at"] = time.Now()
+	updates["version"] = gorm.Expr("version + 1")
+
+	// Update, unless another write bumped the version since the model was read
+	stale := false
+	if err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&dto.AIModel{}).Where("id = ? AND version = ?", modelUUID, dtoModel.Version).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			stale = true
+			return nil
+		}
+		if storedKey == "" || dtoModel.EncryptedAPIKey == "" {
+			return nil
+		}
+		return tx.Model(&dto.ProviderKey{}).
+			Where("model_id = ? AND encrypted_key = ?", modelUUID, dtoModel.EncryptedAPIKey).
+			Updates(map[string]interface{}{
+				"encrypted_key": storedKey,
+				"key_hint":      entities.ProviderKeyHint(payload.APIKey),
+				"updated_at":    time.Now(),
+			}).Error
+	}); err != nil {
+		return nil, errors.Internal(fmt.Errorf(constants.ErrFailedToUpdateModel, err))
 	}
-	if payload.Config != nil {
-		configJSON, _ := json.Marshal(payload.Config)
-		updates["config"] = string(configJSON)
+	if stale {
+		return nil, errors.Conflict(fmt.Sprintf(constants.ErrModelVersionStale, dtoModel.Version))
 	}
-	if payload.QuotaDaily > 0 {
-		updates["quota_daily"] = payload.QuotaDaily
+
+	// Fetch updated model
+	return r.GetModel(ctx, payload.ID)
+}
+
+// ListModelsBySource lists the global models managed by source
+func (r *modelRepository) ListModelsBySource(ctx context.Context, source entities.ModelSource) ([]*entities.AIModel, errors.BaseError) {
+	var dtoModels []dto.AIModel
+	if err := r.db.WithContext(ctx).
+		Where("tenant_id IS NULL AND source = ?", string(source)).
+		Order("name").
+		Find(&dtoModels).Error; err != nil {
+		return nil, errors.Internal(err)
 	}
-	if payload.QuotaMonthly > 0 {
-		updates["quota_monthly"] = payload.QuotaMonthly
+
+	models := make([]*entities.AIModel, 0, len(dtoModels))
+	for i := range dtoModels {
+		model, err := r.dtoToEntity(&dtoModels[i])
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, model)
 	}
-	if payload.Status != "" {
-		updates["status"] = string(payload.Status)
+	return models, nil
+}
+
+// SaveCatalogModel writes the catalog-managed fields of a model, including zero values.
+// The API key and secret backend are never touched.
+func (r *modelRepository) SaveCatalogModel(ctx context.Context, model *entities.AIModel) (*entities.AIModel, errors.BaseError) {
+	modelUUID, err := uuid.Parse(model.ID)
+	if err != nil {
+		return nil, errors.BadRequest(constants.ErrInvalidModelID)
 	}
-	updates["updated_at"] = time.Now()
 
-	// Update
-	if err := r.db.WithContext(ctx).Model(&dtoModel).Updates(updates).Error; err != nil {
-		return nil, errors.Internal(fmt.Errorf(constants.ErrFailedToUpdateModel, err))
+	configJSON, _ := json.Marshal(model.Config)
+	updates := map[string]interface{}{
+		"provider":           model.Provider,
+		"model_id":           model.ModelID,
+		"base_url":           model.BaseURL,
+		"config":             string(configJSON),
+		"quota_daily":        model.QuotaDaily,
+		"quota_monthly":      model.QuotaMonthly,
+		"cost_per_1k_tokens": model.CostPer1kTokens,
+		"status":             string(model.Status),
+		"source":             string(model.Source),
+		"supports_tools":     model.Capabilities.Tools,
+		"supports_vision":    model.Capabilities.Vision,
+		"supports_json_mode": model.Capabilities.JSONMode,
+		"supports_streaming": model.Capabilities.Streaming,
+		"context_window":     model.Limits.ContextWindow,
+		"max_output_tokens":  model.Limits.MaxOutputTokens,
+		"fallback_models":    fallbackModelsJSON(model.FallbackModels),
+		"tier":               string(model.Tier),
+		"updated_at":         time.Now(),
+		"version":            gorm.Expr("version + 1"),
+	}
+	maps.Copy(updates, deprecationUpdates(model.Status, model.Deprecation))
+	result := r.db.WithContext(ctx).Model(&dto.AIModel{}).Where("id = ?", modelUUID).Updates(updates)
+	if result.Error != nil {
+		return nil, errors.Internal(fmt.Errorf(constants.ErrFailedToUpdateModel, result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.NotFound(constants.ErrModelNotFound)
 	}
 
-	// Fetch updated model
-	return r.GetModel(ctx, payload.ID)
+	return r.GetModel(ctx, model.ID)
+}
+
+// DeprecateModel marks a model deprecated with the payload's dates and replacement
+func (r *modelRepository) DeprecateModel(ctx context.Context, payload *entities.DeprecateModelPayload) (*entities.AIModel, errors.BaseError) {
+	modelUUID, err := uuid.Parse(payload.ModelID)
+	if err != nil {
+		return nil, errors.BadRequest(constants.ErrInvalidModelID)
+	}
+
+	updates := deprecationUpdates(entities.ModelStatusDeprecated, &entities.ModelDeprecation{
+		AnnouncedAt:   payload.AnnouncedAt,
+		SunsetAt:      payload.SunsetAt,
+		ReplacementID: payload.ReplacementID,
+	})
+	updates["status"] = string(entities.ModelStatusDeprecated)
+	updates["updated_at"] = time.Now()
+	updates["version"] = gorm.Expr("version + 1")
+	result := r.db.WithContext(ctx).Model(&dto.AIModel{}).Where("id = ?", modelUUID).Updates(updates)
+	if result.Error != nil {
+		return nil, errors.Internal(fmt.Errorf(constants.ErrFailedToUpdateModel, result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.NotFound(constants.ErrModelNotFound)
+	}
+
+	return r.GetModel(ctx, payload.ModelID)
+}
+
+// deprecationUpdates returns the deprecation columns of a model with the given status. They are
+// cleared unless the model is deprecated; a deprecation without a date is dated now.
+func deprecationUpdates(status entities.ModelStatus, deprecation *entities.ModelDeprecation) map[string]interface{} {
+	if status != entities.ModelStatusDeprecated {
+		return map[string]interface{}{"deprecated_at": nil, "sunset_at": nil, "replacement_id": nil}
+	}
+	if deprecation == nil {
+		deprecation = &entities.ModelDeprecation{}
+	}
+	announcedAt := deprecation.AnnouncedAt
+	if announcedAt.IsZero() {
+		announcedAt = time.Now()
+	}
+	return map[string]interface{}{
+		"deprecated_at":  announcedAt,
+		"sunset_at":      deprecation.SunsetAt,
+		"replacement_id": parseOptionalUUID(deprecation.ReplacementID),
+	}
 }
 
-// DeleteModel deletes a model
+// DeleteModel soft-deletes a model. Its row, keys, pricing and usage are kept until it is purged.
 func (r *modelRepository) DeleteModel(ctx context.Context, id string) errors.BaseError {
 	modelUUID, err := uuid.Parse(id)
 	if err != nil {
@@ -199,7 +456,33 @@ func (r *modelRepository) DeleteModel(ctx context.Context, id string) errors.Bas
 	return nil
 }
 
-// LogUsage logs AI usage
+// RestoreModel undeletes a deleted model, unless another model took its name in the meantime
+func (r *modelRepository) RestoreModel(ctx context.Context, id string) (*entities.AIModel, errors.BaseError) {
+	model, err := r.GetDeletedModel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.GetModelByName(ctx, model.Name); err == nil {
+		return nil, errors.Conflict(constants.ErrModelAlreadyExists)
+	} else if err.GetCode() != errors.NOT_FOUND {
+		return nil, err
+	}
+
+	result := r.db.WithContext(ctx).Unscoped().Model(&dto.AIModel{}).
+		Where("id = ? AND deleted_at IS NOT NULL", model.ID).
+		Updates(map[string]interface{}{"deleted_at": nil, "updated_at": time.Now(), "version": gorm.Expr("version + 1")})
+	if result.Error != nil {
+		return nil, errors.Internal(fmt.Errorf(constants.ErrFailedToUpdateModel, result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.NotFound(constants.ErrModelNotDeleted)
+	}
+
+	return r.GetModel(ctx, model.ID)
+}
+
+// LogUsage logs AI usage. Payloads carrying an event ID are logged at most once;
+// a repeated event returns a Conflict error and leaves the rollups untouched.
 func (r *modelRepository) LogUsage(ctx context.Context, payload *entities.LogUsagePayload) errors.BaseError {
 	modelUUID, err := uuid.Parse(payload.ModelID)
 	if err != nil {
@@ -208,87 +491,158 @@ func (r *modelRepository) LogUsage(ctx context.Context, payload *entities.LogUsa
 
 	var userUUID, sessionUUID *uuid.UUID
 	if payload.UserID != "" {
-		parsed, _ := uuid.Parse(payload.UserID)
+		parsed, err := uuid.Parse(payload.UserID)
+		if err != nil {
+			return errors.BadRequest(constants.ErrInvalidUserID)
+		}
 		userUUID = &parsed
 	}
 	if payload.SessionID != "" {
-		parsed, _ := uuid.Parse(payload.SessionID)
+		parsed, err := uuid.Parse(payload.SessionID)
+		if err != nil {
+			return errors.BadRequest(constants.ErrInvalidSessionID)
+		}
 		sessionUUID = &parsed
 	}
 
-	// Get model to calculate cost
+	// Get model to calculate cost. Calls made just before a model was deleted are still billed.
 	var model dto.AIModel
-	if err := r.db.WithContext(ctx).Where("id = ?", modelUUID).First(&model).Error; err != nil {
+	if err := r.db.WithContext(ctx).Unscoped().Where("id = ?", modelUUID).First(&model).Error; err != nil {
 		return errors.NotFound(constants.ErrModelNotFound)
 	}
 
-	// Calculate cost
+	now := time.Now()
+	if payload.OccurredAt != nil && !payload.OccurredAt.IsZero() {
+		now = *payload.OccurredAt
+	}
+	if payload.TokensUsed == 0 {
+		payload.TokensUsed = payload.PromptTokens + payload.CompletionTokens
+	}
+
+	// Calculate cost from the pricing version in effect, falling back to the flat per-1k rate
+	var pricingID *uuid.UUID
 	cost := model.CostPer1kTokens.Mul(decimal.NewFromInt(payload.TokensUsed)).Div(decimal.NewFromInt(1000))
+	pricing, pricingErr := r.GetEffectivePricing(ctx, payload.ModelID, now)
+	if pricingErr == nil {
+		cost = pricing.Cost(payload)
+		pricingID = parseOptionalUUID(pricing.ID)
+	} else if pricingErr.GetCode() != errors.NOT_FOUND {
+		return pricingErr
+	}
 
 	// Create usage log
 	usageLog := &dto.UsageLog{
-		ID:           uuid.New(),
-		ModelID:      modelUUID,
-		UserID:       userUUID,
-		SessionID:    sessionUUID,
-		PromptHash:   payload.PromptHash,
-		TokensUsed:   payload.TokensUsed,
-		Cost:         cost,
-		LatencyMs:    payload.LatencyMs,
-		Status:       string(payload.Status),
-		ErrorMessage: payload.ErrorMessage,
-		CreatedAt:    time.Now(),
-	}
-
-	if err := r.db.WithContext(ctx).Create(usageLog).Error; err != nil {
+		ID:               uuid.New(),
+		ModelID:          modelUUID,
+		TenantID:         parseOptionalUUID(payload.TenantID),
+		ProjectID:        parseOptionalUUID(payload.ProjectID),
+		UserID:           userUUID,
+		SessionID:        sessionUUID,
+		APIKeyID:         parseOptionalUUID(payload.APIKeyID),
+		Alias:            payload.Alias,
+		RequestedModelID: parseOptionalUUID(payload.RequestedModelID),
+		PromptHash:       payload.PromptHash,
+		TokensUsed:       payload.TokensUsed,
+		PromptTokens:     payload.PromptTokens,
+		CompletionTokens: payload.CompletionTokens,
+		CachedReadTokens: payload.CachedReadTokens,
+		CacheWriteTokens: payload.CacheWriteTokens,
+		ImageCount:       payload.ImageCount,
+		PricingID:        pricingID,
+		Cost:             cost,
+		LatencyMs:        payload.LatencyMs,
+		Status:           string(payload.Status),
+		ErrorMessage:     payload.ErrorMessage,
+		CreatedAt:        now,
+	}
+	if payload.EventID != "" {
+		usageLog.EventID = &payload.EventID
+	}
+
+	// Store the log and fold it into the hourly/daily rollups atomically
+	duplicate := false
+	store := func() error {
+		return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(usageLog)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				duplicate = true
+				return nil
+			}
+			return upsertUsageRollups(tx, usageLog)
+		})
+	}
+	err = store()
+	// Events of a month whose partition maintenance has not created yet create it
+	if err != nil && isMissingUsagePartition(err) {
+		if err = createUsagePartition(r.db.WithContext(ctx), startOfUTCMonth(now)); err == nil {
+			err = store()
+		}
+	}
+	if err != nil {
 		return errors.Internal(fmt.Errorf(constants.ErrFailedToLogUsage, err))
 	}
+	if duplicate {
+		return errors.Conflict(constants.ErrDuplicateUsageEvent)
+	}
 
 	return nil
 }
 
-// GetDailyUsage gets total tokens used today for a model
-func (r *modelRepository) GetDailyUsage(ctx context.Context, modelID string, date time.Time) (int64, errors.BaseError) {
+// GetDailyUsage gets total tokens used on a day for a model, optionally scoped to a tenant
+func (r *modelRepository) GetDailyUsage(ctx context.Context, modelID, tenantID string, date time.Time) (int64, errors.BaseError) {
 	modelUUID, err := uuid.Parse(modelID)
 	if err != nil {
 		return 0, errors.BadRequest(constants.ErrInvalidModelID)
 	}
 
-	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour)
-
-	var total int64
-	if err := r.db.WithContext(ctx).Model(&dto.UsageLog{}).
-		Where("model_id = ? AND created_at >= ? AND created_at < ? AND status = ?",
-			modelUUID, startOfDay, endOfDay, string(entities.UsageStatusSuccess)).
-		Select("COALESCE(SUM(tokens_used), 0)").
-		Scan(&total).Error; err != nil {
-		return 0, errors.Internal(fmt.Errorf(constants.ErrFailedToGetUsage, err))
-	}
-
-	return total, nil
+	startOfDay := startOfUTCDay(date)
+	return r.sumRollupTokens(ctx, &modelUUID, tenantID, startOfDay, startOfDay.Add(24*time.Hour))
 }
 
-// GetMonthlyUsage gets total tokens used this month for a model
-func (r *modelRepository) GetMonthlyUsage(ctx context.Context, modelID string, year int, month int) (int64, errors.BaseError) {
+// GetMonthlyUsage gets total tokens used in a month for a model, optionally scoped to a tenant
+func (r *modelRepository) GetMonthlyUsage(ctx context.Context, modelID, tenantID string, year int, month int) (int64, errors.BaseError) {
 	modelUUID, err := uuid.Parse(modelID)
 	if err != nil {
 		return 0, errors.BadRequest(constants.ErrInvalidModelID)
 	}
 
 	startOfMonth := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	endOfMonth := startOfMonth.AddDate(0, 1, 0)
+	return r.sumRollupTokens(ctx, &modelUUID, tenantID, startOfMonth, startOfMonth.AddDate(0, 1, 0))
+}
 
-	var total int64
-	if err := r.db.WithContext(ctx).Model(&dto.UsageLog{}).
-		Where("model_id = ? AND created_at >= ? AND created_at < ? AND status = ?",
-			modelUUID, startOfMonth, endOfMonth, string(entities.UsageStatusSuccess)).
-		Select("COALESCE(SUM(tokens_used), 0)").
-		Scan(&total).Error; err != nil {
-		return 0, errors.Internal(fmt.Errorf(constants.ErrFailedToGetUsage, err))
+// GetDailyUsageByModels gets total tokens used on a day for each of the models, optionally scoped to a tenant
+func (r *modelRepository) GetDailyUsageByModels(ctx context.Context, modelIDs []string, tenantID string, date time.Time) (map[string]int64, errors.BaseError) {
+	startOfDay := startOfUTCDay(date)
+	return r.sumRollupTokensByModel(ctx, modelIDs, tenantID, startOfDay, startOfDay.Add(24*time.Hour))
+}
+
+// GetMonthlyUsageByModels gets total tokens used in a month for each of the models, optionally scoped to a tenant
+func (r *modelRepository) GetMonthlyUsageByModels(ctx context.Context, modelIDs []string, tenantID string, year int, month int) (map[string]int64, errors.BaseError) {
+	startOfMonth := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return r.sumRollupTokensByModel(ctx, modelIDs, tenantID, startOfMonth, startOfMonth.AddDate(0, 1, 0))
+}
+
+// GetTenantDailyUsage gets total tokens used on a day by a tenant across all models
+func (r *modelRepository) GetTenantDailyUsage(ctx context.Context, tenantID string, date time.Time) (int64, errors.BaseError) {
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return 0, errors.BadRequest(constants.ErrInvalidTenantID)
+	}
+
+	startOfDay := startOfUTCDay(date)
+	return r.sumRollupTokens(ctx, nil, tenantID, startOfDay, startOfDay.Add(24*time.Hour))
+}
+
+// GetTenantMonthlyUsage gets total tokens used in a month by a tenant across all models
+func (r *modelRepository) GetTenantMonthlyUsage(ctx context.Context, tenantID string, year int, month int) (int64, errors.BaseError) {
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return 0, errors.BadRequest(constants.ErrInvalidTenantID)
 	}
 
-	return total, nil
+	startOfMonth := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return r.sumRollupTokens(ctx, nil, tenantID, startOfMonth, startOfMonth.AddDate(0, 1, 0))
 }
 
 // Helper: Convert DTO to Entity
@@ -297,20 +651,72 @@ func (r *modelRepository) dtoToEntity(dtoModel *dto.AIModel) (*entities.AIModel,
 	if err := json.Unmarshal([]byte(dtoModel.Config), &config); err != nil {
 		config = make(map[string]string)
 	}
+	var fallbackModels []string
+	if err := json.Unmarshal([]byte(dtoModel.FallbackModels), &fallbackModels); err != nil {
+		fallbackModels = nil
+	}
 
-	return &entities.AIModel{
+	model := &entities.AIModel{
 		ID:              dtoModel.ID.String(),
+		TenantID:        uuidString(dtoModel.TenantID),
 		Name:            dtoModel.Name,
 		Provider:        dtoModel.Provider,
 		ModelID:         dtoModel.ModelID,
 		BaseURL:         dtoModel.BaseURL,
 		EncryptedAPIKey: dtoModel.EncryptedAPIKey,
+		SecretBackend:   entities.SecretBackend(dtoModel.SecretBackend),
 		Config:          config,
 		QuotaDaily:      dtoModel.QuotaDaily,
 		QuotaMonthly:    dtoModel.QuotaMonthly,
 		CostPer1kTokens: dtoModel.CostPer1kTokens,
 		Status:          entities.ModelStatus(dtoModel.Status),
-		CreatedAt:       dtoModel.CreatedAt,
-		UpdatedAt:       dtoModel.UpdatedAt,
-	}, nil
+		Source:          entities.ModelSource(dtoModel.Source),
+		Capabilities: entities.ModelCapabilities{
+			Tools:     dtoModel.SupportsTools,
+			Vision:    dtoModel.SupportsVision,
+			JSONMode:  dtoModel.SupportsJSONMode,
+			Streaming: dtoModel.SupportsStreaming,
+		},
+		Limits: entities.ModelLimits{
+			ContextWindow:   dtoModel.ContextWindow,
+			MaxOutputTokens: dtoModel.MaxOutputTokens,
+		},
+		FallbackModels: fallbackModels,
+		Tier:           entities.ModelTier(dtoModel.Tier),
+		CreatedAt:      dtoModel.CreatedAt,
+		UpdatedAt:      dtoModel.UpdatedAt,
+		Version:        dtoModel.Version,
+	}
+	if dtoModel.DeletedAt.Valid {
+		model.DeletedAt = &dtoModel.DeletedAt.Time
+	}
+	if model.Status == entities.ModelStatusDeprecated {
+		model.Deprecation = &entities.ModelDeprecation{
+			SunsetAt:      dtoModel.SunsetAt,
+			ReplacementID: uuidString(dtoModel.ReplacementID),
+		}
+		if dtoModel.DeprecatedAt != nil {
+			model.Deprecation.AnnouncedAt = *dtoModel.DeprecatedAt
+		}
+	}
+	return model, nil
+}
+
+// setCapabilities copies capabilities and limits onto a model DTO
+func setCapabilities(dtoModel *dto.AIModel, capabilities entities.ModelCapabilities, limits entities.ModelLimits) {
+	dtoModel.SupportsTools = capabilities.Tools
+	dtoModel.SupportsVision = capabilities.Vision
+	dtoModel.SupportsJSONMode = capabilities.JSONMode
+	dtoModel.SupportsStreaming = capabilities.Streaming
+	dtoModel.ContextWindow = limits.ContextWindow
+	dtoModel.MaxOutputTokens = limits.MaxOutputTokens
+}
+
+// fallbackModelsJSON encodes a fallback list for the fallback_models column
+func fallbackModelsJSON(models []string) string {
+	if models == nil {
+		models = []string{}
+	}
+	encoded, _ := json.Marshal(models)
+	return string(encoded)
 }
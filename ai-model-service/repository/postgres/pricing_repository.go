@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/dto"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreatePricing adds a new pricing version for a model
+func (r *modelRepository) CreatePricing(ctx context.Context, payload *entities.SetModelPricingPayload) (*entities.ModelPricing, errors.BaseError) {
+	modelUUID, err := uuid.Parse(payload.ModelID)
+	if err != nil {
+		return nil, errors.BadRequest(constants.ErrInvalidModelID)
+	}
+
+	effectiveFrom := time.Now()
+	if payload.EffectiveFrom != nil {
+		effectiveFrom = *payload.EffectiveFrom
+	}
+
+	dtoPricing := &dto.ModelPricing{
+		ID:                    uuid.New(),
+		ModelID:               modelUUID,
+		InputPer1kTokens:      payload.InputPer1kTokens,
+		OutputPer1kTokens:     payload.OutputPer1kTokens,
+		CachedReadPer1kTokens: payload.CachedReadPer1kTokens,
+		CacheWritePer1kTokens: payload.CacheWritePer1kTokens,
+		PerImage:              payload.PerImage,
+		PerRequest:            payload.PerRequest,
+		EffectiveFrom:         effectiveFrom,
+		CreatedAt:             time.Now(),
+	}
+
+	if err := r.db.WithContext(ctx).Create(dtoPricing).Error; err != nil {
+		return nil, errors.Internal(fmt.Errorf(constants.ErrFailedToCreatePricing, err))
+	}
+
+	return pricingToEntity(dtoPricing), nil
+}
+
+// ListPricing lists every pricing version of a model, newest first
+func (r *modelRepository) ListPricing(ctx context.Context, modelID string) ([]*entities.ModelPricing, errors.BaseError) {
+	modelUUID, err := uuid.Parse(modelID)
+	if err != nil {
+		return nil, errors.BadRequest(constants.ErrInvalidModelID)
+	}
+
+	var dtoPricing []dto.ModelPricing
+	if err := r.db.WithContext(ctx).
+		Where("model_id = ?", modelUUID).
+		Order("effective_from DESC").
+		Find(&dtoPricing).Error; err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	pricing := make([]*entities.ModelPricing, len(dtoPricing))
+	for i := range dtoPricing {
+		pricing[i] = pricingToEntity(&dtoPricing[i])
+	}
+
+	return pricing, nil
+}
+
+// GetEffectivePricing returns the pricing version in effect for a model at the given time
+func (r *modelRepository) GetEffectivePricing(ctx context.Context, modelID string, at time.Time) (*entities.ModelPricing, errors.BaseError) {
+	modelUUID, err := uuid.Parse(modelID)
+	if err != nil {
+		return nil, errors.BadRequest(constants.ErrInvalidModelID)
+	}
+
+	var dtoPricing dto.ModelPricing
+	if err := r.db.WithContext(ctx).
+		Where("model_id = ? AND effective_from <= ?", modelUUID, at).
+		Order("effective_from DESC").
+		First(&dtoPricing).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound(constants.ErrPricingNotFound)
+		}
+		return nil, errors.Internal(err)
+	}
+
+	return pricingToEntity(&dtoPricing), nil
+}
+
+// pricingToEntity converts a pricing DTO to its entity
+func pricingToEntity(dtoPricing *dto.ModelPricing) *entities.ModelPricing {
+	return &entities.ModelPricing{
+		ID:                    dtoPricing.ID.String(),
+		ModelID:               dtoPricing.ModelID.String(),
+		InputPer1kTokens:      dtoPricing.InputPer1kTokens,
+		OutputPer1kTokens:     dtoPricing.OutputPer1kTokens,
+		CachedReadPer1kTokens: dtoPricing.CachedReadPer1kTokens,
+		CacheWritePer1kTokens: dtoPricing.CacheWritePer1kTokens,
+		PerImage:              dtoPricing.PerImage,
+		PerRequest:            dtoPricing.PerRequest,
+		EffectiveFrom:         dtoPricing.EffectiveFrom,
+		CreatedAt:             dtoPricing.CreatedAt,
+	}
+}
@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/dto"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type providerKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewProviderKeyRepository creates a new provider key pool repository
+func NewProviderKeyRepository(db *gorm.DB) *providerKeyRepository {
+	return &providerKeyRepository{db: db}
+}
+
+// CreateProviderKey adds an encrypted provider key to a model's pool
+func (r *providerKeyRepository) CreateProviderKey(ctx context.Context, payload *entities.AddProviderKeyPayload, encryptedKey, keyHint string) (*entities.ProviderKey, errors.BaseError) {
+	modelUUID, err := uuid.Parse(payload.ModelID)
+	if err != nil {
+		return nil, errors.BadRequest(constants.ErrInvalidModelID)
+	}
+
+	dtoKey := &dto.ProviderKey{
+		ID:           uuid.New(),
+		ModelID:      modelUUID,
+		Label:        payload.Label,
+		KeyHint:      keyHint,
+		EncryptedKey: encryptedKey,
+		Weight:       payload.Weight,
+		Status:       string(entities.ProviderKeyStatusActive),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	if err := r.db.WithContext(ctx).Create(dtoKey).Error; err != nil {
+		return nil, errors.Internal(fmt.Errorf(constants.ErrFailedToCreateProviderKey, err))
+	}
+
+	return providerKeyToEntity(dtoKey), nil
+}
+
+// GetProviderKey retrieves a key from a model's pool
+func (r *providerKeyRepository) GetProviderKey(ctx context.Context, modelID, id string) (*entities.ProviderKey, errors.BaseError) {
+	keyUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.BadRequest(constants.ErrInvalidProviderKeyID)
+	}
+
+	var dtoKey dto.ProviderKey
+	if err := r.db.WithContext(ctx).
+		Where("id = ? AND model_id = ?", keyUUID, modelID).
+		First(&dtoKey).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound(constants.ErrProviderKeyNotFound)
+		}
+		return nil, errors.Internal(err)
+	}
+
+	return providerKeyToEntity(&dtoKey), nil
+}
+
+// ListProviderKeys lists every key in a model's pool, including revoked keys
+func (r *providerKeyRepository) ListProviderKeys(ctx context.Context, modelID string) ([]*entities.ProviderKey, errors.BaseError) {
+	modelUUID, err := uuid.Parse(modelID)
+	if err != nil {
+		return nil, errors.BadRequest(constants.ErrInvalidModelID)
+	}
+
+	var dtoKeys []dto.ProviderKey
+	if err := r.db.WithContext(ctx).
+		Where("model_id = ?", modelUUID).
+		Order("created_at ASC").
+		Find(&dtoKeys).Error; err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	keys := make([]*entities.ProviderKey, len(dtoKeys))
+	for i := range dtoKeys {
+		keys[i] = providerKeyToEntity(&dtoKeys[i])
+	}
+	return keys, nil
+}
+
+// UpdateProviderKey changes the weight and/or status of a key
+func (r *providerKeyRepository) UpdateProviderKey(ctx context.Context, payload *entities.UpdateProviderKeyPayload) (*entities.ProviderKey, errors.BaseError) {
+	updates := map[string]interface{}{
+		"updated_at": time.Now(),
+	}
+	if payload.Weight > 0 {
+		updates["weight"] = payload.Weight
+	}
+	if payload.Status != "" {
+		updates["status"] = string(payload.Status)
+		if payload.Status == entities.ProviderKeyStatusActive {
+			// Re-enabling a key clears any quarantine left from before it was disabled
+			updates["quarantined_until"] = nil
+			updates["failure_count"] = 0
+		}
+	}
+
+	if err := r.updateProviderKey(ctx, payload.ModelID, payload.ID, updates); err != nil {
+		return nil, err
+	}
+	return r.GetProviderKey(ctx, payload.ModelID, payload.ID)
+}
+
+// RevokeProviderKey permanently retires a key and erases its ciphertext
+func (r *providerKeyRepository) RevokeProviderKey(ctx context.Context, modelID, id string) errors.BaseError {
+	return r.updateProviderKey(ctx, modelID, id, map[string]interface{}{
+		"status":        string(entities.ProviderKeyStatusRevoked),
+		"encrypted_key": "",
+		"updated_at":    time.Now(),
+	})
+}
+
+// QuarantineProviderKey takes a key out of rotation until the given time
+func (r *providerKeyRepository) QuarantineProviderKey(ctx context.Context, key *entities.ProviderKey) errors.BaseError {
+	return r.updateProviderKey(ctx, key.ModelID, key.ID, map[string]interface{}{
+		"quarantined_until":    key.QuarantinedUntil,
+		"failure_count":        key.FailureCount,
+		"last_failure_at":      key.LastFailureAt,
+		"last_rate_limited_at": key.LastRateLimitedAt,
+		"updated_at":           time.Now(),
+	})
+}
+
+func (r *providerKeyRepository) updateProviderKey(ctx context.Context, modelID, id string, updates map[string]interface{}) errors.BaseError {
+	keyUUID, err := uuid.Parse(id)
+	if err != nil {
+		return errors.BadRequest(constants.ErrInvalidProviderKeyID)
+	}
+
+	result := r.db.WithContext(ctx).Model(&dto.ProviderKey{}).
+		Where("id = ? AND model_id = ?", keyUUID, modelID).
+		Updates(updates)
+	if result.Error != nil {
+		return errors.Internal(fmt.Errorf(constants.ErrFailedToUpdateProviderKey, result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.NotFound(constants.ErrProviderKeyNotFound)
+	}
+	return nil
+}
+
+// Helper: Convert DTO to Entity
+func providerKeyToEntity(dtoKey *dto.ProviderKey) *entities.ProviderKey {
+	return &entities.ProviderKey{
+		ID:                dtoKey.ID.String(),
+		ModelID:           dtoKey.ModelID.String(),
+		Label:             dtoKey.Label,
+		KeyHint:           dtoKey.KeyHint,
+		EncryptedKey:      dtoKey.EncryptedKey,
+		Weight:            dtoKey.Weight,
+		Status:            entities.ProviderKeyStatus(dtoKey.Status),
+		QuarantinedUntil:  dtoKey.QuarantinedUntil,
+		FailureCount:      dtoKey.FailureCount,
+		LastFailureAt:     dtoKey.LastFailureAt,
+		LastRateLimitedAt: dtoKey.LastRateLimitedAt,
+		CreatedAt:         dtoKey.CreatedAt,
+		UpdatedAt:         dtoKey.UpdatedAt,
+	}
+}
@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/dto"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type tenantRepository struct {
+	db *gorm.DB
+}
+
+// NewTenantRepository creates a new tenant repository
+func NewTenantRepository(db *gorm.DB) *tenantRepository {
+	return &tenantRepository{db: db}
+}
+
+// CreateTenant creates a new tenant
+func (r *tenantRepository) CreateTenant(ctx context.Context, payload *entities.CreateTenantPayload) (*entities.Tenant, errors.BaseError) {
+	var existing dto.Tenant
+	if err := r.db.WithContext(ctx).Where("name = ?", payload.Name).First(&existing).Error; err == nil {
+		return nil, errors.Conflict(constants.ErrTenantAlreadyExists)
+	}
+
+	dtoTenant := &dto.Tenant{
+		ID:           uuid.New(),
+		Name:         payload.Name,
+		QuotaDaily:   payload.QuotaDaily,
+		QuotaMonthly: payload.QuotaMonthly,
+		Status:       string(entities.TenantStatusActive),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	if err := r.db.WithContext(ctx).Create(dtoTenant).Error; err != nil {
+		return nil, errors.Internal(fmt.Errorf(constants.ErrFailedToCreateTenant, err))
+	}
+
+	return tenantToEntity(dtoTenant), nil
+}
+
+// GetTenant retrieves a tenant by ID
+func (r *tenantRepository) GetTenant(ctx context.Context, id string) (*entities.Tenant, errors.BaseError) {
+	tenantUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.BadRequest(constants.ErrInvalidTenantID)
+	}
+
+	var dtoTenant dto.Tenant
+	if err := r.db.WithContext(ctx).Where("id = ?", tenantUUID).First(&dtoTenant).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound(constants.ErrTenantNotFound)
+		}
+		return nil, errors.Internal(err)
+	}
+
+	return tenantToEntity(&dtoTenant), nil
+}
+
+// ListTenants lists all tenants
+func (r *tenantRepository) ListTenants(ctx context.Context) ([]*entities.Tenant, errors.BaseError) {
+	var dtoTenants []dto.Tenant
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&dtoTenants).Error; err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	tenants := make([]*entities.Tenant, 0, len(dtoTenants))
+	for i := range dtoTenants {
+		tenants = append(tenants, tenantToEntity(&dtoTenants[i]))
+	}
+
+	return tenants, nil
+}
+
+// CreateProject creates a new project within a tenant
+func (r *tenantRepository) CreateProject(ctx context.Context, payload *entities.CreateProjectPayload) (*entities.Project, errors.BaseError) {
+	tenantUUID, err := uuid.Parse(payload.TenantID)
+	if err != nil {
+		return nil, errors.BadRequest(constants.ErrInvalidTenantID)
+	}
+
+	var existing dto.Project
+	if err := r.db.WithContext(ctx).Where("tenant_id = ? AND name = ?", tenantUUID, payload.Name).First(&existing).Error; err == nil {
+		return nil, errors.Conflict(constants.ErrProjectAlreadyExists)
+	}
+
+	dtoProject := &dto.Project{
+		ID:        uuid.New(),
+		TenantID:  tenantUUID,
+		Name:      payload.Name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := r.db.WithContext(ctx).Create(dtoProject).Error; err != nil {
+		return nil, errors.Internal(fmt.Errorf(constants.ErrFailedToCreateProject, err))
+	}
+
+	return projectToEntity(dtoProject), nil
+}
+
+// GetProject retrieves a project by ID
+func (r *tenantRepository) GetProject(ctx context.Context, id string) (*entities.Project, errors.BaseError) {
+	projectUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.BadRequest(constants.ErrInvalidProjectID)
+	}
+
+	var dtoProject dto.Project
+	if err := r.db.WithContext(ctx).Where("id = ?", projectUUID).First(&dtoProject).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound(constants.ErrProjectNotFound)
+		}
+		return nil, errors.Internal(err)
+	}
+
+	return projectToEntity(&dtoProject), nil
+}
+
+// ListProjects lists the projects of a tenant
+func (r *tenantRepository) ListProjects(ctx context.Context, tenantID string) ([]*entities.Project, errors.BaseError) {
+	tenantUUID, err := uuid.Parse(tenantID)
+	if err != nil {
+		return nil, errors.BadRequest(constants.ErrInvalidTenantID)
+	}
+
+	var dtoProjects []dto.Project
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantUUID).Order("created_at DESC").Find(&dtoProjects).Error; err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	projects := make([]*entities.Project, 0, len(dtoProjects))
+	for i := range dtoProjects {
+		projects = append(projects, projectToEntity(&dtoProjects[i]))
+	}
+
+	return projects, nil
+}
+
+// Helper: Convert tenant DTO to Entity
+func tenantToEntity(dtoTenant *dto.Tenant) *entities.Tenant {
+	return &entities.Tenant{
+		ID:           dtoTenant.ID.String(),
+		Name:         dtoTenant.Name,
+		QuotaDaily:   dtoTenant.QuotaDaily,
+		QuotaMonthly: dtoTenant.QuotaMonthly,
+		Status:       entities.TenantStatus(dtoTenant.Status),
+		CreatedAt:    dtoTenant.CreatedAt,
+		UpdatedAt:    dtoTenant.UpdatedAt,
+	}
+}
+
+// Helper: Convert project DTO to Entity
+func projectToEntity(dtoProject *dto.Project) *entities.Project {
+	return &entities.Project{
+		ID:        dtoProject.ID.String(),
+		TenantID:  dtoProject.TenantID.String(),
+		Name:      dtoProject.Name,
+		CreatedAt: dtoProject.CreatedAt,
+		UpdatedAt: dtoProject.UpdatedAt,
+	}
+}
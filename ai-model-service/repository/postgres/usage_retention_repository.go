@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/dto"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// usageDeleteBatchSize bounds each delete when raw usage logs are not partitioned
+const usageDeleteBatchSize = 10000
+
+// usagePartitionLayout is the month suffix of usage log partitions, e.g. ai_usage_logs_2025_01
+const usagePartitionLayout = "2006_01"
+
+type usageRetentionRepository struct {
+	db *gorm.DB
+}
+
+// NewUsageRetentionRepository creates a repository maintaining usage log partitions and retention
+func NewUsageRetentionRepository(db *gorm.DB) *usageRetentionRepository {
+	return &usageRetentionRepository{db: db}
+}
+
+// IsUsagePartitioned reports whether ai_usage_logs is range-partitioned by month
+func (r *usageRetentionRepository) IsUsagePartitioned(ctx context.Context) (bool, errors.BaseError) {
+	var count int64
+	if err := r.db.WithContext(ctx).Raw(
+		`SELECT COUNT(*) FROM pg_partitioned_table pt
+		 JOIN pg_class c ON c.oid = pt.partrelid
+		 WHERE c.relname = ?`, dto.UsageLog{}.TableName(),
+	).Scan(&count).Error; err != nil {
+		return false, errors.Internal(fmt.Errorf(constants.ErrFailedToMaintainUsage, err))
+	}
+	return count > 0, nil
+}
+
+// EnsureUsagePartitions creates the monthly partitions from the month containing from through monthsAhead months later
+func (r *usageRetentionRepository) EnsureUsagePartitions(ctx context.Context, from time.Time, monthsAhead int) errors.BaseError {
+	start := startOfUTCMonth(from)
+	for i := 0; i <= monthsAhead; i++ {
+		if err := createUsagePartition(r.db.WithContext(ctx), start.AddDate(0, i, 0)); err != nil {
+			return errors.Internal(fmt.Errorf(constants.ErrFailedToMaintainUsage, err))
+		}
+	}
+	return nil
+}
+
+// createUsagePartition creates the partition of the month starting at monthStart if it is missing
+func createUsagePartition(db *gorm.DB, monthStart time.Time) error {
+	// Names and bounds are derived from dates, never from user input
+	statement := fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')",
+		usagePartitionName(monthStart),
+		dto.UsageLog{}.TableName(),
+		monthStart.Format(time.RFC3339),
+		monthStart.AddDate(0, 1, 0).Format(time.RFC3339),
+	)
+	return db.Exec(statement).Error
+}
+
+// isMissingUsagePartition reports whether an insert failed because no partition covers its row
+func isMissingUsagePartition(err error) bool {
+	var pgErr *pgconn.PgError
+	// 23514 is check_violation, which Postgres also raises for rows outside every partition
+	return stderrors.As(err, &pgErr) && pgErr.Code == "23514" && strings.HasPrefix(pgErr.Message, "no partition of relation")
+}
+
+// DropUsagePartitionsBefore drops monthly partitions that end on or before cutoff and returns how many were dropped
+func (r *usageRetentionRepository) DropUsagePartitionsBefore(ctx context.Context, cutoff time.Time) (int64, errors.BaseError) {
+	var partitions []string
+	if err := r.db.WithContext(ctx).Raw(
+		`SELECT child.relname FROM pg_inherits i
+		 JOIN pg_class parent ON parent.oid = i.inhparent
+		 JOIN pg_class child ON child.oid = i.inhrelid
+		 WHERE parent.relname = ?`, dto.UsageLog{}.TableName(),
+	).Scan(&partitions).Error; err != nil {
+		return 0, errors.Internal(fmt.Errorf(constants.ErrFailedToMaintainUsage, err))
+	}
+
+	prefix := dto.UsageLog{}.TableName() + "_"
+	var dropped int64
+	for _, partition := range partitions {
+		if len(partition) != len(prefix)+len(usagePartitionLayout) || partition[:len(prefix)] != prefix {
+			continue
+		}
+		monthStart, err := time.Parse(usagePartitionLayout, partition[len(prefix):])
+		if err != nil || monthStart.AddDate(0, 1, 0).After(cutoff) {
+			continue
+		}
+		if err := r.db.WithContext(ctx).Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", partition)).Error; err != nil {
+			return dropped, errors.Internal(fmt.Errorf(constants.ErrFailedToMaintainUsage, err))
+		}
+		dropped++
+	}
+
+	return dropped, nil
+}
+
+// DeleteUsageBefore deletes raw usage logs older than cutoff in batches and returns how many were deleted.
+// Used when ai_usage_logs is not partitioned.
+func (r *usageRetentionRepository) DeleteUsageBefore(ctx context.Context, cutoff time.Time) (int64, errors.BaseError) {
+	var deleted int64
+	for {
+		result := r.db.WithContext(ctx).Exec(
+			`DELETE FROM ai_usage_logs WHERE id IN (
+				SELECT id FROM ai_usage_logs WHERE created_at < ? LIMIT ?
+			)`, cutoff, usageDeleteBatchSize,
+		)
+		if result.Error != nil {
+			return deleted, errors.Internal(fmt.Errorf(constants.ErrFailedToMaintainUsage, result.Error))
+		}
+		deleted += result.RowsAffected
+		if result.RowsAffected < usageDeleteBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// usagePartitionName returns the partition table name for the month starting at monthStart
+func usagePartitionName(monthStart time.Time) string {
+	return dto.UsageLog{}.TableName() + "_" + monthStart.Format(usagePartitionLayout)
+}
+
+// startOfUTCMonth returns midnight UTC on the first day of the month containing t
+func startOfUTCMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/dto"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// upsertUsageRollups adds a usage log to its hourly and daily rollup rows, to the daily spend of
+// its project and user, and to the running spend of the API key that made the call
+func upsertUsageRollups(tx *gorm.DB, usageLog *dto.UsageLog) error {
+	tenantID := uuid.Nil
+	if usageLog.TenantID != nil {
+		tenantID = *usageLog.TenantID
+	}
+
+	rollup := dto.UsageRollup{
+		ModelID:          usageLog.ModelID,
+		TenantID:         tenantID,
+		RequestCount:     1,
+		TokensUsed:       usageLog.TokensUsed,
+		PromptTokens:     usageLog.PromptTokens,
+		CompletionTokens: usageLog.CompletionTokens,
+		Cost:             usageLog.Cost,
+		UpdatedAt:        usageLog.CreatedAt,
+	}
+	if usageLog.Status == string(entities.UsageStatusSuccess) {
+		rollup.SuccessTokens = usageLog.TokensUsed
+	} else {
+		rollup.ErrorCount = 1
+	}
+
+	hourly := dto.HourlyUsage(rollup)
+	hourly.BucketStart = usageLog.CreatedAt.UTC().Truncate(time.Hour)
+	if err := tx.Clauses(rollupConflictClause(hourly.TableName())).Create(&hourly).Error; err != nil {
+		return err
+	}
+
+	daily := dto.DailyUsage(rollup)
+	daily.BucketStart = startOfUTCDay(usageLog.CreatedAt)
+	if err := tx.Clauses(rollupConflictClause(daily.TableName())).Create(&daily).Error; err != nil {
+		return err
+	}
+
+	if usageLog.Cost.IsZero() {
+		return nil
+	}
+	// Rows are always written in the same order so concurrent logs cannot deadlock
+	spenders := []struct {
+		scope   entities.BudgetScope
+		scopeID *uuid.UUID
+	}{
+		{entities.BudgetScopeProject, usageLog.ProjectID},
+		{entities.BudgetScopeUser, usageLog.UserID},
+	}
+	for _, spender := range spenders {
+		if spender.scopeID == nil {
+			continue
+		}
+		spend := dto.DailySpend{
+			BucketStart: daily.BucketStart,
+			Scope:       string(spender.scope),
+			ScopeID:     *spender.scopeID,
+			TenantID:    tenantID,
+			Cost:        usageLog.Cost,
+			UpdatedAt:   usageLog.CreatedAt,
+		}
+		if err := tx.Clauses(spendConflictClause(spend.TableName())).Create(&spend).Error; err != nil {
+			return err
+		}
+	}
+
+	if usageLog.APIKeyID == nil {
+		return nil
+	}
+	return tx.Model(&dto.APIKey{}).
+		Where("id = ?", *usageLog.APIKeyID).
+		Update("spent_usd", gorm.Expr("spent_usd + ?", usageLog.Cost)).Error
+}
+
+// rollupConflictClause increments the existing rollup row instead of inserting a duplicate
+func rollupConflictClause(table string) clause.OnConflict {
+	increment := func(column string) clause.Expr {
+		return gorm.Expr(fmt.Sprintf("%s.%s + EXCLUDED.%s", table, column, column))
+	}
+
+	return clause.OnConflict{
+		Columns: []clause.Column{{Name: "bucket_start"}, {Name: "model_id"}, {Name: "tenant_id"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "request_count"}, Value: increment("request_count")},
+			{Column: clause.Column{Name: "error_count"}, Value: increment("error_count")},
+			{Column: clause.Column{Name: "tokens_used"}, Value: increment("tokens_used")},
+			{Column: clause.Column{Name: "success_tokens"}, Value: increment("success_tokens")},
+			{Column: clause.Column{Name: "prompt_tokens"}, Value: increment("prompt_tokens")},
+			{Column: clause.Column{Name: "completion_tokens"}, Value: increment("completion_tokens")},
+			{Column: clause.Column{Name: "cost"}, Value: increment("cost")},
+			{Column: clause.Column{Name: "updated_at"}, Value: gorm.Expr("EXCLUDED.updated_at")},
+		},
+	}
+}
+
+// spendConflictClause adds to the existing daily spend row instead of inserting a duplicate
+func spendConflictClause(table string) clause.OnConflict {
+	return clause.OnConflict{
+		Columns: []clause.Column{{Name: "bucket_start"}, {Name: "scope"}, {Name: "scope_id"}, {Name: "tenant_id"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "cost"}, Value: gorm.Expr(fmt.Sprintf("%s.cost + EXCLUDED.cost", table))},
+			{Column: clause.Column{Name: "updated_at"}, Value: gorm.Expr("EXCLUDED.updated_at")},
+		},
+	}
+}
+
+// sumRollupTokens sums successful token usage from the daily rollup in [start, end), filtered by model and/or tenant
+func (r *modelRepository) sumRollupTokens(ctx context.Context, modelUUID *uuid.UUID, tenantID string, start, end time.Time) (int64, errors.BaseError) {
+	query := r.db.WithContext(ctx).Model(&dto.DailyUsage{}).
+		Where("bucket_start >= ? AND bucket_start < ?", start, end)
+	if modelUUID != nil {
+		query = query.Where("model_id = ?", *modelUUID)
+	}
+	if tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+
+	var total int64
+	if err := query.Select("COALESCE(SUM(success_tokens), 0)").Scan(&total).Error; err != nil {
+		return 0, errors.Internal(fmt.Errorf(constants.ErrFailedToGetUsage, err))
+	}
+
+	return total, nil
+}
+
+// sumRollupTokensByModel sums successful token usage from the daily rollup in [start, end) per model,
+// optionally filtered by tenant. Models without usage are missing from the result.
+func (r *modelRepository) sumRollupTokensByModel(ctx context.Context, modelIDs []string, tenantID string, start, end time.Time) (map[string]int64, errors.BaseError) {
+	totals := make(map[string]int64, len(modelIDs))
+	if len(modelIDs) == 0 {
+		return totals, nil
+	}
+
+	modelUUIDs := make([]uuid.UUID, len(modelIDs))
+	for i, modelID := range modelIDs {
+		modelUUID, err := uuid.Parse(modelID)
+		if err != nil {
+			return nil, errors.BadRequest(constants.ErrInvalidModelID)
+		}
+		modelUUIDs[i] = modelUUID
+	}
+
+	query := r.db.WithContext(ctx).Model(&dto.DailyUsage{}).
+		Where("bucket_start >= ? AND bucket_start < ?", start, end).
+		Where("model_id IN ?", modelUUIDs)
+	if tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+
+	var rows []struct {
+		ModelID uuid.UUID
+		Total   int64
+	}
+	if err := query.Select("model_id, COALESCE(SUM(success_tokens), 0) AS total").
+		Group("model_id").
+		Scan(&rows).Error; err != nil {
+		return nil, errors.Internal(fmt.Errorf(constants.ErrFailedToGetUsage, err))
+	}
+
+	for _, row := range rows {
+		totals[row.ModelID.String()] = row.Total
+	}
+	return totals, nil
+}
+
+// startOfUTCDay returns midnight UTC of the day containing t
+func startOfUTCDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// maxUsageStatsRows caps the number of groups a single stats query may return
+const maxUsageStatsRows = 10000
+
+// usageGroupColumns maps group-by dimensions to their usage log columns
+var usageGroupColumns = map[entities.UsageGroupBy]string{
+	entities.UsageGroupByModel:   "model_id",
+	entities.UsageGroupByUser:    "user_id",
+	entities.UsageGroupBySession: "session_id",
+	entities.UsageGroupByStatus:  "status",
+	entities.UsageGroupByAlias:   "alias",
+}
+
+// usageStatsRow is the scan target of the usage stats aggregation
+type usageStatsRow struct {
+	ModelID          *uuid.UUID
+	UserID           *uuid.UUID
+	SessionID        *uuid.UUID
+	Alias            *string
+	Status           *string
+	BucketStart      *time.Time
+	RequestCount     int64
+	ErrorCount       int64
+	TokensUsed       int64
+	PromptTokens     int64
+	CompletionTokens int64
+	Cost             decimal.Decimal
+	LatencyAvgMs     float64
+	LatencyP50Ms     float64
+	LatencyP95Ms     float64
+	LatencyP99Ms     float64
+}
+
+// GetUsageStats aggregates usage logs by the requested dimensions and time bucket in a single query
+func (r *modelRepository) GetUsageStats(ctx context.Context, filter *entities.UsageStatsFilter) ([]*entities.UsageStats, errors.BaseError) {
+	selects := make([]string, 0, len(filter.GroupBy)+1)
+	groups := make([]string, 0, len(filter.GroupBy)+1)
+	for _, dimension := range filter.GroupBy {
+		column, ok := usageGroupColumns[dimension]
+		if !ok {
+			return nil, errors.BadRequest(fmt.Sprintf(constants.ErrInvalidUsageGroupBy, dimension))
+		}
+		selects = append(selects, column)
+		groups = append(groups, column)
+	}
+
+	switch filter.Bucket {
+	case entities.UsageBucketNone:
+	case entities.UsageBucketHour, entities.UsageBucketDay, entities.UsageBucketMonth:
+		// Bucket is whitelisted above, so it is safe to inline
+		selects = append(selects, fmt.Sprintf("date_trunc('%s', created_at) AS bucket_start", filter.Bucket))
+		groups = append(groups, "bucket_start")
+	default:
+		return nil, errors.BadRequest(fmt.Sprintf(constants.ErrInvalidUsageBucket, filter.Bucket))
+	}
+
+	selects = append(selects,
+		"COUNT(*) AS request_count",
+		"COUNT(*) FILTER (WHERE status <> ?) AS error_count",
+		"COALESCE(SUM(tokens_used), 0) AS tokens_used",
+		"COALESCE(SUM(prompt_tokens), 0) AS prompt_tokens",
+		"COALESCE(SUM(completion_tokens), 0) AS completion_tokens",
+		"COALESCE(SUM(cost), 0) AS cost",
+		"COALESCE(AVG(latency_ms), 0) AS latency_avg_ms",
+		"COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY latency_ms), 0) AS latency_p50_ms",
+		"COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY latency_ms), 0) AS latency_p95_ms",
+		"COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY latency_ms), 0) AS latency_p99_ms",
+	)
+
+	query := r.db.WithContext(ctx).
+		Table("ai_usage_logs").
+		Select(strings.Join(selects, ", "), string(entities.UsageStatusSuccess)).
+		Where("created_at >= ? AND created_at < ?", filter.StartTime, filter.EndTime)
+
+	if filter.TenantID != "" {
+		query = query.Where("tenant_id = ?", filter.TenantID)
+	}
+	if filter.ModelID != "" {
+		query = query.Where("model_id = ?", filter.ModelID)
+	}
+	if filter.UserID != "" {
+		userUUID := parseOptionalUUID(filter.UserID)
+		if userUUID == nil {
+			return nil, errors.BadRequest(constants.ErrInvalidUserID)
+		}
+		query = query.Where("user_id = ?", *userUUID)
+	}
+	if filter.SessionID != "" {
+		sessionUUID := parseOptionalUUID(filter.SessionID)
+		if sessionUUID == nil {
+			return nil, errors.BadRequest(constants.ErrInvalidSessionID)
+		}
+		query = query.Where("session_id = ?", *sessionUUID)
+	}
+	if filter.Alias != "" {
+		query = query.Where("alias = ?", filter.Alias)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	if len(groups) > 0 {
+		query = query.Group(strings.Join(groups, ", ")).Order(strings.Join(groups, ", "))
+	}
+
+	var rows []usageStatsRow
+	if err := query.Limit(maxUsageStatsRows).Scan(&rows).Error; err != nil {
+		return nil, errors.Internal(fmt.Errorf(constants.ErrFailedToGetUsage, err))
+	}
+
+	stats := make([]*entities.UsageStats, len(rows))
+	for i, row := range rows {
+		stat := &entities.UsageStats{
+			ModelID:          uuidString(row.ModelID),
+			UserID:           uuidString(row.UserID),
+			SessionID:        uuidString(row.SessionID),
+			BucketStart:      row.BucketStart,
+			RequestCount:     row.RequestCount,
+			ErrorCount:       row.ErrorCount,
+			TokensUsed:       row.TokensUsed,
+			PromptTokens:     row.PromptTokens,
+			CompletionTokens: row.CompletionTokens,
+			Cost:             row.Cost,
+			LatencyAvgMs:     row.LatencyAvgMs,
+			LatencyP50Ms:     row.LatencyP50Ms,
+			LatencyP95Ms:     row.LatencyP95Ms,
+			LatencyP99Ms:     row.LatencyP99Ms,
+		}
+		if row.Alias != nil {
+			stat.Alias = *row.Alias
+		}
+		if row.Status != nil {
+			stat.Status = entities.UsageStatus(*row.Status)
+		}
+		if row.RequestCount > 0 {
+			stat.ErrorRate = float64(row.ErrorCount) / float64(row.RequestCount)
+		}
+		stats[i] = stat
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,23 @@
+package postgres
+
+import "github.com/google/uuid"
+
+// parseOptionalUUID parses an optional UUID string, returning nil when empty or malformed
+func parseOptionalUUID(value string) *uuid.UUID {
+	if value == "" {
+		return nil
+	}
+	parsed, err := uuid.Parse(value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
+
+// uuidString formats an optional UUID, returning an empty string when nil
+func uuidString(value *uuid.UUID) string {
+	if value == nil {
+		return ""
+	}
+	return value.String()
+}
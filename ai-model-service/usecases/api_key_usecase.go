@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/blcvn/backend/services/ai-model-service/helper"
+)
+
+type apiKeyUsecase struct {
+	repository       iAPIKeyRepository
+	tenantRepository iTenantRepository
+}
+
+// CreateAPIKey issues a new virtual API key and returns it together with the plaintext secret.
+// Every key is bound to a tenant, the caller's for tenant-scoped callers: the proxy forwards the
+// key's tenant, and a caller without one would act at platform level.
+func (u *apiKeyUsecase) CreateAPIKey(ctx context.Context, scope *entities.TenantScope, payload *entities.CreateAPIKeyPayload) (*entities.APIKey, string, errors.BaseError) {
+	if payload.Name == "" {
+		return nil, "", errors.BadRequest(constants.ErrInvalidAPIKeyName)
+	}
+	if !scope.IsPlatform() {
+		if payload.TenantID != "" && payload.TenantID != scope.TenantID {
+			return nil, "", errors.Forbidden(constants.ErrTenantAccessDenied)
+		}
+		payload.TenantID = scope.TenantID
+	}
+	if payload.TenantID == "" {
+		return nil, "", errors.BadRequest(constants.ErrAPIKeyTenantRequired)
+	}
+	if _, err := u.tenantRepository.GetTenant(ctx, payload.TenantID); err != nil {
+		if err.GetCode() == errors.NOT_FOUND {
+			return nil, "", errors.BadRequest(constants.ErrTenantNotFound)
+		}
+		return nil, "", err
+	}
+	if payload.ProjectID != "" {
+		project, err := u.tenantRepository.GetProject(ctx, payload.ProjectID)
+		if err != nil {
+			return nil, "", err
+		}
+		if project.TenantID != payload.TenantID {
+			return nil, "", errors.BadRequest(constants.ErrProjectNotFound)
+		}
+	}
+	if payload.ExpiresAt != nil && payload.ExpiresAt.Before(time.Now()) {
+		return nil, "", errors.BadRequest(constants.ErrAPIKeyExpired)
+	}
+
+	plaintext, prefix, hash, err := helper.GenerateAPIKey()
+	if err != nil {
+		return nil, "", errors.Internal(fmt.Errorf("failed to generate api key: %v", err))
+	}
+
+	key, repoErr := u.repository.CreateAPIKey(ctx, payload, prefix, hash)
+	if repoErr != nil {
+		return nil, "", repoErr
+	}
+
+	return key, plaintext, nil
+}
+
+// ListAPIKeys lists virtual API keys of the caller's tenant
+func (u *apiKeyUsecase) ListAPIKeys(ctx context.Context, scope *entities.TenantScope, filter *entities.APIKeyFilter) ([]*entities.APIKey, errors.BaseError) {
+	if !scope.IsPlatform() {
+		filter.TenantID = scope.TenantID
+	}
+	return u.repository.ListAPIKeys(ctx, filter)
+}
+
+// RevokeAPIKey revokes a virtual API key of the caller's tenant
+func (u *apiKeyUsecase) RevokeAPIKey(ctx context.Context, scope *entities.TenantScope, id string) errors.BaseError {
+	tenantID := ""
+	if !scope.IsPlatform() {
+		tenantID = scope.TenantID
+	}
+	return u.repository.RevokeAPIKey(ctx, id, tenantID)
+}
+
+// VerifyAPIKey authenticates a plaintext virtual API key and returns the caller identity
+func (u *apiKeyUsecase) VerifyAPIKey(ctx context.Context, plaintext string) (*entities.CallerIdentity, errors.BaseError) {
+	if plaintext == "" {
+		return nil, errors.Unauthorized(constants.ErrInvalidAPIKey)
+	}
+
+	key, err := u.repository.GetAPIKeyByHash(ctx, helper.HashAPIKey(plaintext))
+	if err != nil {
+		if err.GetCode() == errors.NOT_FOUND {
+			return nil, errors.Unauthorized(constants.ErrInvalidAPIKey)
+		}
+		return nil, err
+	}
+
+	if key.Status != entities.APIKeyStatusActive {
+		return nil, errors.Unauthorized(constants.ErrAPIKeyRevoked)
+	}
+	// Keys issued without a tenant before one was required would give their holder platform scope
+	if key.TenantID == "" {
+		return nil, errors.Unauthorized(constants.ErrAPIKeyTenantRequired)
+	}
+	if key.ExpiresAt != nil && key.ExpiresAt.Before(time.Now()) {
+		return nil, errors.Unauthorized(constants.ErrAPIKeyExpired)
+	}
+	if key.BudgetUSD.IsPositive() && key.SpentUSD.GreaterThanOrEqual(key.BudgetUSD) {
+		return nil, errors.Forbidden(constants.ErrAPIKeyBudgetExceeded)
+	}
+
+	if touchErr := u.repository.TouchAPIKey(ctx, key.ID); touchErr != nil {
+		log.Printf("Warning: failed to update last_used_at for api key %s: %v", key.ID, touchErr)
+	}
+
+	return &entities.CallerIdentity{
+		APIKeyID:      key.ID,
+		TenantID:      key.TenantID,
+		ProjectID:     key.ProjectID,
+		UserID:        key.UserID,
+		AllowedModels: key.AllowedModels,
+	}, nil
+}
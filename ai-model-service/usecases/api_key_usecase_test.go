@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/blcvn/backend/services/ai-model-service/helper"
+)
+
+// fakeAPIKeyRepository keeps virtual API keys in memory, by hash
+type fakeAPIKeyRepository struct {
+	iAPIKeyRepository
+	keys map[string]*entities.APIKey
+}
+
+func (r *fakeAPIKeyRepository) CreateAPIKey(ctx context.Context, payload *entities.CreateAPIKeyPayload, prefix, keyHash string) (*entities.APIKey, errors.BaseError) {
+	key := &entities.APIKey{ID: prefix, TenantID: payload.TenantID, Status: entities.APIKeyStatusActive}
+	r.keys[keyHash] = key
+	return key, nil
+}
+
+func (r *fakeAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*entities.APIKey, errors.BaseError) {
+	if key, ok := r.keys[keyHash]; ok {
+		return key, nil
+	}
+	return nil, errors.NotFound(keyHash)
+}
+
+func (r *fakeAPIKeyRepository) TouchAPIKey(ctx context.Context, id string) errors.BaseError {
+	return nil
+}
+
+// fakeTenantRepository serves GetTenant from memory; other methods are not used
+type fakeTenantRepository struct {
+	iTenantRepository
+	tenants map[string]*entities.Tenant
+}
+
+func (r *fakeTenantRepository) GetTenant(ctx context.Context, id string) (*entities.Tenant, errors.BaseError) {
+	if tenant, ok := r.tenants[id]; ok {
+		return tenant, nil
+	}
+	return nil, errors.NotFound(id)
+}
+
+func TestTenantlessAPIKeyCannotReachPrivateModels(t *testing.T) {
+	ctx := context.Background()
+	keys := &fakeAPIKeyRepository{keys: make(map[string]*entities.APIKey)}
+	tenants := &fakeTenantRepository{tenants: map[string]*entities.Tenant{
+		"tenant-a": {ID: "tenant-a"},
+		"tenant-b": {ID: "tenant-b"},
+	}}
+	apiKeys := NewAPIKeyUsecase(keys, tenants)
+	platform := &entities.TenantScope{Platform: true}
+
+	if _, _, err := apiKeys.CreateAPIKey(ctx, platform, &entities.CreateAPIKeyPayload{Name: "no-tenant"}); err == nil || err.GetCode() != errors.BAD_REQUEST {
+		t.Fatalf("CreateAPIKey without a tenant = %v, want BAD_REQUEST", err)
+	}
+	if _, _, err := apiKeys.CreateAPIKey(ctx, platform, &entities.CreateAPIKeyPayload{Name: "unknown", TenantID: "tenant-c"}); err == nil || err.GetCode() != errors.BAD_REQUEST {
+		t.Fatalf("CreateAPIKey for an unknown tenant = %v, want BAD_REQUEST", err)
+	}
+
+	// A key issued without a tenant before one was required no longer authenticates
+	keys.keys[helper.HashAPIKey("sk-vk-legacy")] = &entities.APIKey{ID: "legacy", Status: entities.APIKeyStatusActive}
+	if _, err := apiKeys.VerifyAPIKey(ctx, "sk-vk-legacy"); err == nil || err.GetCode() != errors.UNAUTHORIZED {
+		t.Fatalf("VerifyAPIKey of a tenantless key = %v, want UNAUTHORIZED", err)
+	}
+
+	_, plaintext, err := apiKeys.CreateAPIKey(ctx, platform, &entities.CreateAPIKeyPayload{Name: "tenant-a", TenantID: "tenant-a"})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	caller, err := apiKeys.VerifyAPIKey(ctx, plaintext)
+	if err != nil {
+		t.Fatalf("VerifyAPIKey: %v", err)
+	}
+
+	// The proxy forwards the key's tenant, which only reaches global models and its own
+	models := &fakeModelRepository{models: map[string]*entities.AIModel{
+		"global":    {ID: "global"},
+		"private-b": {ID: "private-b", TenantID: "tenant-b"},
+	}}
+	usecase := &modelUsecase{repository: models, allowRawCredentials: true}
+	scope := &entities.TenantScope{TenantID: caller.TenantID}
+
+	if _, err := usecase.GetModel(ctx, scope, "global"); err != nil {
+		t.Fatalf("GetModel of a global model: %v", err)
+	}
+	if _, err := usecase.GetModel(ctx, scope, "private-b"); err == nil || err.GetCode() != errors.NOT_FOUND {
+		t.Fatalf("GetModel of another tenant's model = %v, want NOT_FOUND", err)
+	}
+	if _, err := usecase.GetCredentials(ctx, scope, "private-b"); err == nil || err.GetCode() != errors.NOT_FOUND {
+		t.Fatalf("GetCredentials of another tenant's model = %v, want NOT_FOUND", err)
+	}
+}
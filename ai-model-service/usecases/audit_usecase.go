@@ -0,0 +1,280 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/google/uuid"
+)
+
+// auditChainBatchSize is how many events VerifyAuditChain loads, and the chainer appends, at a time
+const auditChainBatchSize = 1000
+
+// anonymousActor is recorded for requests without an authenticated principal
+const anonymousActor = "anonymous"
+
+// secretConfigMarkers flag model config keys whose values are redacted in audit changes
+var secretConfigMarkers = []string{"key", "secret", "token", "password", "authorization", "credential"}
+
+type auditUsecase struct {
+	repository iAuditRepository
+}
+
+// RecordAuditEvent appends an event for an action the scope's actor performed on a resource
+func (u *auditUsecase) RecordAuditEvent(ctx context.Context, scope *entities.TenantScope, action entities.AuditAction, resourceType, resourceID string, changes []entities.AuditChange) errors.BaseError {
+	return u.repository.AppendAuditEvent(ctx, newAuditEvent(scope, action, resourceType, resourceID, changes))
+}
+
+// QueueAuditEvent records an event without waiting for the chain lock; Start appends it to
+// the chain within one chain interval
+func (u *auditUsecase) QueueAuditEvent(ctx context.Context, scope *entities.TenantScope, action entities.AuditAction, resourceType, resourceID string, changes []entities.AuditChange) errors.BaseError {
+	return u.repository.QueueAuditEvent(ctx, newAuditEvent(scope, action, resourceType, resourceID, changes))
+}
+
+// Start appends queued events to the chain every interval until ctx is cancelled
+func (u *auditUsecase) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := u.ChainQueuedAuditEvents(ctx); err != nil {
+			log.Printf("Warning: chaining queued audit events failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ChainQueuedAuditEvents appends every queued event to the chain, a batch per lock hold
+func (u *auditUsecase) ChainQueuedAuditEvents(ctx context.Context) errors.BaseError {
+	for {
+		chained, err := u.repository.ChainQueuedAuditEvents(ctx, auditChainBatchSize)
+		if err != nil {
+			return err
+		}
+		if chained < auditChainBatchSize {
+			return nil
+		}
+	}
+}
+
+// newAuditEvent builds an event for an action the scope's actor performed on a resource
+func newAuditEvent(scope *entities.TenantScope, action entities.AuditAction, resourceType, resourceID string, changes []entities.AuditChange) *entities.AuditEvent {
+	event := &entities.AuditEvent{
+		ID:           uuid.New().String(),
+		Actor:        actorIdentity(scope),
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Changes:      changes,
+		OccurredAt:   time.Now().UTC().Truncate(time.Microsecond),
+	}
+	if scope != nil {
+		event.TenantID = scope.TenantID
+		if actor := scope.Actor; actor != nil {
+			event.SourceIP = actor.SourceIP
+			event.RequestMetadata = actor.Metadata
+		}
+	}
+	return event
+}
+
+// ListAuditEvents lists audit events. Tenant-scoped callers only see their own tenant's events.
+func (u *auditUsecase) ListAuditEvents(ctx context.Context, scope *entities.TenantScope, filter *entities.AuditEventFilter) ([]*entities.AuditEvent, int64, errors.BaseError) {
+	if !scope.IsPlatform() {
+		filter.TenantID = scope.TenantID
+	}
+	if !filter.StartTime.IsZero() && !filter.EndTime.IsZero() && !filter.StartTime.Before(filter.EndTime) {
+		return nil, 0, errors.BadRequest(constants.ErrInvalidTimeRange)
+	}
+	return u.repository.ListAuditEvents(ctx, filter)
+}
+
+// VerifyAuditChain recomputes every event's hash from the start of the log and reports the
+// first event that was altered, or that follows a removed event
+func (u *auditUsecase) VerifyAuditChain(ctx context.Context, scope *entities.TenantScope) (*entities.AuditChainVerification, errors.BaseError) {
+	if !scope.IsPlatform() {
+		return nil, errors.Forbidden(constants.ErrTenantAccessDenied)
+	}
+
+	result := &entities.AuditChainVerification{Verified: true}
+	var prevHash string
+	var after int64
+	for {
+		events, err := u.repository.ListAuditChain(ctx, after, auditChainBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			hash, hashErr := event.ComputeHash(prevHash)
+			if hashErr != nil || event.PrevHash != prevHash || event.Hash != hash {
+				result.Verified = false
+				result.BrokenSequence = event.Sequence
+				return result, nil
+			}
+			prevHash = event.Hash
+			after = event.Sequence
+			result.Checked++
+		}
+		if len(events) < auditChainBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// recordAudit records an audit event for a model change that has already been applied. A failure
+// is logged rather than returned, since the caller cannot undo the change.
+func recordAudit(ctx context.Context, recorder iAuditRecorder, scope *entities.TenantScope, action entities.AuditAction, resourceID string, changes []entities.AuditChange) {
+	recordResourceAudit(ctx, recorder, scope, action, entities.AuditResourceModel, resourceID, changes)
+}
+
+// recordResourceAudit is recordAudit for any resource type
+func recordResourceAudit(ctx context.Context, recorder iAuditRecorder, scope *entities.TenantScope, action entities.AuditAction, resourceType, resourceID string, changes []entities.AuditChange) {
+	if recorder == nil {
+		return
+	}
+	if err := recorder.RecordAuditEvent(ctx, scope, action, resourceType, resourceID, changes); err != nil {
+		log.Printf("Failed to record %s audit event for %s %s: %v", action, resourceType, resourceID, err)
+	}
+}
+
+// actorIdentity returns the authenticated principal of the scope, or anonymousActor
+func actorIdentity(scope *entities.TenantScope) string {
+	if scope != nil && scope.Actor != nil && scope.Actor.Identity != "" {
+		return scope.Actor.Identity
+	}
+	return anonymousActor
+}
+
+// modelChanges lists the fields that differ between two versions of a model. A nil before
+// describes a creation and a nil after a deletion. Secrets are never recorded, only that they changed.
+func modelChanges(before, after *entities.AIModel) []entities.AuditChange {
+	if before == nil {
+		before = &entities.AIModel{}
+	}
+	if after == nil {
+		after = &entities.AIModel{}
+	}
+
+	var changes []entities.AuditChange
+	add := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, entities.AuditChange{Field: field, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	add("tenant_id", before.TenantID, after.TenantID)
+	add("name", before.Name, after.Name)
+	add("provider", before.Provider, after.Provider)
+	add("model_id", before.ModelID, after.ModelID)
+	add("base_url", before.BaseURL, after.BaseURL)
+	add("secret_backend", string(before.SecretBackend), string(after.SecretBackend))
+	add("quota_daily", strconv.FormatInt(before.QuotaDaily, 10), strconv.FormatInt(after.QuotaDaily, 10))
+	add("quota_monthly", strconv.FormatInt(before.QuotaMonthly, 10), strconv.FormatInt(after.QuotaMonthly, 10))
+	add("cost_per_1k_tokens", before.CostPer1kTokens.String(), after.CostPer1kTokens.String())
+	add("status", string(before.Status), string(after.Status))
+	add("source", string(before.Source), string(after.Source))
+	add("supports_tools", strconv.FormatBool(before.Capabilities.Tools), strconv.FormatBool(after.Capabilities.Tools))
+	add("supports_vision", strconv.FormatBool(before.Capabilities.Vision), strconv.FormatBool(after.Capabilities.Vision))
+	add("supports_json_mode", strconv.FormatBool(before.Capabilities.JSONMode), strconv.FormatBool(after.Capabilities.JSONMode))
+	add("supports_streaming", strconv.FormatBool(before.Capabilities.Streaming), strconv.FormatBool(after.Capabilities.Streaming))
+	add("context_window", strconv.Itoa(int(before.Limits.ContextWindow)), strconv.Itoa(int(after.Limits.ContextWindow)))
+	add("max_output_tokens", strconv.Itoa(int(before.Limits.MaxOutputTokens)), strconv.Itoa(int(after.Limits.MaxOutputTokens)))
+	add("fallback_models", strings.Join(before.FallbackModels, ","), strings.Join(after.FallbackModels, ","))
+	add("tier", string(before.Tier), string(after.Tier))
+	beforeAnnounced, beforeSunset, beforeReplacement := deprecationValues(before.Deprecation)
+	afterAnnounced, afterSunset, afterReplacement := deprecationValues(after.Deprecation)
+	add("deprecated_at", beforeAnnounced, afterAnnounced)
+	add("sunset_at", beforeSunset, afterSunset)
+	add("replacement_id", beforeReplacement, afterReplacement)
+	if before.EncryptedAPIKey != "" && after.EncryptedAPIKey != "" && before.EncryptedAPIKey != after.EncryptedAPIKey {
+		// A rotated key is recorded without either value
+		changes = append(changes, entities.AuditChange{Field: "api_key", OldValue: entities.AuditRedacted, NewValue: entities.AuditRedacted})
+	} else {
+		add("api_key", redactedIfSet(before.EncryptedAPIKey), redactedIfSet(after.EncryptedAPIKey))
+	}
+
+	keys := slices.Collect(maps.Keys(before.Config))
+	for key := range after.Config {
+		if _, ok := before.Config[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		oldValue, newValue := before.Config[key], after.Config[key]
+		if oldValue == newValue {
+			continue
+		}
+		if isSecretConfigKey(key) {
+			// Record that the secret changed without either value
+			oldValue, newValue = redactedIfSet(oldValue), redactedIfSet(newValue)
+		}
+		changes = append(changes, entities.AuditChange{Field: "config." + key, OldValue: oldValue, NewValue: newValue})
+	}
+	return changes
+}
+
+// deprecationValues renders a model's deprecation for the audit log; all empty when it has none
+func deprecationValues(deprecation *entities.ModelDeprecation) (announcedAt, sunsetAt, replacementID string) {
+	if deprecation == nil {
+		return "", "", ""
+	}
+	if !deprecation.AnnouncedAt.IsZero() {
+		announcedAt = deprecation.AnnouncedAt.UTC().Format(time.RFC3339)
+	}
+	if deprecation.SunsetAt != nil {
+		sunsetAt = deprecation.SunsetAt.UTC().Format(time.RFC3339)
+	}
+	return announcedAt, sunsetAt, deprecation.ReplacementID
+}
+
+// pricingChanges describes a new pricing version
+func pricingChanges(pricing *entities.ModelPricing) []entities.AuditChange {
+	return []entities.AuditChange{
+		{Field: "pricing_id", NewValue: pricing.ID},
+		{Field: "input_per_1k_tokens", NewValue: pricing.InputPer1kTokens.String()},
+		{Field: "output_per_1k_tokens", NewValue: pricing.OutputPer1kTokens.String()},
+		{Field: "cached_read_per_1k_tokens", NewValue: pricing.CachedReadPer1kTokens.String()},
+		{Field: "cache_write_per_1k_tokens", NewValue: pricing.CacheWritePer1kTokens.String()},
+		{Field: "per_image", NewValue: pricing.PerImage.String()},
+		{Field: "per_request", NewValue: pricing.PerRequest.String()},
+		{Field: "effective_from", NewValue: pricing.EffectiveFrom.UTC().Format(time.RFC3339)},
+	}
+}
+
+// credentialChanges names the pool key handed out, if any; the key itself is never recorded
+func credentialChanges(keyID string) []entities.AuditChange {
+	if keyID == "" {
+		return nil
+	}
+	return []entities.AuditChange{{Field: "key_id", NewValue: keyID}}
+}
+
+func redactedIfSet(value string) string {
+	if value == "" {
+		return ""
+	}
+	return entities.AuditRedacted
+}
+
+func isSecretConfigKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, marker := range secretConfigMarkers {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,275 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/shopspring/decimal"
+)
+
+type budgetUsecase struct {
+	repository       iBudgetRepository
+	modelRepository  iModelRepository
+	tenantRepository iTenantRepository
+	notifier         iBudgetNotifier
+
+	// pending holds the budget queries touched by usage logged since the last evaluation
+	mu      sync.Mutex
+	pending map[entities.BudgetQuery]struct{}
+}
+
+// budgetEvaluationTimeout bounds one evaluation of the budgets touched by logged usage
+const budgetEvaluationTimeout = 30 * time.Second
+
+// CreateBudget creates a USD budget. Tenant-scoped callers may only budget their own tenant's spend.
+func (u *budgetUsecase) CreateBudget(ctx context.Context, scope *entities.TenantScope, payload *entities.CreateBudgetPayload) (*entities.Budget, errors.BaseError) {
+	if payload.Period == "" {
+		payload.Period = entities.BudgetPeriodMonthly
+	}
+	if payload.Period != entities.BudgetPeriodDaily && payload.Period != entities.BudgetPeriodMonthly {
+		return nil, errors.BadRequest(constants.ErrInvalidBudgetPeriod)
+	}
+	if !payload.LimitUSD.IsPositive() {
+		return nil, errors.BadRequest(constants.ErrInvalidBudgetLimit)
+	}
+	thresholds, err := normalizeThresholds(payload.Thresholds)
+	if err != nil {
+		return nil, err
+	}
+	payload.Thresholds = thresholds
+
+	if !scope.IsPlatform() {
+		if payload.TenantID != "" && payload.TenantID != scope.TenantID {
+			return nil, errors.Forbidden(constants.ErrTenantAccessDenied)
+		}
+		payload.TenantID = scope.TenantID
+	}
+
+	switch payload.Scope {
+	case entities.BudgetScopeTenant:
+		if payload.TenantID != "" && payload.TenantID != payload.ScopeID {
+			return nil, errors.Forbidden(constants.ErrTenantAccessDenied)
+		}
+		if _, err := u.tenantRepository.GetTenant(ctx, payload.ScopeID); err != nil {
+			return nil, err
+		}
+		payload.TenantID = payload.ScopeID
+	case entities.BudgetScopeProject:
+		project, err := u.tenantRepository.GetProject(ctx, payload.ScopeID)
+		if err != nil {
+			return nil, err
+		}
+		if payload.TenantID != "" && project.TenantID != payload.TenantID {
+			return nil, errors.NotFound(constants.ErrProjectNotFound)
+		}
+		payload.TenantID = project.TenantID
+	case entities.BudgetScopeModel:
+		model, err := u.modelRepository.GetModel(ctx, payload.ScopeID)
+		if err != nil {
+			return nil, err
+		}
+		if !scope.CanAccessTenant(model.TenantID) {
+			return nil, errors.NotFound(constants.ErrModelNotFound)
+		}
+		payload.ScopeID = model.ID
+	case entities.BudgetScopeUser:
+	default:
+		return nil, errors.BadRequest(constants.ErrInvalidBudgetScope)
+	}
+
+	return u.repository.CreateBudget(ctx, payload)
+}
+
+// ListBudgets lists budgets visible to the caller
+func (u *budgetUsecase) ListBudgets(ctx context.Context, scope *entities.TenantScope) ([]*entities.Budget, errors.BaseError) {
+	tenantID := ""
+	if !scope.IsPlatform() {
+		tenantID = scope.TenantID
+	}
+	return u.repository.ListBudgets(ctx, tenantID)
+}
+
+// DeleteBudget deletes a budget owned by the caller's tenant
+func (u *budgetUsecase) DeleteBudget(ctx context.Context, scope *entities.TenantScope, id string) errors.BaseError {
+	tenantID := ""
+	if !scope.IsPlatform() {
+		tenantID = scope.TenantID
+	}
+	return u.repository.DeleteBudget(ctx, id, tenantID)
+}
+
+// GetBudgetStatus reports current spend against every budget applying to the request context.
+// A model the caller cannot see is not found, like everywhere else.
+func (u *budgetUsecase) GetBudgetStatus(ctx context.Context, scope *entities.TenantScope, query *entities.BudgetQuery) ([]*entities.BudgetStatus, errors.BaseError) {
+	if !scope.IsPlatform() {
+		query.TenantID = scope.TenantID
+		if scope.ProjectID != "" {
+			query.ProjectID = scope.ProjectID
+		}
+	}
+	if query.ModelID != "" {
+		model, err := u.modelRepository.GetModel(ctx, query.ModelID)
+		if err != nil {
+			return nil, err
+		}
+		if !scope.CanAccessTenant(model.TenantID) {
+			return nil, errors.NotFound(constants.ErrModelNotFound)
+		}
+		query.ModelID = model.ID
+	}
+
+	budgets, err := u.repository.FindApplicableBudgets(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	statuses := make([]*entities.BudgetStatus, 0, len(budgets))
+	for _, budget := range budgets {
+		status, err := u.budgetStatus(ctx, budget, now)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// QueueUsage marks the budgets touched by a usage record for the next evaluation. Usage with
+// the same tenant, project, user and model is evaluated once however many records it has.
+func (u *budgetUsecase) QueueUsage(payload *entities.LogUsagePayload) {
+	query := entities.BudgetQuery{
+		TenantID:  payload.TenantID,
+		ProjectID: payload.ProjectID,
+		UserID:    payload.UserID,
+		ModelID:   payload.ModelID,
+	}
+	u.mu.Lock()
+	u.pending[query] = struct{}{}
+	u.mu.Unlock()
+}
+
+// Start evaluates the budgets touched by queued usage every interval until ctx is cancelled
+func (u *budgetUsecase) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		u.mu.Lock()
+		pending := u.pending
+		u.pending = make(map[entities.BudgetQuery]struct{})
+		u.mu.Unlock()
+
+		for query := range pending {
+			evalCtx, cancel := context.WithTimeout(ctx, budgetEvaluationTimeout)
+			u.evaluateBudgets(evalCtx, &query)
+			cancel()
+		}
+	}
+}
+
+// evaluateBudgets checks the budgets applying to a query and sends an alert for every
+// threshold crossed for the first time in the current period.
+func (u *budgetUsecase) evaluateBudgets(ctx context.Context, query *entities.BudgetQuery) {
+	statuses, err := u.GetBudgetStatus(ctx, &entities.TenantScope{Platform: true}, query)
+	if err != nil {
+		log.Printf("Warning: failed to evaluate budgets: %v", err)
+		return
+	}
+
+	for _, status := range statuses {
+		for _, threshold := range status.Budget.Thresholds {
+			if status.PercentUsed < float64(threshold) {
+				break
+			}
+
+			recorded, err := u.repository.RecordBudgetAlert(ctx, status.Budget.ID, status.PeriodStart, threshold, status.SpentUSD)
+			if err != nil {
+				log.Printf("Warning: failed to record budget alert for %s: %v", status.Budget.ID, err)
+				continue
+			}
+			if !recorded {
+				continue
+			}
+
+			alert := &entities.BudgetAlert{
+				BudgetID:    status.Budget.ID,
+				TenantID:    status.Budget.TenantID,
+				Scope:       status.Budget.Scope,
+				ScopeID:     status.Budget.ScopeID,
+				Period:      status.Budget.Period,
+				Threshold:   threshold,
+				LimitUSD:    status.Budget.LimitUSD,
+				SpentUSD:    status.SpentUSD,
+				HardStop:    status.Budget.HardStop,
+				PeriodStart: status.PeriodStart,
+				TriggeredAt: time.Now(),
+			}
+			if err := u.notifier.NotifyBudgetAlert(ctx, status.Budget.WebhookURL, alert); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}
+	}
+}
+
+// budgetStatus computes spend against a budget for the period containing now
+func (u *budgetUsecase) budgetStatus(ctx context.Context, budget *entities.Budget, now time.Time) (*entities.BudgetStatus, errors.BaseError) {
+	start, end := budget.PeriodBounds(now)
+	spent, err := u.repository.GetBudgetSpend(ctx, budget, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := budget.LimitUSD.Sub(spent)
+	if remaining.IsNegative() {
+		remaining = decimal.Zero
+	}
+	percent, _ := spent.Div(budget.LimitUSD).Mul(decimal.NewFromInt(100)).Float64()
+	exceeded := spent.GreaterThanOrEqual(budget.LimitUSD)
+
+	return &entities.BudgetStatus{
+		Budget:       budget,
+		SpentUSD:     spent,
+		RemainingUSD: remaining,
+		PercentUsed:  percent,
+		Exceeded:     exceeded,
+		Blocked:      exceeded && budget.HardStop,
+		PeriodStart:  start,
+		PeriodEnd:    end,
+	}, nil
+}
+
+// normalizeThresholds validates alert thresholds and returns them sorted and de-duplicated
+func normalizeThresholds(thresholds []int32) ([]int32, errors.BaseError) {
+	if len(thresholds) == 0 {
+		return entities.DefaultBudgetThresholds, nil
+	}
+
+	seen := make(map[int32]bool, len(thresholds))
+	normalized := make([]int32, 0, len(thresholds))
+	for _, threshold := range thresholds {
+		if threshold < 1 || threshold > 100 {
+			return nil, errors.BadRequest(constants.ErrInvalidBudgetThreshold)
+		}
+		if !seen[threshold] {
+			seen[threshold] = true
+			normalized = append(normalized, threshold)
+		}
+	}
+	sort.Slice(normalized, func(i, j int) bool { return normalized[i] < normalized[j] })
+
+	return normalized, nil
+}
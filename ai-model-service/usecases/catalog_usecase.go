@@ -0,0 +1,410 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// catalogCapabilities maps the capability names used in the catalog to their flag
+var catalogCapabilities = map[string]func(*entities.ModelCapabilities) *bool{
+	"tools":     func(c *entities.ModelCapabilities) *bool { return &c.Tools },
+	"vision":    func(c *entities.ModelCapabilities) *bool { return &c.Vision },
+	"json_mode": func(c *entities.ModelCapabilities) *bool { return &c.JSONMode },
+	"streaming": func(c *entities.ModelCapabilities) *bool { return &c.Streaming },
+}
+
+type catalogUsecase struct {
+	repository   iCatalogRepository
+	secretStores iSecretStores
+	audit        iAuditRecorder
+	events       iModelEventPublisher
+	// lookupEnv resolves the environment variables named by api_key_env
+	lookupEnv func(name string) string
+}
+
+// catalogStep is one planned change together with what is needed to apply it
+type catalogStep struct {
+	change *entities.CatalogChange
+	entry  *entities.CatalogModel
+	// existing and model are the model before and after the change; existing is nil for a new model
+	existing *entities.AIModel
+	model    *entities.AIModel
+	apiKey   string
+}
+
+// PlanCatalog diffs the catalog against the stored models without changing anything
+func (u *catalogUsecase) PlanCatalog(ctx context.Context, catalog *entities.ModelCatalog) (*entities.CatalogPlan, errors.BaseError) {
+	steps, err := u.plan(ctx, catalog)
+	if err != nil {
+		return nil, err
+	}
+	return planOf(steps), nil
+}
+
+// ApplyCatalog creates and updates the models the catalog lists and deprecates catalog models
+// it no longer lists. Models are changed one at a time; the first failure stops the apply.
+func (u *catalogUsecase) ApplyCatalog(ctx context.Context, scope *entities.TenantScope, catalog *entities.ModelCatalog) (*entities.CatalogPlan, errors.BaseError) {
+	steps, err := u.plan(ctx, catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, step := range steps {
+		var applyErr errors.BaseError
+		switch step.change.Action {
+		case entities.CatalogActionCreate:
+			applyErr = u.create(ctx, scope, step)
+		case entities.CatalogActionUpdate, entities.CatalogActionDeprecate:
+			applyErr = u.update(ctx, scope, step)
+		}
+		if applyErr != nil {
+			return planOf(steps), errors.Internal(fmt.Errorf(constants.ErrFailedToApplyCatalog, step.change.ModelName, applyErr))
+		}
+	}
+	return planOf(steps), nil
+}
+
+// plan validates the catalog and works out the change to each model
+func (u *catalogUsecase) plan(ctx context.Context, catalog *entities.ModelCatalog) ([]*catalogStep, errors.BaseError) {
+	listed := make(map[string]bool, len(catalog.Models))
+	for i := range catalog.Models {
+		entry := &catalog.Models[i]
+		if err := validateCatalogModel(catalog, entry); err != nil {
+			return nil, err
+		}
+		if listed[entry.Name] {
+			return nil, errors.BadRequest(fmt.Sprintf(constants.ErrDuplicateCatalogModel, entry.Name))
+		}
+		listed[entry.Name] = true
+	}
+
+	var steps []*catalogStep
+	for i := range catalog.Models {
+		step, err := u.planModel(ctx, catalog, &catalog.Models[i])
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+
+	// Catalog models that were removed from the file are deprecated, never deleted,
+	// so callers still using them keep working and their usage history is kept
+	managed, err := u.repository.ListModelsBySource(ctx, entities.ModelSourceCatalog)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range managed {
+		if listed[existing.Name] || existing.Status == entities.ModelStatusDeprecated {
+			continue
+		}
+		model := *existing
+		model.Status = entities.ModelStatusDeprecated
+		steps = append(steps, &catalogStep{
+			change: &entities.CatalogChange{
+				Action:    entities.CatalogActionDeprecate,
+				ModelName: existing.Name,
+				ModelID:   existing.ID,
+				Changes:   modelChanges(existing, &model),
+			},
+			existing: existing,
+			model:    &model,
+		})
+	}
+	return steps, nil
+}
+
+// planModel works out the change to the model of one catalog entry
+func (u *catalogUsecase) planModel(ctx context.Context, catalog *entities.ModelCatalog, entry *entities.CatalogModel) (*catalogStep, errors.BaseError) {
+	step := &catalogStep{
+		change: &entities.CatalogChange{ModelName: entry.Name},
+		entry:  entry,
+	}
+
+	existing, err := u.repository.GetModelByName(ctx, entry.Name)
+	if err != nil && err.GetCode() != errors.NOT_FOUND {
+		return nil, err
+	}
+	if existing == nil {
+		secretBackend := entry.SecretBackend
+		if secretBackend == "" {
+			secretBackend = u.secretStores.DefaultBackend()
+		}
+		if _, err := u.secretStores.Store(secretBackend); err != nil {
+			return nil, err
+		}
+		step.model = catalogModel(catalog, entry, &entities.AIModel{
+			Name:          entry.Name,
+			SecretBackend: secretBackend,
+			Status:        entities.ModelStatusActive,
+			Capabilities:  entities.ModelCapabilities{Streaming: true},
+		})
+		if env := catalogAPIKeyEnv(catalog, entry); env != "" && u.lookupEnv != nil {
+			step.apiKey = u.lookupEnv(env)
+		}
+		if step.apiKey == "" {
+			step.change.Warning = constants.ErrCatalogModelWithoutAPIKey
+		} else {
+			// Only marks that a key will be set, so the planned changes list it
+			step.model.EncryptedAPIKey = entities.AuditRedacted
+		}
+		if err := validateModelLimits(&step.model.Limits); err != nil {
+			return nil, errors.BadRequest(fmt.Sprintf(constants.ErrInvalidCatalogModel, entry.Name, err.Error()))
+		}
+		step.change.Action = entities.CatalogActionCreate
+		step.change.Changes = modelChanges(nil, step.model)
+		step.change.Pricing = entry.Pricing
+		return step, nil
+	}
+
+	// Names are unique across tenants, so a tenant's model blocks the catalog entry
+	step.change.ModelID = existing.ID
+	step.existing = existing
+	if existing.TenantID != "" {
+		step.change.Action = entities.CatalogActionSkip
+		step.change.Warning = fmt.Sprintf(constants.ErrCatalogNameTaken, existing.TenantID)
+		return step, nil
+	}
+
+	step.model = catalogModel(catalog, entry, existing)
+	if err := validateModelLimits(&step.model.Limits); err != nil {
+		return nil, errors.BadRequest(fmt.Sprintf(constants.ErrInvalidCatalogModel, entry.Name, err.Error()))
+	}
+	step.change.Changes = modelChanges(existing, step.model)
+	if entry.Pricing != nil {
+		pricing, err := u.repository.GetEffectivePricing(ctx, existing.ID, time.Now())
+		if err != nil && err.GetCode() != errors.NOT_FOUND {
+			return nil, err
+		}
+		if !entry.Pricing.Matches(pricing) {
+			step.change.Pricing = entry.Pricing
+		}
+	}
+
+	step.change.Action = entities.CatalogActionUnchanged
+	if len(step.change.Changes) > 0 || step.change.Pricing != nil {
+		step.change.Action = entities.CatalogActionUpdate
+	}
+	return step, nil
+}
+
+// create stores a new catalog model, with the key from its api_key_env when there is one
+func (u *catalogUsecase) create(ctx context.Context, scope *entities.TenantScope, step *catalogStep) errors.BaseError {
+	store, err := u.secretStores.Store(step.model.SecretBackend)
+	if err != nil {
+		return err
+	}
+
+	payload := &entities.CreateModelPayload{
+		ID:              uuid.New().String(),
+		Name:            step.model.Name,
+		Provider:        step.model.Provider,
+		ModelID:         step.model.ModelID,
+		BaseURL:         step.model.BaseURL,
+		APIKey:          step.apiKey,
+		SecretBackend:   step.model.SecretBackend,
+		Config:          step.model.Config,
+		QuotaDaily:      step.model.QuotaDaily,
+		QuotaMonthly:    step.model.QuotaMonthly,
+		CostPer1kTokens: step.model.CostPer1kTokens,
+		Status:          step.model.Status,
+		Source:          entities.ModelSourceCatalog,
+		Capabilities:    step.model.Capabilities,
+		Limits:          step.model.Limits,
+		FallbackModels:  step.model.FallbackModels,
+		Tier:            step.model.Tier,
+	}
+	var storedKey string
+	if step.apiKey != "" {
+		if storedKey, err = store.Put(ctx, payload.ID, step.apiKey); err != nil {
+			return err
+		}
+	}
+
+	model, err := u.repository.CreateModel(ctx, payload, storedKey)
+	if err != nil {
+		if storedKey != "" {
+			if err := store.Delete(ctx, storedKey); err != nil {
+				log.Printf("Failed to remove api key of unsaved model %s: %v", payload.ID, err)
+			}
+		}
+		return err
+	}
+	step.change.ModelID = model.ID
+	recordAudit(ctx, u.audit, scope, entities.AuditActionModelCreated, model.ID, modelChanges(nil, model))
+
+	return u.setPricing(ctx, scope, model, step.change.Pricing)
+}
+
+// update writes the catalog fields of an existing model and adds its new pricing version
+func (u *catalogUsecase) update(ctx context.Context, scope *entities.TenantScope, step *catalogStep) errors.BaseError {
+	if len(step.change.Changes) > 0 {
+		model, err := u.repository.SaveCatalogModel(ctx, step.model)
+		if err != nil {
+			return err
+		}
+		recordAudit(ctx, u.audit, scope, entities.AuditActionModelUpdated, model.ID, modelChanges(step.existing, model))
+		if u.events != nil {
+			publishModelEvent(ctx, u.events, entities.ModelEventUpdated, model)
+		}
+	}
+	return u.setPricing(ctx, scope, step.model, step.change.Pricing)
+}
+
+// setPricing adds a pricing version effective now; nil pricing is a no-op
+func (u *catalogUsecase) setPricing(ctx context.Context, scope *entities.TenantScope, model *entities.AIModel, pricing *entities.CatalogPricing) errors.BaseError {
+	if pricing == nil {
+		return nil
+	}
+	version, err := u.repository.CreatePricing(ctx, &entities.SetModelPricingPayload{
+		ModelID:               model.ID,
+		InputPer1kTokens:      pricing.InputPer1kTokens,
+		OutputPer1kTokens:     pricing.OutputPer1kTokens,
+		CachedReadPer1kTokens: pricing.CachedReadPer1kTokens,
+		CacheWritePer1kTokens: pricing.CacheWritePer1kTokens,
+		PerImage:              pricing.PerImage,
+		PerRequest:            pricing.PerRequest,
+	})
+	if err != nil {
+		return err
+	}
+	recordAudit(ctx, u.audit, scope, entities.AuditActionPricingSet, model.ID, pricingChanges(version))
+	return nil
+}
+
+// catalogModel applies a catalog entry to a copy of model. Fields the entry leaves unset keep
+// their stored value, and the API key and secret backend are never changed.
+func catalogModel(catalog *entities.ModelCatalog, entry *entities.CatalogModel, model *entities.AIModel) *entities.AIModel {
+	updated := *model
+	updated.Provider = entry.Provider
+	updated.ModelID = entry.ModelID
+	updated.BaseURL = entry.BaseURL
+	if updated.BaseURL == "" {
+		updated.BaseURL = catalog.Providers[entry.Provider].BaseURL
+	}
+	updated.Source = entities.ModelSourceCatalog
+	if entry.Status != "" {
+		updated.Status = entry.Status
+	}
+	if entry.Limits.QuotaDaily != nil {
+		updated.QuotaDaily = *entry.Limits.QuotaDaily
+	}
+	if entry.Limits.QuotaMonthly != nil {
+		updated.QuotaMonthly = *entry.Limits.QuotaMonthly
+	}
+	if entry.Limits.ContextWindow != nil {
+		updated.Limits.ContextWindow = *entry.Limits.ContextWindow
+	}
+	if entry.Limits.MaxOutputTokens != nil {
+		updated.Limits.MaxOutputTokens = *entry.Limits.MaxOutputTokens
+	}
+	if entry.CostPer1kTokens != nil {
+		updated.CostPer1kTokens = *entry.CostPer1kTokens
+	}
+	if entry.Capabilities != nil {
+		updated.Capabilities = entities.ModelCapabilities{}
+		for _, name := range entry.Capabilities {
+			*catalogCapabilities[name](&updated.Capabilities) = true
+		}
+	}
+	if entry.Fallbacks != nil {
+		updated.FallbackModels = slices.Clone(entry.Fallbacks)
+	}
+	if entry.Tier != nil {
+		updated.Tier = *entry.Tier
+	}
+
+	updated.Config = maps.Clone(model.Config)
+	if entry.Config != nil {
+		updated.Config = maps.Clone(entry.Config)
+	}
+	return &updated
+}
+
+// catalogAPIKeyEnv returns the environment variable holding the key for a new catalog model
+func catalogAPIKeyEnv(catalog *entities.ModelCatalog, entry *entities.CatalogModel) string {
+	if entry.APIKeyEnv != "" {
+		return entry.APIKeyEnv
+	}
+	return catalog.Providers[entry.Provider].APIKeyEnv
+}
+
+// validateCatalogModel checks a catalog entry can be applied
+func validateCatalogModel(catalog *entities.ModelCatalog, entry *entities.CatalogModel) errors.BaseError {
+	invalid := func(reason string) errors.BaseError {
+		return errors.BadRequest(fmt.Sprintf(constants.ErrInvalidCatalogModel, entry.Name, reason))
+	}
+
+	switch {
+	case entry.Name == "":
+		return invalid(constants.ErrInvalidModelName)
+	case entry.Provider == "":
+		return invalid(constants.ErrInvalidProvider)
+	case entry.ModelID == "":
+		return invalid("model_id is required")
+	case entry.BaseURL == "" && catalog.Providers[entry.Provider].BaseURL == "":
+		return invalid(constants.ErrInvalidBaseURL)
+	}
+	switch entry.Status {
+	case "", entities.ModelStatusActive, entities.ModelStatusDisabled, entities.ModelStatusDeprecated:
+	default:
+		return invalid(fmt.Sprintf("unknown status %q", entry.Status))
+	}
+	if (entry.Limits.QuotaDaily != nil && *entry.Limits.QuotaDaily < 0) ||
+		(entry.Limits.QuotaMonthly != nil && *entry.Limits.QuotaMonthly < 0) {
+		return invalid("quotas must not be negative")
+	}
+	if (entry.Limits.ContextWindow != nil && *entry.Limits.ContextWindow < 0) ||
+		(entry.Limits.MaxOutputTokens != nil && *entry.Limits.MaxOutputTokens < 0) {
+		return invalid(constants.ErrInvalidModelLimits)
+	}
+	for _, name := range entry.Capabilities {
+		if catalogCapabilities[name] == nil {
+			return invalid(fmt.Sprintf("unknown capability %q", name))
+		}
+	}
+	if entry.Tier != nil && !entry.Tier.Valid() {
+		return invalid(fmt.Sprintf(constants.ErrInvalidModelTier, *entry.Tier))
+	}
+	for _, fallback := range entry.Fallbacks {
+		if fallback == entry.Name {
+			return invalid(fmt.Sprintf(constants.ErrInvalidFallback, fallback, "must name another model"))
+		}
+	}
+	if entry.CostPer1kTokens != nil && entry.CostPer1kTokens.IsNegative() {
+		return invalid(constants.ErrInvalidPricing)
+	}
+	if p := entry.Pricing; p != nil {
+		for _, price := range []decimal.Decimal{
+			p.InputPer1kTokens,
+			p.OutputPer1kTokens,
+			p.CachedReadPer1kTokens,
+			p.CacheWritePer1kTokens,
+			p.PerImage,
+			p.PerRequest,
+		} {
+			if price.IsNegative() {
+				return invalid(constants.ErrInvalidPricing)
+			}
+		}
+	}
+	return nil
+}
+
+// planOf collects the changes of the planned steps
+func planOf(steps []*catalogStep) *entities.CatalogPlan {
+	plan := &entities.CatalogPlan{}
+	for _, step := range steps {
+		plan.Changes = append(plan.Changes, step.change)
+	}
+	return plan
+}
@@ -1,17 +1,169 @@
 package usecases
 
-import "github.com/blcvn/backend/services/ai-model-service/helper"
+import (
+	"strings"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/blcvn/backend/services/ai-model-service/helper"
+)
 
 // NewModelUsecase creates a new model usecase
 // NewModelUsecase creates a new model usecase
 func NewModelUsecase(
 	repository iModelRepository,
-	vaultClient iVaultClient,
-	crypto helper.CryptoHelpers,
+	tenantRepository iTenantRepository,
+	secretStores iSecretStores,
+	budgetEvaluator iBudgetEvaluator,
+	keySelector iKeySelector,
+	events iModelEventBus,
+	audit iAuditRecorder,
+	aliasRepository iModelAliasRepository,
+	allowRawCredentials bool,
 ) *modelUsecase {
 	return &modelUsecase{
-		repository:  repository,
-		vaultClient: vaultClient,
-		crypto:      crypto,
+		repository:          repository,
+		tenantRepository:    tenantRepository,
+		secretStores:        secretStores,
+		budgetEvaluator:     budgetEvaluator,
+		keySelector:         keySelector,
+		events:              events,
+		audit:               audit,
+		aliasRepository:     aliasRepository,
+		allowRawCredentials: allowRawCredentials,
+	}
+}
+
+// NewAuditUsecase creates the audit log usecase
+func NewAuditUsecase(repository iAuditRepository) *auditUsecase {
+	return &auditUsecase{
+		repository: repository,
+	}
+}
+
+// NewCatalogUsecase creates the usecase that applies the model catalog. lookupEnv resolves the
+// api_key_env variables giving new catalog models their key, e.g. os.Getenv.
+func NewCatalogUsecase(
+	repository iCatalogRepository,
+	secretStores iSecretStores,
+	audit iAuditRecorder,
+	events iModelEventPublisher,
+	lookupEnv func(name string) string,
+) *catalogUsecase {
+	return &catalogUsecase{
+		repository:   repository,
+		secretStores: secretStores,
+		audit:        audit,
+		events:       events,
+		lookupEnv:    lookupEnv,
+	}
+}
+
+// NewAPIKeyUsecase creates a new virtual API key usecase
+func NewAPIKeyUsecase(repository iAPIKeyRepository, tenantRepository iTenantRepository) *apiKeyUsecase {
+	return &apiKeyUsecase{
+		repository:       repository,
+		tenantRepository: tenantRepository,
+	}
+}
+
+// NewTenantUsecase creates a new tenant usecase
+func NewTenantUsecase(repository iTenantRepository) *tenantUsecase {
+	return &tenantUsecase{
+		repository: repository,
+	}
+}
+
+// NewBudgetUsecase creates a new budget usecase
+func NewBudgetUsecase(
+	repository iBudgetRepository,
+	modelRepository iModelRepository,
+	tenantRepository iTenantRepository,
+	notifier iBudgetNotifier,
+) *budgetUsecase {
+	return &budgetUsecase{
+		repository:       repository,
+		modelRepository:  modelRepository,
+		tenantRepository: tenantRepository,
+		notifier:         notifier,
+		pending:          make(map[entities.BudgetQuery]struct{}),
+	}
+}
+
+// NewProviderKeyUsecase creates the provider key pool usecase
+func NewProviderKeyUsecase(
+	repository iProviderKeyRepository,
+	modelRepository iModelRepository,
+	secretStores iSecretStores,
+	events iModelEventPublisher,
+) *providerKeyUsecase {
+	return &providerKeyUsecase{
+		repository:      repository,
+		modelRepository: modelRepository,
+		secretStores:    secretStores,
+		events:          events,
+	}
+}
+
+// NewUsageRetentionUsecase creates the usage log maintenance usecase.
+// retentionMonths <= 0 keeps raw usage logs forever.
+func NewUsageRetentionUsecase(repository iUsageRetentionRepository, retentionMonths int) *usageRetentionUsecase {
+	return &usageRetentionUsecase{
+		repository:      repository,
+		retentionMonths: retentionMonths,
+	}
+}
+
+// NewModelPurgeUsecase creates the usecase purging deleted models.
+// retention <= 0 keeps deleted models forever.
+func NewModelPurgeUsecase(repository iModelPurgeRepository, audit iAuditRecorder, retention time.Duration) *modelPurgeUsecase {
+	return &modelPurgeUsecase{
+		repository: repository,
+		audit:      audit,
+		retention:  retention,
+	}
+}
+
+// NewRekeyUsecase creates the usecase that moves stored secrets onto the current master key
+func NewRekeyUsecase(repository iEncryptedSecretRepository, crypto helper.CryptoHelpers) *rekeyUsecase {
+	return &rekeyUsecase{
+		repository: repository,
+		crypto:     crypto,
+	}
+}
+
+// NewSecretMigrationUsecase creates the usecase that moves model secrets between backends
+func NewSecretMigrationUsecase(repository iEncryptedSecretRepository, modelRepository iModelRepository, secretStores iSecretStores) *secretMigrationUsecase {
+	return &secretMigrationUsecase{
+		repository:      repository,
+		modelRepository: modelRepository,
+		secretStores:    secretStores,
+	}
+}
+
+// NewCredentialLeaseUsecase creates the credential lease usecase. A nil signer disables leases.
+// egressURL is the externally reachable base URL of the egress, e.g. "https://ai-model:8086/egress".
+func NewCredentialLeaseUsecase(
+	credentialSelector iCredentialSelector,
+	modelRepository iModelRepository,
+	keyRepository iProviderKeyRepository,
+	secretStores iSecretStores,
+	audit iAuditRecorder,
+	signer *helper.LeaseSigner,
+	ttl time.Duration,
+	egressURL string,
+) *credentialLeaseUsecase {
+	u := &credentialLeaseUsecase{
+		credentialSelector: credentialSelector,
+		modelRepository:    modelRepository,
+		keyRepository:      keyRepository,
+		secretStores:       secretStores,
+		audit:              audit,
+		ttl:                ttl,
+		egressURL:          strings.TrimSuffix(egressURL, "/"),
+	}
+	if signer != nil {
+		u.signer = signer
 	}
+	return u
 }
@@ -0,0 +1,125 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/google/uuid"
+)
+
+type credentialLeaseUsecase struct {
+	credentialSelector iCredentialSelector
+	modelRepository    iModelRepository
+	keyRepository      iProviderKeyRepository
+	secretStores       iSecretStores
+	audit              iAuditRecorder
+	signer             iLeaseSigner
+	ttl                time.Duration
+	egressURL          string
+}
+
+// IssueCredentialLease grants the calling proxy short-lived use of a model's provider through
+// the egress. The key is picked from the pool now, so quarantine and weighting apply as they
+// do for GetCredentials, but the proxy only ever sees the signed lease.
+func (u *credentialLeaseUsecase) IssueCredentialLease(ctx context.Context, scope *entities.TenantScope, modelID, identity string) (*entities.IssuedCredentialLease, errors.BaseError) {
+	if u.signer == nil {
+		return nil, errors.BadRequest(constants.ErrCredentialLeasesDisabled)
+	}
+	if identity == "" {
+		return nil, errors.Unauthorized(constants.ErrProxyIdentityRequired)
+	}
+
+	model, key, err := u.credentialSelector.SelectCredential(ctx, scope, modelID)
+	if err != nil {
+		return nil, err
+	}
+
+	lease := &entities.CredentialLease{
+		ID:        uuid.New().String(),
+		ModelID:   model.ID,
+		Identity:  identity,
+		ExpiresAt: time.Now().Add(u.ttl).UTC(),
+	}
+	if key != nil {
+		lease.KeyID = key.ID
+	}
+
+	token, signErr := u.signer.Sign(lease)
+	if signErr != nil {
+		return nil, errors.Internal(fmt.Errorf(constants.ErrFailedToSignLease, signErr))
+	}
+
+	// Credential access is only granted once it is on record (queued, as for GetCredentials)
+	if u.audit != nil {
+		if err := u.audit.QueueAuditEvent(ctx, scope, entities.AuditActionCredentialsLeased, entities.AuditResourceModel, model.ID, credentialChanges(lease.KeyID)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &entities.IssuedCredentialLease{
+		Token:     token,
+		BaseURL:   u.egressURL + "/" + url.PathEscape(model.ID),
+		KeyID:     lease.KeyID,
+		ExpiresAt: lease.ExpiresAt,
+	}, nil
+}
+
+// ResolveCredentialLease redeems a lease at the egress. The lease must be unexpired, issued
+// for this model and presented by the proxy it was issued to, and its key must still be active.
+func (u *credentialLeaseUsecase) ResolveCredentialLease(ctx context.Context, token, modelID, identity string) (*entities.EgressTarget, errors.BaseError) {
+	if u.signer == nil {
+		return nil, errors.BadRequest(constants.ErrCredentialLeasesDisabled)
+	}
+	if identity == "" {
+		return nil, errors.Unauthorized(constants.ErrProxyIdentityRequired)
+	}
+
+	lease, verifyErr := u.signer.Verify(token, time.Now())
+	if verifyErr != nil {
+		return nil, errors.Unauthorized(fmt.Sprintf(constants.ErrInvalidCredentialLease, verifyErr))
+	}
+	if lease.ModelID != modelID || lease.Identity != identity {
+		return nil, errors.Forbidden(constants.ErrCredentialLeaseNotAllowed)
+	}
+
+	model, err := u.modelRepository.GetModel(ctx, lease.ModelID)
+	if err != nil {
+		return nil, err
+	}
+	if model.Status != entities.ModelStatusActive {
+		return nil, errors.Forbidden(fmt.Sprintf("model is not active: %s", model.Status))
+	}
+
+	encryptedKey := model.EncryptedAPIKey
+	if lease.KeyID != "" {
+		key, err := u.keyRepository.GetProviderKey(ctx, model.ID, lease.KeyID)
+		if err != nil {
+			return nil, err
+		}
+		if key.Status != entities.ProviderKeyStatusActive {
+			return nil, errors.Forbidden(constants.ErrCredentialLeaseNotAllowed)
+		}
+		encryptedKey = key.EncryptedKey
+	}
+
+	store, err := u.secretStores.Store(model.SecretBackend)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, err := store.Get(ctx, encryptedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.EgressTarget{
+		Provider: model.Provider,
+		BaseURL:  model.BaseURL,
+		APIKey:   apiKey,
+		Headers:  make(map[string]string),
+	}, nil
+}
@@ -0,0 +1,95 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+)
+
+// defaultDeprecatedCallersWindow is how far back the deprecated model callers report looks by default
+const defaultDeprecatedCallersWindow = 30 * 24 * time.Hour
+
+// DeprecateModel deprecates a model, or changes the dates or replacement of a deprecated one.
+// The model keeps serving with a warning to callers until its sunset; after that the proxy
+// redirects its callers to the replacement. Setting the model active again ends the deprecation.
+func (u *modelUsecase) DeprecateModel(ctx context.Context, scope *entities.TenantScope, payload *entities.DeprecateModelPayload) (*entities.AIModel, errors.BaseError) {
+	if payload.ModelID == "" {
+		return nil, errors.BadRequest(constants.ErrInvalidModelID)
+	}
+	before, err := u.manageableModel(ctx, scope, payload.ModelID)
+	if err != nil {
+		return nil, err
+	}
+	payload.ModelID = before.ID
+
+	if payload.AnnouncedAt.IsZero() {
+		payload.AnnouncedAt = time.Now()
+	}
+	if payload.SunsetAt != nil && !payload.SunsetAt.After(payload.AnnouncedAt) {
+		return nil, errors.BadRequest(constants.ErrInvalidSunset)
+	}
+	if payload.ReplacementID != "" {
+		replacement, err := u.GetModel(ctx, scope, payload.ReplacementID)
+		if err != nil {
+			return nil, errors.BadRequest(fmt.Sprintf(constants.ErrInvalidReplacement, payload.ReplacementID, err.Error()))
+		}
+		switch {
+		case replacement.ID == before.ID:
+			return nil, errors.BadRequest(fmt.Sprintf(constants.ErrInvalidReplacement, payload.ReplacementID, "must name another model"))
+		case replacement.Status != entities.ModelStatusActive:
+			return nil, errors.BadRequest(fmt.Sprintf(constants.ErrInvalidReplacement, payload.ReplacementID, "model is not active"))
+		// Callers of a global model may belong to any tenant
+		case replacement.TenantID != "" && replacement.TenantID != before.TenantID:
+			return nil, errors.BadRequest(fmt.Sprintf(constants.ErrInvalidReplacement, payload.ReplacementID, "a global model can only be replaced by a global model"))
+		}
+		payload.ReplacementID = replacement.ID
+	}
+
+	model, err := u.repository.DeprecateModel(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, u.audit, scope, entities.AuditActionModelUpdated, model.ID, modelChanges(before, model))
+	publishModelEvent(ctx, u.events, entities.ModelEventUpdated, model)
+	return model, nil
+}
+
+// checkModelReplacements refuses to delete a model while deprecated models are replaced by it
+func (u *modelUsecase) checkModelReplacements(ctx context.Context, model *entities.AIModel) errors.BaseError {
+	replaced, _, err := u.repository.ListModels(ctx, &entities.ModelFilter{ReplacementID: model.ID})
+	if err != nil {
+		return err
+	}
+	if len(replaced) == 0 {
+		return nil
+	}
+	names := make([]string, len(replaced))
+	for i, deprecated := range replaced {
+		names[i] = deprecated.Name
+	}
+	return errors.Conflict(fmt.Sprintf(constants.ErrModelIsReplacement, strings.Join(names, ", ")))
+}
+
+// GetDeprecatedModelCallers reports which tenants, projects, users and API keys still call
+// deprecated models, by default over the last 30 days. Tenant-scoped callers only see their own.
+func (u *modelUsecase) GetDeprecatedModelCallers(ctx context.Context, scope *entities.TenantScope, filter *entities.DeprecatedModelCallerFilter) ([]*entities.DeprecatedModelCaller, errors.BaseError) {
+	if !scope.IsPlatform() {
+		filter.TenantID = scope.TenantID
+	}
+	if filter.ModelID != "" {
+		model, err := u.getModel(ctx, scope, filter.ModelID)
+		if err != nil {
+			return nil, err
+		}
+		filter.ModelID = model.ID
+	}
+	if filter.StartTime.IsZero() {
+		filter.StartTime = time.Now().Add(-defaultDeprecatedCallersWindow)
+	}
+	return u.repository.GetDeprecatedModelCallers(ctx, filter)
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/blcvn/backend/services/ai-model-service/common/errors"
 	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/blcvn/backend/services/ai-model-service/helper"
+	"github.com/shopspring/decimal"
 )
 
 // iModelRepository defines repository interface
@@ -14,14 +16,162 @@ type iModelRepository interface {
 	GetModel(ctx context.Context, id string) (*entities.AIModel, errors.BaseError)
 	GetModelByName(ctx context.Context, name string) (*entities.AIModel, errors.BaseError)
 	ListModels(ctx context.Context, filter *entities.ModelFilter) ([]*entities.AIModel, int64, errors.BaseError)
-	UpdateModel(ctx context.Context, payload *entities.UpdateModelPayload) (*entities.AIModel, errors.BaseError)
+	UpdateModel(ctx context.Context, payload *entities.UpdateModelPayload, storedKey string) (*entities.AIModel, errors.BaseError)
 	DeleteModel(ctx context.Context, id string) errors.BaseError
+	GetDeletedModel(ctx context.Context, id string) (*entities.AIModel, errors.BaseError)
+	RestoreModel(ctx context.Context, id string) (*entities.AIModel, errors.BaseError)
+	DeprecateModel(ctx context.Context, payload *entities.DeprecateModelPayload) (*entities.AIModel, errors.BaseError)
+	GetDeprecatedModelCallers(ctx context.Context, filter *entities.DeprecatedModelCallerFilter) ([]*entities.DeprecatedModelCaller, errors.BaseError)
 	LogUsage(ctx context.Context, payload *entities.LogUsagePayload) errors.BaseError
-	GetDailyUsage(ctx context.Context, modelID string, date time.Time) (int64, errors.BaseError)
-	GetMonthlyUsage(ctx context.Context, modelID string, year int, month int) (int64, errors.BaseError)
+	GetDailyUsage(ctx context.Context, modelID, tenantID string, date time.Time) (int64, errors.BaseError)
+	GetMonthlyUsage(ctx context.Context, modelID, tenantID string, year int, month int) (int64, errors.BaseError)
+	GetDailyUsageByModels(ctx context.Context, modelIDs []string, tenantID string, date time.Time) (map[string]int64, errors.BaseError)
+	GetMonthlyUsageByModels(ctx context.Context, modelIDs []string, tenantID string, year int, month int) (map[string]int64, errors.BaseError)
+	GetTenantDailyUsage(ctx context.Context, tenantID string, date time.Time) (int64, errors.BaseError)
+	GetTenantMonthlyUsage(ctx context.Context, tenantID string, year int, month int) (int64, errors.BaseError)
+	CreatePricing(ctx context.Context, payload *entities.SetModelPricingPayload) (*entities.ModelPricing, errors.BaseError)
+	ListPricing(ctx context.Context, modelID string) ([]*entities.ModelPricing, errors.BaseError)
+	GetEffectivePricing(ctx context.Context, modelID string, at time.Time) (*entities.ModelPricing, errors.BaseError)
+	GetUsageStats(ctx context.Context, filter *entities.UsageStatsFilter) ([]*entities.UsageStats, errors.BaseError)
 }
 
-// iVaultClient defines Vault client interface
-type iVaultClient interface {
-	GetCredentials(ctx context.Context, vaultPath string) (*entities.Credentials, errors.BaseError)
+// iCatalogRepository defines the model storage the model catalog is applied to
+type iCatalogRepository interface {
+	CreateModel(ctx context.Context, payload *entities.CreateModelPayload, encryptedAPIKey string) (*entities.AIModel, errors.BaseError)
+	GetModelByName(ctx context.Context, name string) (*entities.AIModel, errors.BaseError)
+	ListModelsBySource(ctx context.Context, source entities.ModelSource) ([]*entities.AIModel, errors.BaseError)
+	SaveCatalogModel(ctx context.Context, model *entities.AIModel) (*entities.AIModel, errors.BaseError)
+	CreatePricing(ctx context.Context, payload *entities.SetModelPricingPayload) (*entities.ModelPricing, errors.BaseError)
+	GetEffectivePricing(ctx context.Context, modelID string, at time.Time) (*entities.ModelPricing, errors.BaseError)
+}
+
+// iModelAliasRepository defines model alias repository interface
+type iModelAliasRepository interface {
+	GetModelAlias(ctx context.Context, name string) (*entities.ModelAlias, errors.BaseError)
+	ListModelAliases(ctx context.Context, filter *entities.ModelAliasFilter) ([]*entities.ModelAlias, errors.BaseError)
+	SetModelAlias(ctx context.Context, payload *entities.SetModelAliasPayload) (*entities.ModelAlias, *entities.ModelAliasRevision, errors.BaseError)
+	DeleteModelAlias(ctx context.Context, alias *entities.ModelAlias, actor string) errors.BaseError
+	ListModelAliasHistory(ctx context.Context, name, tenantID string) ([]*entities.ModelAliasRevision, errors.BaseError)
+}
+
+// iTenantRepository defines tenant repository interface
+type iTenantRepository interface {
+	CreateTenant(ctx context.Context, payload *entities.CreateTenantPayload) (*entities.Tenant, errors.BaseError)
+	GetTenant(ctx context.Context, id string) (*entities.Tenant, errors.BaseError)
+	ListTenants(ctx context.Context) ([]*entities.Tenant, errors.BaseError)
+	CreateProject(ctx context.Context, payload *entities.CreateProjectPayload) (*entities.Project, errors.BaseError)
+	GetProject(ctx context.Context, id string) (*entities.Project, errors.BaseError)
+	ListProjects(ctx context.Context, tenantID string) ([]*entities.Project, errors.BaseError)
+}
+
+// iAPIKeyRepository defines virtual API key repository interface
+type iAPIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, payload *entities.CreateAPIKeyPayload, prefix, keyHash string) (*entities.APIKey, errors.BaseError)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*entities.APIKey, errors.BaseError)
+	ListAPIKeys(ctx context.Context, filter *entities.APIKeyFilter) ([]*entities.APIKey, errors.BaseError)
+	RevokeAPIKey(ctx context.Context, id, tenantID string) errors.BaseError
+	TouchAPIKey(ctx context.Context, id string) errors.BaseError
+}
+
+// iBudgetRepository defines budget repository interface
+type iBudgetRepository interface {
+	CreateBudget(ctx context.Context, payload *entities.CreateBudgetPayload) (*entities.Budget, errors.BaseError)
+	ListBudgets(ctx context.Context, tenantID string) ([]*entities.Budget, errors.BaseError)
+	DeleteBudget(ctx context.Context, id, tenantID string) errors.BaseError
+	FindApplicableBudgets(ctx context.Context, query *entities.BudgetQuery) ([]*entities.Budget, errors.BaseError)
+	GetBudgetSpend(ctx context.Context, budget *entities.Budget, start, end time.Time) (decimal.Decimal, errors.BaseError)
+	RecordBudgetAlert(ctx context.Context, budgetID string, periodStart time.Time, threshold int32, spent decimal.Decimal) (bool, errors.BaseError)
+}
+
+// iBudgetNotifier defines budget alert delivery interface
+type iBudgetNotifier interface {
+	NotifyBudgetAlert(ctx context.Context, webhookURL string, alert *entities.BudgetAlert) errors.BaseError
+}
+
+// iBudgetEvaluator defines the hook run after usage is logged
+type iBudgetEvaluator interface {
+	QueueUsage(payload *entities.LogUsagePayload)
+}
+
+// iUsageRetentionRepository defines usage log partitioning and retention interface
+type iUsageRetentionRepository interface {
+	IsUsagePartitioned(ctx context.Context) (bool, errors.BaseError)
+	EnsureUsagePartitions(ctx context.Context, from time.Time, monthsAhead int) errors.BaseError
+	DropUsagePartitionsBefore(ctx context.Context, cutoff time.Time) (int64, errors.BaseError)
+	DeleteUsageBefore(ctx context.Context, cutoff time.Time) (int64, errors.BaseError)
+}
+
+// iModelPurgeRepository defines the purge of deleted models
+type iModelPurgeRepository interface {
+	ListModelsDeletedBefore(ctx context.Context, cutoff time.Time) ([]*entities.AIModel, errors.BaseError)
+	PurgeModel(ctx context.Context, id string) (int64, errors.BaseError)
+}
+
+// iProviderKeyRepository defines provider key pool repository interface
+type iProviderKeyRepository interface {
+	CreateProviderKey(ctx context.Context, payload *entities.AddProviderKeyPayload, encryptedKey, keyHint string) (*entities.ProviderKey, errors.BaseError)
+	GetProviderKey(ctx context.Context, modelID, id string) (*entities.ProviderKey, errors.BaseError)
+	ListProviderKeys(ctx context.Context, modelID string) ([]*entities.ProviderKey, errors.BaseError)
+	UpdateProviderKey(ctx context.Context, payload *entities.UpdateProviderKeyPayload) (*entities.ProviderKey, errors.BaseError)
+	RevokeProviderKey(ctx context.Context, modelID, id string) errors.BaseError
+	QuarantineProviderKey(ctx context.Context, key *entities.ProviderKey) errors.BaseError
+}
+
+// iKeySelector defines how GetCredentials picks a key from a model's pool
+type iKeySelector interface {
+	SelectKey(ctx context.Context, modelID string) (*entities.ProviderKey, errors.BaseError)
+}
+
+// iEncryptedSecretRepository defines access to every stored provider key column
+type iEncryptedSecretRepository interface {
+	ListEncryptedSecrets(ctx context.Context, backend entities.SecretBackend) ([]*entities.EncryptedSecret, errors.BaseError)
+	ListModelSecrets(ctx context.Context, modelID string) ([]*entities.EncryptedSecret, errors.BaseError)
+	ListModelIDsBySecretBackend(ctx context.Context, backend entities.SecretBackend) ([]string, errors.BaseError)
+	ReplaceCiphertext(ctx context.Context, secret *entities.EncryptedSecret, ciphertext string) (bool, errors.BaseError)
+	SwitchSecretBackend(ctx context.Context, modelID string, from, to entities.SecretBackend, replacements []*entities.SecretReplacement) errors.BaseError
+}
+
+// iCredentialSelector picks the model and pool key a credential lease is issued for
+type iCredentialSelector interface {
+	SelectCredential(ctx context.Context, scope *entities.TenantScope, modelID string) (*entities.AIModel, *entities.ProviderKey, errors.BaseError)
+}
+
+// iLeaseSigner defines credential lease signing interface
+type iLeaseSigner interface {
+	Sign(lease *entities.CredentialLease) (string, error)
+	Verify(token string, now time.Time) (*entities.CredentialLease, error)
+}
+
+// iSecretStores resolves the store that holds a model's provider keys
+type iSecretStores interface {
+	DefaultBackend() entities.SecretBackend
+	Store(backend entities.SecretBackend) (helper.SecretStore, errors.BaseError)
+}
+
+// iAuditRepository defines the append-only audit log storage
+type iAuditRepository interface {
+	AppendAuditEvent(ctx context.Context, event *entities.AuditEvent) errors.BaseError
+	QueueAuditEvent(ctx context.Context, event *entities.AuditEvent) errors.BaseError
+	ChainQueuedAuditEvents(ctx context.Context, limit int) (int, errors.BaseError)
+	ListAuditEvents(ctx context.Context, filter *entities.AuditEventFilter) ([]*entities.AuditEvent, int64, errors.BaseError)
+	ListAuditChain(ctx context.Context, afterSequence int64, limit int) ([]*entities.AuditEvent, errors.BaseError)
+}
+
+// iAuditRecorder records audit events for the actor of a request
+type iAuditRecorder interface {
+	RecordAuditEvent(ctx context.Context, scope *entities.TenantScope, action entities.AuditAction, resourceType, resourceID string, changes []entities.AuditChange) errors.BaseError
+	// QueueAuditEvent durably records an event that is appended to the chain in the background,
+	// for frequent events such as credential reads that must not serialize on the chain lock
+	QueueAuditEvent(ctx context.Context, scope *entities.TenantScope, action entities.AuditAction, resourceType, resourceID string, changes []entities.AuditChange) errors.BaseError
+}
+
+// iModelEventPublisher announces model changes to caches in other services
+type iModelEventPublisher interface {
+	PublishModelEvent(ctx context.Context, event *entities.ModelEvent) errors.BaseError
+}
+
+// iModelEventBus publishes model events and delivers them to watchers
+type iModelEventBus interface {
+	iModelEventPublisher
+	SubscribeModelEvents() (<-chan *entities.ModelEvent, func(), errors.BaseError)
 }
@@ -0,0 +1,226 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/google/uuid"
+)
+
+// SetModelAlias creates an alias or re-points it to another model, replacing its weighted routes.
+// The change is atomic and recorded in the alias history; with PreviousModelID set it only
+// applies if the alias still points to that model. Aliases always point to concrete models:
+// naming another alias as the target pins the model that alias resolves to now.
+func (u *modelUsecase) SetModelAlias(ctx context.Context, scope *entities.TenantScope, payload *entities.SetModelAliasPayload) (*entities.ModelAlias, errors.BaseError) {
+	if payload.Name == "" {
+		return nil, errors.BadRequest(constants.ErrInvalidModelAliasName)
+	}
+	if _, err := uuid.Parse(payload.Name); err == nil {
+		return nil, errors.BadRequest(constants.ErrInvalidModelAliasName)
+	}
+	if !scope.IsPlatform() {
+		payload.TenantID = scope.TenantID
+	}
+
+	existing, err := u.aliasRepository.GetModelAlias(ctx, payload.Name)
+	switch {
+	case err == nil:
+		if !scope.CanAccessTenant(existing.TenantID) {
+			return nil, errors.Conflict(constants.ErrModelAliasNameTaken)
+		}
+		if !scope.CanManageTenant(existing.TenantID) {
+			return nil, errors.Forbidden(constants.ErrTenantAccessDenied)
+		}
+		payload.TenantID = existing.TenantID
+	case err.GetCode() != errors.NOT_FOUND:
+		return nil, err
+	}
+
+	if _, err := u.repository.GetModelByName(ctx, payload.Name); err == nil {
+		return nil, errors.Conflict(constants.ErrModelAliasNameTaken)
+	} else if err.GetCode() != errors.NOT_FOUND {
+		return nil, err
+	}
+
+	target, err := u.GetModel(ctx, scope, payload.ModelID)
+	if err != nil {
+		return nil, err
+	}
+	// A global alias pointing to a tenant's model would expose it to every tenant
+	if target.TenantID != "" && target.TenantID != payload.TenantID {
+		return nil, errors.BadRequest(constants.ErrModelAliasTargetInvalid)
+	}
+	if payload.PreviousModelID != "" {
+		previous, err := u.getModel(ctx, scope, payload.PreviousModelID)
+		if err != nil {
+			return nil, errors.Conflict(fmt.Sprintf(constants.ErrModelAliasChanged, payload.PreviousModelID))
+		}
+		payload.PreviousModelID = previous.ID
+	}
+	payload.ModelID = target.ID
+	if err := u.checkModelAliasRoutes(ctx, scope, payload); err != nil {
+		return nil, err
+	}
+	payload.Actor = actorIdentity(scope)
+
+	alias, revision, err := u.aliasRepository.SetModelAlias(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if revision != nil {
+		var changes []entities.AuditChange
+		if existing == nil {
+			changes = append(changes, entities.AuditChange{Field: "name", NewValue: alias.Name})
+		}
+		if revision.PreviousModelID != revision.ModelID {
+			changes = append(changes, entities.AuditChange{Field: "model_id", OldValue: revision.PreviousModelID, NewValue: revision.ModelID})
+		}
+		var previousRoutes []entities.ModelAliasRoute
+		if existing != nil {
+			previousRoutes = existing.Routes
+		}
+		if before, after := formatModelAliasRoutes(previousRoutes), formatModelAliasRoutes(alias.Routes); before != after {
+			changes = append(changes, entities.AuditChange{Field: "routes", OldValue: before, NewValue: after})
+		}
+		recordResourceAudit(ctx, u.audit, scope, entities.AuditActionModelAliasSet, entities.AuditResourceModelAlias, alias.ID, changes)
+		publishModelAliasEvent(ctx, u.events, alias)
+	}
+	return alias, nil
+}
+
+// GetModelAlias retrieves an alias the scope can see
+func (u *modelUsecase) GetModelAlias(ctx context.Context, scope *entities.TenantScope, name string) (*entities.ModelAlias, errors.BaseError) {
+	alias, err := u.aliasRepository.GetModelAlias(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.CanAccessTenant(alias.TenantID) {
+		return nil, errors.NotFound(constants.ErrModelAliasNotFound)
+	}
+	return alias, nil
+}
+
+// ListModelAliases lists global aliases plus the caller's tenant aliases, optionally only those
+// pointing or routing traffic to a model
+func (u *modelUsecase) ListModelAliases(ctx context.Context, scope *entities.TenantScope, filter *entities.ModelAliasFilter) ([]*entities.ModelAlias, errors.BaseError) {
+	if !scope.IsPlatform() {
+		filter.TenantID = scope.TenantID
+	}
+	if filter.ModelID != "" {
+		model, err := u.getModel(ctx, scope, filter.ModelID)
+		if err != nil {
+			return nil, err
+		}
+		filter.ModelID = model.ID
+	}
+	return u.aliasRepository.ListModelAliases(ctx, filter)
+}
+
+// DeleteModelAlias removes an alias. Its history is kept.
+func (u *modelUsecase) DeleteModelAlias(ctx context.Context, scope *entities.TenantScope, name string) errors.BaseError {
+	alias, err := u.GetModelAlias(ctx, scope, name)
+	if err != nil {
+		return err
+	}
+	if !scope.CanManageTenant(alias.TenantID) {
+		return errors.Forbidden(constants.ErrTenantAccessDenied)
+	}
+	if err := u.aliasRepository.DeleteModelAlias(ctx, alias, actorIdentity(scope)); err != nil {
+		return err
+	}
+
+	changes := []entities.AuditChange{
+		{Field: "name", OldValue: alias.Name},
+		{Field: "model_id", OldValue: alias.ModelID},
+	}
+	if len(alias.Routes) > 0 {
+		changes = append(changes, entities.AuditChange{Field: "routes", OldValue: formatModelAliasRoutes(alias.Routes)})
+	}
+	recordResourceAudit(ctx, u.audit, scope, entities.AuditActionModelAliasDeleted, entities.AuditResourceModelAlias, alias.ID, changes)
+	publishModelAliasEvent(ctx, u.events, alias)
+	return nil
+}
+
+// ListModelAliasHistory lists every change of an alias, newest first, including changes made
+// before it was deleted and re-created
+func (u *modelUsecase) ListModelAliasHistory(ctx context.Context, scope *entities.TenantScope, name string) ([]*entities.ModelAliasRevision, errors.BaseError) {
+	if name == "" {
+		return nil, errors.BadRequest(constants.ErrInvalidModelAliasName)
+	}
+	tenantID := ""
+	if !scope.IsPlatform() {
+		tenantID = scope.TenantID
+	}
+	return u.aliasRepository.ListModelAliasHistory(ctx, name, tenantID)
+}
+
+// checkModelAliases refuses to delete a model while aliases point or route traffic to it
+func (u *modelUsecase) checkModelAliases(ctx context.Context, model *entities.AIModel) errors.BaseError {
+	aliases, err := u.aliasRepository.ListModelAliases(ctx, &entities.ModelAliasFilter{ModelID: model.ID})
+	if err != nil {
+		return err
+	}
+	if len(aliases) == 0 {
+		return nil
+	}
+	names := make([]string, len(aliases))
+	for i, alias := range aliases {
+		names[i] = alias.Name
+	}
+	return errors.Conflict(fmt.Sprintf(constants.ErrModelHasAliases, strings.Join(names, ", ")))
+}
+
+// checkModelAliasRoutes resolves the models of an alias's routes and checks them: each route takes
+// 1 to 100 percent of the traffic to a model the alias could point to and does not already use,
+// and together the routes take at most 100 percent
+func (u *modelUsecase) checkModelAliasRoutes(ctx context.Context, scope *entities.TenantScope, payload *entities.SetModelAliasPayload) errors.BaseError {
+	used := map[string]bool{payload.ModelID: true}
+	var total int32
+	for i, route := range payload.Routes {
+		if route.Weight < 1 || route.Weight > 100 {
+			return errors.BadRequest(fmt.Sprintf(constants.ErrInvalidModelAliasRoute, route.ModelID, "weight must be between 1 and 100"))
+		}
+		total += route.Weight
+
+		model, err := u.GetModel(ctx, scope, route.ModelID)
+		if err != nil {
+			return errors.BadRequest(fmt.Sprintf(constants.ErrInvalidModelAliasRoute, route.ModelID, err.Error()))
+		}
+		if model.TenantID != "" && model.TenantID != payload.TenantID {
+			return errors.BadRequest(constants.ErrModelAliasTargetInvalid)
+		}
+		if used[model.ID] {
+			return errors.BadRequest(fmt.Sprintf(constants.ErrInvalidModelAliasRoute, route.ModelID, "model is already used by the alias"))
+		}
+		used[model.ID] = true
+		payload.Routes[i].ModelID = model.ID
+	}
+	if total > 100 {
+		return errors.BadRequest(constants.ErrModelAliasRouteWeights)
+	}
+	return nil
+}
+
+// formatModelAliasRoutes renders routes for the audit log, e.g. "<model id>:5,<model id>:20"
+func formatModelAliasRoutes(routes []entities.ModelAliasRoute) string {
+	parts := make([]string, len(routes))
+	for i, route := range routes {
+		parts[i] = fmt.Sprintf("%s:%d", route.ModelID, route.Weight)
+	}
+	return strings.Join(parts, ",")
+}
+
+// checkNameNotAlias refuses a model name that is already an alias
+func (u *modelUsecase) checkNameNotAlias(ctx context.Context, name string) errors.BaseError {
+	if _, err := u.aliasRepository.GetModelAlias(ctx, name); err == nil {
+		return errors.Conflict(constants.ErrModelNameTakenByAlias)
+	} else if err.GetCode() != errors.NOT_FOUND {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+)
+
+// WatchModels streams changes to the models the scope can see until ctx ends. send is first
+// called with nil once the subscription is in place, so the watcher knows every later change
+// reaches it. An interrupted stream returns an error; the watcher must then drop what it cached.
+func (u *modelUsecase) WatchModels(ctx context.Context, scope *entities.TenantScope, send func(*entities.ModelEvent) error) errors.BaseError {
+	events, unsubscribe, err := u.events.SubscribeModelEvents()
+	if err != nil {
+		return err
+	}
+	defer unsubscribe()
+
+	if err := send(nil); err != nil {
+		return nil
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return errors.ServiceUnavailable(constants.ErrModelEventsInterrupted)
+			}
+			if !scope.CanAccessTenant(event.TenantID) {
+				continue
+			}
+			if err := send(event); err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+// publishModelEvent announces a committed model change. Publishing is best effort: watchers
+// that miss an event still expire their cached copy.
+func publishModelEvent(ctx context.Context, publisher iModelEventPublisher, eventType entities.ModelEventType, model *entities.AIModel) {
+	event := &entities.ModelEvent{
+		Type:       eventType,
+		ModelID:    model.ID,
+		ModelName:  model.Name,
+		TenantID:   model.TenantID,
+		OccurredAt: time.Now().UTC(),
+	}
+	if err := publisher.PublishModelEvent(ctx, event); err != nil {
+		log.Printf("Failed to publish %s event for model %s: %v", eventType, model.ID, err)
+	}
+}
+
+// publishModelAliasEvent announces that an alias changed, so callers that cached the model
+// under the alias name look it up again
+func publishModelAliasEvent(ctx context.Context, publisher iModelEventPublisher, alias *entities.ModelAlias) {
+	event := &entities.ModelEvent{
+		Type:       entities.ModelEventAliasChanged,
+		ModelID:    alias.ModelID,
+		ModelName:  alias.Name,
+		TenantID:   alias.TenantID,
+		OccurredAt: time.Now().UTC(),
+	}
+	if err := publisher.PublishModelEvent(ctx, event); err != nil {
+		log.Printf("Failed to publish %s event for alias %s: %v", event.Type, alias.Name, err)
+	}
+}
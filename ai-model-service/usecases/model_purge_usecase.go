@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+)
+
+// modelPurgeActor is the actor recorded in the audit log for purged models
+const modelPurgeActor = "model-purge"
+
+type modelPurgeUsecase struct {
+	repository iModelPurgeRepository
+	audit      iAuditRecorder
+	retention  time.Duration
+}
+
+// RunPurge purges the models deleted longer than the retention window ago, archiving their usage
+// logs first. A model that fails to purge, e.g. because a restore raced it, is retried next run.
+func (u *modelPurgeUsecase) RunPurge(ctx context.Context) errors.BaseError {
+	if u.retention <= 0 {
+		return nil
+	}
+
+	models, err := u.repository.ListModelsDeletedBefore(ctx, time.Now().Add(-u.retention))
+	if err != nil {
+		return err
+	}
+
+	scope := &entities.TenantScope{Platform: true, Actor: &entities.Actor{Identity: modelPurgeActor}}
+	for _, model := range models {
+		if ctx.Err() != nil {
+			return errors.Internal(ctx.Err())
+		}
+		archived, err := u.repository.PurgeModel(ctx, model.ID)
+		if err != nil {
+			log.Printf("Warning: failed to purge deleted model %s (%s): %v", model.Name, model.ID, err)
+			continue
+		}
+		log.Printf("Purged model %s (%s), archived %d usage logs", model.Name, model.ID, archived)
+		recordAudit(ctx, u.audit, scope, entities.AuditActionModelPurged, model.ID, []entities.AuditChange{
+			{Field: "archived_usage_logs", NewValue: strconv.FormatInt(archived, 10)},
+		})
+	}
+	return nil
+}
+
+// Start runs the purge immediately and then on every interval until ctx is cancelled
+func (u *modelPurgeUsecase) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := u.RunPurge(ctx); err != nil {
+			log.Printf("Warning: model purge failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+)
+
+// Quarantine policy for keys rejected upstream. Each consecutive failure doubles the
+// quarantine up to maxKeyQuarantine; the streak resets after keyFailureResetWindow without failures.
+const (
+	rateLimitKeyQuarantine = 30 * time.Second
+	authKeyQuarantine      = 5 * time.Minute
+	maxKeyQuarantine       = time.Hour
+	keyFailureResetWindow  = time.Hour
+	maxProviderKeyWeight   = 1000
+)
+
+type providerKeyUsecase struct {
+	repository      iProviderKeyRepository
+	modelRepository iModelRepository
+	secretStores    iSecretStores
+	events          iModelEventPublisher
+}
+
+// AddProviderKey adds a provider API key to a model's pool. New keys are active immediately,
+// so a key can be rotated by adding its replacement and then revoking the old one.
+func (u *providerKeyUsecase) AddProviderKey(ctx context.Context, scope *entities.TenantScope, payload *entities.AddProviderKeyPayload) (*entities.ProviderKey, errors.BaseError) {
+	if payload.APIKey == "" {
+		return nil, errors.BadRequest(constants.ErrProviderKeyRequired)
+	}
+	if payload.Weight == 0 {
+		payload.Weight = 1
+	}
+	if payload.Weight < 0 || payload.Weight > maxProviderKeyWeight {
+		return nil, errors.BadRequest(constants.ErrInvalidProviderKeyWeight)
+	}
+
+	model, err := u.manageableModel(ctx, scope, payload.ModelID)
+	if err != nil {
+		return nil, err
+	}
+	payload.ModelID = model.ID
+
+	store, err := u.secretStores.Store(model.SecretBackend)
+	if err != nil {
+		return nil, err
+	}
+	storedKey, err := store.Put(ctx, model.ID, payload.APIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := u.repository.CreateProviderKey(ctx, payload, storedKey, entities.ProviderKeyHint(payload.APIKey))
+	if err != nil {
+		if deleteErr := store.Delete(ctx, storedKey); deleteErr != nil {
+			log.Printf("Failed to remove unsaved provider key of model %s: %v", model.ID, deleteErr)
+		}
+		return nil, err
+	}
+	return key, nil
+}
+
+// ListProviderKeys lists the keys in a model's pool
+func (u *providerKeyUsecase) ListProviderKeys(ctx context.Context, scope *entities.TenantScope, modelID string) ([]*entities.ProviderKey, errors.BaseError) {
+	model, err := u.manageableModel(ctx, scope, modelID)
+	if err != nil {
+		return nil, err
+	}
+	return u.repository.ListProviderKeys(ctx, model.ID)
+}
+
+// UpdateProviderKey reweights a key or moves it between active and disabled
+func (u *providerKeyUsecase) UpdateProviderKey(ctx context.Context, scope *entities.TenantScope, payload *entities.UpdateProviderKeyPayload) (*entities.ProviderKey, errors.BaseError) {
+	if payload.Weight < 0 || payload.Weight > maxProviderKeyWeight {
+		return nil, errors.BadRequest(constants.ErrInvalidProviderKeyWeight)
+	}
+	if payload.Status != "" && payload.Status != entities.ProviderKeyStatusActive && payload.Status != entities.ProviderKeyStatusDisabled {
+		return nil, errors.BadRequest(constants.ErrInvalidProviderKeyStatus)
+	}
+
+	model, err := u.manageableModel(ctx, scope, payload.ModelID)
+	if err != nil {
+		return nil, err
+	}
+	payload.ModelID = model.ID
+
+	key, err := u.repository.GetProviderKey(ctx, model.ID, payload.ID)
+	if err != nil {
+		return nil, err
+	}
+	if key.Status == entities.ProviderKeyStatusRevoked {
+		return nil, errors.BadRequest(constants.ErrProviderKeyRevoked)
+	}
+
+	updated, err := u.repository.UpdateProviderKey(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	publishModelEvent(ctx, u.events, entities.ModelEventCredentialsChanged, model)
+	return updated, nil
+}
+
+// RevokeProviderKey permanently removes a key from rotation and from its secret backend
+func (u *providerKeyUsecase) RevokeProviderKey(ctx context.Context, scope *entities.TenantScope, modelID, id string) errors.BaseError {
+	model, err := u.manageableModel(ctx, scope, modelID)
+	if err != nil {
+		return err
+	}
+	key, err := u.repository.GetProviderKey(ctx, model.ID, id)
+	if err != nil {
+		return err
+	}
+	if err := u.repository.RevokeProviderKey(ctx, model.ID, id); err != nil {
+		return err
+	}
+	publishModelEvent(ctx, u.events, entities.ModelEventCredentialsChanged, model)
+
+	// The model's initial key is also kept on the model itself, so it stays in the backend
+	if key.EncryptedKey == "" || key.EncryptedKey == model.EncryptedAPIKey {
+		return nil
+	}
+	store, err := u.secretStores.Store(model.SecretBackend)
+	if err == nil {
+		err = store.Delete(ctx, key.EncryptedKey)
+	}
+	if err != nil {
+		log.Printf("Failed to remove revoked provider key %s from secret backend: %v", key.ID, err)
+	}
+	return nil
+}
+
+// ReportProviderKeyFailure quarantines a key the provider rejected with 401/403 or 429
+func (u *providerKeyUsecase) ReportProviderKeyFailure(ctx context.Context, scope *entities.TenantScope, failure *entities.ProviderKeyFailure) errors.BaseError {
+	model, err := u.modelRepository.GetModel(ctx, failure.ModelID)
+	if err != nil {
+		return err
+	}
+	if !scope.CanAccessTenant(model.TenantID) {
+		return errors.NotFound(constants.ErrModelNotFound)
+	}
+
+	key, err := u.repository.GetProviderKey(ctx, model.ID, failure.KeyID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if key.LastFailureAt == nil || now.Sub(*key.LastFailureAt) > keyFailureResetWindow {
+		key.FailureCount = 0
+	}
+	key.FailureCount++
+	key.LastFailureAt = &now
+
+	var quarantine time.Duration
+	switch failure.StatusCode {
+	case http.StatusTooManyRequests:
+		quarantine = backoffQuarantine(rateLimitKeyQuarantine, key.FailureCount)
+		if failure.RetryAfter > quarantine {
+			quarantine = failure.RetryAfter
+		}
+		key.LastRateLimitedAt = &now
+	case http.StatusUnauthorized, http.StatusForbidden:
+		quarantine = backoffQuarantine(authKeyQuarantine, key.FailureCount)
+	default:
+		return errors.BadRequest(fmt.Sprintf("unsupported provider key failure status: %d", failure.StatusCode))
+	}
+
+	until := now.Add(quarantine)
+	key.QuarantinedUntil = &until
+	return u.repository.QuarantineProviderKey(ctx, key)
+}
+
+// SelectKey picks a key from the model's pool for a request. Healthy keys are chosen at
+// random in proportion to their weight. When every active key is quarantined, the one
+// released soonest (then the one rate limited longest ago) is used rather than failing.
+// A model without a key pool returns nil, meaning its legacy single key applies.
+func (u *providerKeyUsecase) SelectKey(ctx context.Context, modelID string) (*entities.ProviderKey, errors.BaseError) {
+	keys, err := u.repository.ListProviderKeys(ctx, modelID)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	var available, quarantined []*entities.ProviderKey
+	totalWeight := 0
+	for _, key := range keys {
+		switch {
+		case key.IsAvailable(now):
+			available = append(available, key)
+			totalWeight += int(max(key.Weight, 1))
+		case key.Status == entities.ProviderKeyStatusActive:
+			quarantined = append(quarantined, key)
+		}
+	}
+
+	if len(available) > 0 {
+		pick := rand.Intn(totalWeight)
+		for _, key := range available {
+			pick -= int(max(key.Weight, 1))
+			if pick < 0 {
+				return key, nil
+			}
+		}
+	}
+
+	var best *entities.ProviderKey
+	for _, key := range quarantined {
+		if best == nil || releasedBefore(key, best) {
+			best = key
+		}
+	}
+	if best == nil {
+		return nil, errors.BadRequest(constants.ErrNoActiveProviderKeys)
+	}
+	return best, nil
+}
+
+// manageableModel loads a model the scope is allowed to administer
+func (u *providerKeyUsecase) manageableModel(ctx context.Context, scope *entities.TenantScope, modelID string) (*entities.AIModel, errors.BaseError) {
+	model, err := u.modelRepository.GetModel(ctx, modelID)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.CanAccessTenant(model.TenantID) {
+		return nil, errors.NotFound(constants.ErrModelNotFound)
+	}
+	if !scope.CanManageTenant(model.TenantID) {
+		return nil, errors.Forbidden(constants.ErrTenantAccessDenied)
+	}
+	return model, nil
+}
+
+// backoffQuarantine doubles base for each consecutive failure, capped at maxKeyQuarantine
+func backoffQuarantine(base time.Duration, failures int32) time.Duration {
+	quarantine := base
+	for i := int32(1); i < failures && quarantine < maxKeyQuarantine; i++ {
+		quarantine *= 2
+	}
+	return min(quarantine, maxKeyQuarantine)
+}
+
+// releasedBefore orders quarantined keys by release time, then by least recent rate limiting
+func releasedBefore(a, b *entities.ProviderKey) bool {
+	if !a.QuarantinedUntil.Equal(*b.QuarantinedUntil) {
+		return a.QuarantinedUntil.Before(*b.QuarantinedUntil)
+	}
+	if a.LastRateLimitedAt == nil || b.LastRateLimitedAt == nil {
+		return a.LastRateLimitedAt == nil && b.LastRateLimitedAt != nil
+	}
+	return a.LastRateLimitedAt.Before(*b.LastRateLimitedAt)
+}
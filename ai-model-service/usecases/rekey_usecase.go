@@ -0,0 +1,70 @@
+package usecases
+
+import (
+	"context"
+	"log"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/blcvn/backend/services/ai-model-service/helper"
+)
+
+type rekeyUsecase struct {
+	repository iEncryptedSecretRepository
+	crypto     helper.CryptoHelpers
+}
+
+// Rekey re-encrypts every locally stored provider key that is not wrapped with the current master key.
+// Rows that fail to decrypt are counted and skipped so one bad record does not block rotation;
+// once Failed is zero the retired master keys can be removed from configuration.
+func (u *rekeyUsecase) Rekey(ctx context.Context, dryRun bool) (*entities.RekeyResult, errors.BaseError) {
+	secrets, err := u.repository.ListEncryptedSecrets(ctx, entities.SecretBackendLocal)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entities.RekeyResult{Scanned: len(secrets)}
+	current := u.crypto.CurrentKeyVersion()
+	for _, secret := range secrets {
+		if ctx.Err() != nil {
+			return result, errors.Internal(ctx.Err())
+		}
+		if u.crypto.KeyVersion(secret.Ciphertext) == current {
+			result.Current++
+			continue
+		}
+
+		plaintext, cryptoErr := u.crypto.Decrypt(secret.Ciphertext)
+		if cryptoErr != nil {
+			log.Printf("Rekey: failed to decrypt %s %s: %v", secret.Kind, secret.ID, cryptoErr)
+			result.Failed++
+			continue
+		}
+		if dryRun {
+			result.Rekeyed++
+			continue
+		}
+
+		ciphertext, cryptoErr := u.crypto.Encrypt(plaintext)
+		if cryptoErr != nil {
+			log.Printf("Rekey: failed to encrypt %s %s: %v", secret.Kind, secret.ID, cryptoErr)
+			result.Failed++
+			continue
+		}
+
+		replaced, err := u.repository.ReplaceCiphertext(ctx, secret, ciphertext)
+		if err != nil {
+			log.Printf("Rekey: failed to update %s %s: %v", secret.Kind, secret.ID, err)
+			result.Failed++
+			continue
+		}
+		if !replaced {
+			// The secret was rewritten since it was listed, so it is already on the current key
+			result.Current++
+			continue
+		}
+		result.Rekeyed++
+	}
+
+	return result, nil
+}
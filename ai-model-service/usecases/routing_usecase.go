@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+)
+
+// ListRoutingCandidates lists the active models of a tier the scope can use, each with its
+// effective price and the quota left for the scope, so the proxy can pick one for a tier request.
+// Models without a pricing version are priced at their flat per-1k-token rate.
+func (u *modelUsecase) ListRoutingCandidates(ctx context.Context, scope *entities.TenantScope, tier entities.ModelTier) ([]*entities.RoutingCandidate, errors.BaseError) {
+	if tier == entities.ModelTierNone || !tier.Valid() {
+		return nil, errors.BadRequest(fmt.Sprintf(constants.ErrInvalidModelTier, tier))
+	}
+
+	filter := &entities.ModelFilter{Status: entities.ModelStatusActive, Tier: tier}
+	if !scope.IsPlatform() {
+		filter.TenantID = scope.TenantID
+	}
+	models, _, err := u.repository.ListModels(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantID := ""
+	if !scope.IsPlatform() {
+		tenantID = scope.TenantID
+	}
+
+	// Load the usage of every candidate in one query per window instead of one per model
+	now := time.Now()
+	modelIDs := make([]string, len(models))
+	for i, model := range models {
+		modelIDs[i] = model.ID
+	}
+	dailyUsed, err := u.repository.GetDailyUsageByModels(ctx, modelIDs, tenantID, now)
+	if err != nil {
+		return nil, err
+	}
+	monthlyUsed, err := u.repository.GetMonthlyUsageByModels(ctx, modelIDs, tenantID, now.Year(), int(now.Month()))
+	if err != nil {
+		return nil, err
+	}
+	tenantQuota, err := u.tenantQuota(ctx, tenantID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*entities.RoutingCandidate, 0, len(models))
+	for _, model := range models {
+		quota := quotaStatus(model, dailyUsed[model.ID], monthlyUsed[model.ID], tenantQuota, now)
+		candidate := &entities.RoutingCandidate{
+			Model:             model,
+			InputPer1kTokens:  model.CostPer1kTokens,
+			OutputPer1kTokens: model.CostPer1kTokens,
+			QuotaRemaining:    quota.Remaining(),
+		}
+
+		pricing, err := u.repository.GetEffectivePricing(ctx, model.ID, now)
+		if err == nil {
+			candidate.InputPer1kTokens = pricing.InputPer1kTokens
+			candidate.OutputPer1kTokens = pricing.OutputPer1kTokens
+		} else if err.GetCode() != errors.NOT_FOUND {
+			return nil, err
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
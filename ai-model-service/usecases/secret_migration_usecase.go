@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/blcvn/backend/services/ai-model-service/helper"
+)
+
+type secretMigrationUsecase struct {
+	repository      iEncryptedSecretRepository
+	modelRepository iModelRepository
+	secretStores    iSecretStores
+}
+
+// MigrateSecrets moves provider keys to another secret backend. With modelID set only that
+// model is moved; otherwise every model on the from backend is. A model that fails to move
+// keeps its current backend and is counted in Failed.
+func (u *secretMigrationUsecase) MigrateSecrets(ctx context.Context, from, to entities.SecretBackend, modelID string, dryRun bool) (*entities.SecretMigrationResult, errors.BaseError) {
+	toStore, err := u.secretStores.Store(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var modelIDs []string
+	if modelID != "" {
+		model, err := u.modelRepository.GetModel(ctx, modelID)
+		if err != nil {
+			return nil, err
+		}
+		if secretBackend(model) == to {
+			return nil, errors.BadRequest(fmt.Sprintf(constants.ErrSecretBackendUnchanged, to))
+		}
+		modelIDs = []string{model.ID}
+	} else {
+		if from == to {
+			return nil, errors.BadRequest(fmt.Sprintf(constants.ErrSecretBackendUnchanged, to))
+		}
+		if _, err := u.secretStores.Store(from); err != nil {
+			return nil, err
+		}
+		modelIDs, err = u.repository.ListModelIDsBySecretBackend(ctx, from)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &entities.SecretMigrationResult{}
+	for _, id := range modelIDs {
+		if ctx.Err() != nil {
+			return result, errors.Internal(ctx.Err())
+		}
+		moved, err := u.migrateModel(ctx, id, to, toStore, dryRun)
+		if err != nil {
+			log.Printf("Secret migration: failed to move model %s to %s: %v", id, to, err)
+			result.Failed++
+			continue
+		}
+		result.Models++
+		result.Secrets += moved
+	}
+
+	return result, nil
+}
+
+// migrateModel copies a model's keys into the target store, switches the model over in one
+// transaction and only then removes the old copies. Keys shared by the model and its pool
+// (the initial key) are copied once so they stay shared.
+func (u *secretMigrationUsecase) migrateModel(ctx context.Context, modelID string, to entities.SecretBackend, toStore helper.SecretStore, dryRun bool) (int, errors.BaseError) {
+	model, err := u.modelRepository.GetModel(ctx, modelID)
+	if err != nil && err.GetCode() == errors.NOT_FOUND {
+		// Deleted models are moved too, so their keys still resolve if they are restored
+		model, err = u.modelRepository.GetDeletedModel(ctx, modelID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	from := secretBackend(model)
+	fromStore, err := u.secretStores.Store(from)
+	if err != nil {
+		return 0, err
+	}
+
+	secrets, err := u.repository.ListModelSecrets(ctx, model.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	moved := make(map[string]string)
+	replacements := make([]*entities.SecretReplacement, 0, len(secrets))
+	for _, secret := range secrets {
+		stored, ok := moved[secret.Ciphertext]
+		if !ok {
+			apiKey, err := fromStore.Get(ctx, secret.Ciphertext)
+			if err != nil {
+				u.discard(ctx, toStore, moved)
+				return 0, err
+			}
+			if !dryRun {
+				stored, err = toStore.Put(ctx, model.ID, apiKey)
+				if err != nil {
+					u.discard(ctx, toStore, moved)
+					return 0, err
+				}
+			}
+			moved[secret.Ciphertext] = stored
+		}
+		replacements = append(replacements, &entities.SecretReplacement{Secret: secret, Stored: stored})
+	}
+	if dryRun {
+		return len(replacements), nil
+	}
+
+	if err := u.repository.SwitchSecretBackend(ctx, model.ID, from, to, replacements); err != nil {
+		u.discard(ctx, toStore, moved)
+		return 0, err
+	}
+
+	for old := range moved {
+		if err := fromStore.Delete(ctx, old); err != nil {
+			log.Printf("Secret migration: failed to remove old copy of a key of model %s from %s: %v", model.ID, from, err)
+		}
+	}
+	return len(replacements), nil
+}
+
+// discard removes copies written to the target store for a model that could not be switched
+func (u *secretMigrationUsecase) discard(ctx context.Context, store helper.SecretStore, moved map[string]string) {
+	for _, stored := range moved {
+		if stored == "" {
+			continue
+		}
+		if err := store.Delete(ctx, stored); err != nil {
+			log.Printf("Secret migration: failed to remove copied key: %v", err)
+		}
+	}
+}
+
+// secretBackend returns the model's backend; models created before backends existed are local
+func secretBackend(model *entities.AIModel) entities.SecretBackend {
+	if model.SecretBackend == "" {
+		return entities.SecretBackendLocal
+	}
+	return model.SecretBackend
+}
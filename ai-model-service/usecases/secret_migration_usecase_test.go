@@ -0,0 +1,156 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+	"github.com/blcvn/backend/services/ai-model-service/helper"
+	"github.com/blcvn/backend/services/ai-model-service/helper/vaulttest"
+)
+
+// fakeSecretRepository keeps a model's secrets in memory and records backend switches
+type fakeSecretRepository struct {
+	secrets  []*entities.EncryptedSecret
+	switched map[string][]*entities.SecretReplacement
+}
+
+func (r *fakeSecretRepository) ListEncryptedSecrets(ctx context.Context, backend entities.SecretBackend) ([]*entities.EncryptedSecret, errors.BaseError) {
+	return r.secrets, nil
+}
+
+func (r *fakeSecretRepository) ListModelSecrets(ctx context.Context, modelID string) ([]*entities.EncryptedSecret, errors.BaseError) {
+	var secrets []*entities.EncryptedSecret
+	for _, secret := range r.secrets {
+		if secret.ModelID == modelID {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets, nil
+}
+
+func (r *fakeSecretRepository) ListModelIDsBySecretBackend(ctx context.Context, backend entities.SecretBackend) ([]string, errors.BaseError) {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, secret := range r.secrets {
+		if !seen[secret.ModelID] {
+			seen[secret.ModelID] = true
+			ids = append(ids, secret.ModelID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *fakeSecretRepository) ReplaceCiphertext(ctx context.Context, secret *entities.EncryptedSecret, ciphertext string) (bool, errors.BaseError) {
+	secret.Ciphertext = ciphertext
+	return true, nil
+}
+
+func (r *fakeSecretRepository) SwitchSecretBackend(ctx context.Context, modelID string, from, to entities.SecretBackend, replacements []*entities.SecretReplacement) errors.BaseError {
+	r.switched[modelID] = replacements
+	for _, replacement := range replacements {
+		replacement.Secret.Ciphertext = replacement.Stored
+	}
+	return nil
+}
+
+// fakeModelRepository serves GetModel from memory; other methods are not used by the migration
+type fakeModelRepository struct {
+	iModelRepository
+	models map[string]*entities.AIModel
+}
+
+func (r *fakeModelRepository) GetModel(ctx context.Context, id string) (*entities.AIModel, errors.BaseError) {
+	if model, ok := r.models[id]; ok {
+		return model, nil
+	}
+	return nil, errors.NotFound(id)
+}
+
+func (r *fakeModelRepository) GetDeletedModel(ctx context.Context, id string) (*entities.AIModel, errors.BaseError) {
+	return nil, errors.NotFound(id)
+}
+
+func TestMigrateSecretsVaultKVToTransit(t *testing.T) {
+	ctx := context.Background()
+	server := vaulttest.NewServer()
+	defer server.Close()
+	vault, verr := helper.NewVaultClient(server.URL, vaulttest.Token)
+	if verr != nil {
+		t.Fatalf("NewVaultClient: %v", verr)
+	}
+	defer vault.Close()
+
+	kv := helper.NewVaultKVSecretStore(vault, "secret", "ai-models")
+	transit := helper.NewVaultTransitSecretStore(vault, "transit", "ai-models")
+	stores, serr := helper.NewSecretStores(entities.SecretBackendVaultKV, map[entities.SecretBackend]helper.SecretStore{
+		entities.SecretBackendVaultKV:      kv,
+		entities.SecretBackendVaultTransit: transit,
+	})
+	if serr != nil {
+		t.Fatalf("NewSecretStores: %v", serr)
+	}
+
+	initial, err := kv.Put(ctx, "model-1", "sk-initial")
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	pooled, err := kv.Put(ctx, "model-1", "sk-pooled")
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	// The initial key is shared by the model and its pool
+	repository := &fakeSecretRepository{
+		secrets: []*entities.EncryptedSecret{
+			{Kind: entities.EncryptedSecretModelKey, ID: "model-1", ModelID: "model-1", Ciphertext: initial},
+			{Kind: entities.EncryptedSecretProviderKey, ID: "key-1", ModelID: "model-1", Ciphertext: initial},
+			{Kind: entities.EncryptedSecretProviderKey, ID: "key-2", ModelID: "model-1", Ciphertext: pooled},
+		},
+		switched: make(map[string][]*entities.SecretReplacement),
+	}
+	models := &fakeModelRepository{models: map[string]*entities.AIModel{
+		"model-1": {ID: "model-1", SecretBackend: entities.SecretBackendVaultKV},
+	}}
+	usecase := NewSecretMigrationUsecase(repository, models, stores)
+
+	result, err := usecase.MigrateSecrets(ctx, entities.SecretBackendVaultKV, entities.SecretBackendVaultTransit, "", true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if result.Models != 1 || result.Secrets != 3 || result.Failed != 0 {
+		t.Fatalf("dry run result = %+v, want 1 model, 3 secrets", result)
+	}
+	if len(repository.switched) != 0 || server.KVCount() != 2 {
+		t.Fatalf("dry run switched %d models and left %d KV secrets, want 0 and 2", len(repository.switched), server.KVCount())
+	}
+
+	result, err = usecase.MigrateSecrets(ctx, entities.SecretBackendVaultKV, entities.SecretBackendVaultTransit, "", false)
+	if err != nil {
+		t.Fatalf("MigrateSecrets: %v", err)
+	}
+	if result.Models != 1 || result.Secrets != 3 || result.Failed != 0 {
+		t.Fatalf("result = %+v, want 1 model, 3 secrets", result)
+	}
+	if server.KVCount() != 0 {
+		t.Fatalf("vault still holds %d KV secrets after the migration", server.KVCount())
+	}
+
+	replacements := repository.switched["model-1"]
+	if len(replacements) != 3 {
+		t.Fatalf("switched %d secrets, want 3", len(replacements))
+	}
+	if replacements[0].Stored != replacements[1].Stored {
+		t.Fatalf("shared initial key was copied twice: %q and %q", replacements[0].Stored, replacements[1].Stored)
+	}
+	want := []string{"sk-initial", "sk-initial", "sk-pooled"}
+	for i, replacement := range replacements {
+		apiKey, err := transit.Get(ctx, replacement.Stored)
+		if err != nil {
+			t.Fatalf("transit Get of %s: %v", replacement.Secret.ID, err)
+		}
+		if apiKey != want[i] {
+			t.Fatalf("%s decrypts to %q, want %q", replacement.Secret.ID, apiKey, want[i])
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/constants"
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+	"github.com/blcvn/backend/services/ai-model-service/entities"
+)
+
+type tenantUsecase struct {
+	repository iTenantRepository
+}
+
+// CreateTenant creates a new tenant (platform-level callers only)
+func (u *tenantUsecase) CreateTenant(ctx context.Context, scope *entities.TenantScope, payload *entities.CreateTenantPayload) (*entities.Tenant, errors.BaseError) {
+	if !scope.IsPlatform() {
+		return nil, errors.Forbidden(constants.ErrTenantAccessDenied)
+	}
+	if payload.Name == "" {
+		return nil, errors.BadRequest(constants.ErrInvalidTenantName)
+	}
+
+	return u.repository.CreateTenant(ctx, payload)
+}
+
+// ListTenants lists tenants visible to the caller
+func (u *tenantUsecase) ListTenants(ctx context.Context, scope *entities.TenantScope) ([]*entities.Tenant, errors.BaseError) {
+	if scope.IsPlatform() {
+		return u.repository.ListTenants(ctx)
+	}
+
+	tenant, err := u.repository.GetTenant(ctx, scope.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	return []*entities.Tenant{tenant}, nil
+}
+
+// CreateProject creates a project within the caller's tenant
+func (u *tenantUsecase) CreateProject(ctx context.Context, scope *entities.TenantScope, payload *entities.CreateProjectPayload) (*entities.Project, errors.BaseError) {
+	if !scope.IsPlatform() {
+		if payload.TenantID != "" && payload.TenantID != scope.TenantID {
+			return nil, errors.Forbidden(constants.ErrTenantAccessDenied)
+		}
+		payload.TenantID = scope.TenantID
+	}
+	if payload.Name == "" {
+		return nil, errors.BadRequest(constants.ErrInvalidProjectName)
+	}
+	if _, err := u.repository.GetTenant(ctx, payload.TenantID); err != nil {
+		return nil, err
+	}
+
+	return u.repository.CreateProject(ctx, payload)
+}
+
+// ListProjects lists the projects of a tenant visible to the caller
+func (u *tenantUsecase) ListProjects(ctx context.Context, scope *entities.TenantScope, tenantID string) ([]*entities.Project, errors.BaseError) {
+	if !scope.IsPlatform() {
+		if tenantID != "" && tenantID != scope.TenantID {
+			return nil, errors.Forbidden(constants.ErrTenantAccessDenied)
+		}
+		tenantID = scope.TenantID
+	}
+
+	return u.repository.ListProjects(ctx, tenantID)
+}
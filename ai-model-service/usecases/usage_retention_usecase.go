@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-model-service/common/errors"
+)
+
+// usagePartitionsAhead is how many future monthly partitions are kept ready for inserts
+const usagePartitionsAhead = 2
+
+type usageRetentionUsecase struct {
+	repository      iUsageRetentionRepository
+	retentionMonths int
+}
+
+// RunMaintenance creates upcoming usage log partitions and removes raw logs older than the
+// retention period. Hourly and daily rollups are kept, so quotas and budgets are unaffected.
+func (u *usageRetentionUsecase) RunMaintenance(ctx context.Context) errors.BaseError {
+	partitioned, err := u.repository.IsUsagePartitioned(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if partitioned {
+		if err := u.repository.EnsureUsagePartitions(ctx, now, usagePartitionsAhead); err != nil {
+			return err
+		}
+	}
+
+	if u.retentionMonths <= 0 {
+		return nil
+	}
+	cutoff := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -u.retentionMonths, 0)
+
+	if partitioned {
+		dropped, err := u.repository.DropUsagePartitionsBefore(ctx, cutoff)
+		if err != nil {
+			return err
+		}
+		if dropped > 0 {
+			log.Printf("Dropped %d usage log partitions older than %s", dropped, cutoff.Format("2006-01"))
+		}
+		return nil
+	}
+
+	deleted, err := u.repository.DeleteUsageBefore(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.Printf("Deleted %d usage logs older than %s", deleted, cutoff.Format("2006-01"))
+	}
+	return nil
+}
+
+// Start runs maintenance immediately and then on every interval until ctx is cancelled
+func (u *usageRetentionUsecase) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := u.RunMaintenance(ctx); err != nil {
+			log.Printf("Warning: usage log maintenance failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -3,22 +3,42 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/blcvn/backend/services/ai-model-service/common/constants"
 	"github.com/blcvn/backend/services/ai-model-service/common/errors"
 	"github.com/blcvn/backend/services/ai-model-service/entities"
 	"github.com/blcvn/backend/services/ai-model-service/helper"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 type modelUsecase struct {
-	repository  iModelRepository
-	vaultClient iVaultClient
-	crypto      helper.CryptoHelpers
+	repository       iModelRepository
+	tenantRepository iTenantRepository
+	secretStores     iSecretStores
+	// allowRawCredentials permits GetCredentials to return plaintext keys
+	allowRawCredentials bool
+	budgetEvaluator     iBudgetEvaluator
+	keySelector         iKeySelector
+	events              iModelEventBus
+	audit               iAuditRecorder
+	aliasRepository     iModelAliasRepository
 }
 
-// CreateModel creates a new AI model
-func (u *modelUsecase) CreateModel(ctx context.Context, payload *entities.CreateModelPayload) (*entities.AIModel, errors.BaseError) {
+// maxUsageBatchSize caps the number of events accepted by a single LogUsageBatch call
+const maxUsageBatchSize = 1000
+
+// Usage events must have occurred within this window around the time they are logged: clock
+// skew between services is tolerated, and a proxy backlog can be replayed after an outage.
+const (
+	maxUsageClockSkew = 5 * time.Minute
+	maxUsageEventAge  = 90 * 24 * time.Hour
+)
+
+// CreateModel creates a new AI model. Tenant-scoped callers always create tenant-private models.
+func (u *modelUsecase) CreateModel(ctx context.Context, scope *entities.TenantScope, payload *entities.CreateModelPayload) (*entities.AIModel, errors.BaseError) {
 	// Validate payload
 	if payload.Name == "" {
 		return nil, errors.BadRequest(constants.ErrInvalidModelName)
@@ -27,70 +47,294 @@ func (u *modelUsecase) CreateModel(ctx context.Context, payload *entities.Create
 		return nil, errors.BadRequest(constants.ErrInvalidProvider)
 	}
 	if payload.APIKey == "" {
-		return nil, errors.BadRequest("api key is required")
+		return nil, errors.BadRequest(constants.ErrAPIKeyRequired)
+	}
+	if !scope.IsPlatform() {
+		payload.TenantID = scope.TenantID
+	}
+	if payload.TenantID != "" {
+		if _, err := u.tenantRepository.GetTenant(ctx, payload.TenantID); err != nil {
+			return nil, err
+		}
+	}
+	if err := u.checkNameNotAlias(ctx, payload.Name); err != nil {
+		return nil, err
+	}
+	if err := validateModelLimits(&payload.Limits); err != nil {
+		return nil, err
+	}
+	if !payload.Tier.Valid() {
+		return nil, errors.BadRequest(fmt.Sprintf(constants.ErrInvalidModelTier, payload.Tier))
+	}
+	if err := u.checkFallbackModels(ctx, scope, payload.Name, payload.FallbackModels); err != nil {
+		return nil, err
 	}
 
-	// Encrypt API Key
-	encryptedKey, cryptoErr := u.crypto.Encrypt(payload.APIKey)
-	if cryptoErr != nil {
-		return nil, errors.Internal(fmt.Errorf("failed to encrypt api key: %v", cryptoErr))
+	// Store API Key in the model's secret backend
+	if payload.SecretBackend == "" {
+		payload.SecretBackend = u.secretStores.DefaultBackend()
+	}
+	store, err := u.secretStores.Store(payload.SecretBackend)
+	if err != nil {
+		return nil, err
+	}
+	payload.ID = uuid.New().String()
+	storedKey, err := store.Put(ctx, payload.ID, payload.APIKey)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create model via repository
-	model, repoErr := u.repository.CreateModel(ctx, payload, encryptedKey)
+	model, repoErr := u.repository.CreateModel(ctx, payload, storedKey)
 	if repoErr != nil {
+		if err := store.Delete(ctx, storedKey); err != nil {
+			log.Printf("Failed to remove api key of unsaved model %s: %v", payload.ID, err)
+		}
 		return nil, repoErr
 	}
 
+	recordAudit(ctx, u.audit, scope, entities.AuditActionModelCreated, model.ID, modelChanges(nil, model))
 	return model, nil
 }
 
-// GetModel retrieves a model by ID
-func (u *modelUsecase) GetModel(ctx context.Context, id string) (*entities.AIModel, errors.BaseError) {
-	return u.repository.GetModel(ctx, id)
+// GetModel retrieves a model by ID or name, or the model an alias points to, hiding models that
+// belong to other tenants
+func (u *modelUsecase) GetModel(ctx context.Context, scope *entities.TenantScope, id string) (*entities.AIModel, errors.BaseError) {
+	model, err := u.getModel(ctx, scope, id)
+	if err == nil || err.GetCode() != errors.NOT_FOUND || u.aliasRepository == nil {
+		return model, err
+	}
+	alias, aliasErr := u.aliasRepository.GetModelAlias(ctx, id)
+	if aliasErr != nil || !scope.CanAccessTenant(alias.TenantID) {
+		return nil, err
+	}
+	model, err = u.getModel(ctx, scope, alias.ModelID)
+	if err != nil {
+		return nil, err
+	}
+	model.Alias = alias
+	return model, nil
+}
+
+// getModel retrieves a model by ID or name without resolving aliases
+func (u *modelUsecase) getModel(ctx context.Context, scope *entities.TenantScope, id string) (*entities.AIModel, errors.BaseError) {
+	model, err := u.repository.GetModel(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.CanAccessTenant(model.TenantID) {
+		return nil, errors.NotFound(constants.ErrModelNotFound)
+	}
+	return model, nil
 }
 
-// ListModels lists models with filtering
-func (u *modelUsecase) ListModels(ctx context.Context, filter *entities.ModelFilter) ([]*entities.AIModel, int64, errors.BaseError) {
+// ListModels lists global models plus the caller's tenant-private models
+func (u *modelUsecase) ListModels(ctx context.Context, scope *entities.TenantScope, filter *entities.ModelFilter) ([]*entities.AIModel, int64, errors.BaseError) {
+	if !scope.IsPlatform() {
+		filter.TenantID = scope.TenantID
+	}
 	return u.repository.ListModels(ctx, filter)
 }
 
-// UpdateModel updates an existing model
-func (u *modelUsecase) UpdateModel(ctx context.Context, payload *entities.UpdateModelPayload) (*entities.AIModel, errors.BaseError) {
+// UpdateModel writes the fields named in the payload, including zero values, and rotates the
+// API key when it is among them. A payload version other than the stored one fails with a conflict.
+func (u *modelUsecase) UpdateModel(ctx context.Context, scope *entities.TenantScope, payload *entities.UpdateModelPayload) (*entities.AIModel, errors.BaseError) {
 	// Validate payload
 	if payload.ID == "" {
 		return nil, errors.BadRequest(constants.ErrInvalidModelID)
 	}
+	before, err := u.manageableModel(ctx, scope, payload.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateUpdateModelPayload(payload); err != nil {
+		return nil, err
+	}
+	// Masked writes and key rotations overwrite what they name, so they must not skip the version check
+	if payload.Version == 0 && (payload.Masked || payload.Updates(entities.ModelFieldAPIKey)) {
+		return nil, errors.FailedPrecondition(constants.ErrModelVersionMissing)
+	}
+	if payload.Updates(entities.ModelFieldName) && payload.Name != before.Name {
+		if err := u.checkNameNotAlias(ctx, payload.Name); err != nil {
+			return nil, err
+		}
+	}
+	if payload.Updates(entities.ModelFieldFallbackModels) {
+		if err := u.checkFallbackModels(ctx, scope, before.Name, payload.FallbackModels); err != nil {
+			return nil, err
+		}
+	}
+
+	// A rotated key is stored first, and the old one only removed once the model points to it
+	var store helper.SecretStore
+	var storedKey string
+	if payload.Updates(entities.ModelFieldAPIKey) {
+		if store, err = u.secretStores.Store(before.SecretBackend); err != nil {
+			return nil, err
+		}
+		if storedKey, err = store.Put(ctx, before.ID, payload.APIKey); err != nil {
+			return nil, err
+		}
+	}
+
+	model, err := u.repository.UpdateModel(ctx, payload, storedKey)
+	if err != nil {
+		if storedKey != "" {
+			if err := store.Delete(ctx, storedKey); err != nil {
+				log.Printf("Failed to remove unsaved api key of model %s: %v", before.ID, err)
+			}
+		}
+		return nil, err
+	}
+	if storedKey != "" && before.EncryptedAPIKey != "" && before.EncryptedAPIKey != storedKey {
+		if err := store.Delete(ctx, before.EncryptedAPIKey); err != nil {
+			log.Printf("Failed to remove rotated api key of model %s: %v", before.ID, err)
+		}
+	}
+
+	recordAudit(ctx, u.audit, scope, entities.AuditActionModelUpdated, model.ID, modelChanges(before, model))
+	publishModelEvent(ctx, u.events, entities.ModelEventUpdated, model)
+	return model, nil
+}
 
-	return u.repository.UpdateModel(ctx, payload)
+// validateUpdateModelPayload checks the values of the fields the payload updates
+func validateUpdateModelPayload(payload *entities.UpdateModelPayload) errors.BaseError {
+	for _, field := range payload.Fields {
+		switch field {
+		case entities.ModelFieldName:
+			if payload.Name == "" {
+				return errors.BadRequest(constants.ErrInvalidModelName)
+			}
+		case entities.ModelFieldProvider:
+			if payload.Provider == "" {
+				return errors.BadRequest(constants.ErrInvalidProvider)
+			}
+		case entities.ModelFieldModelID:
+			if payload.ModelID == "" {
+				return errors.BadRequest(constants.ErrProviderModelID)
+			}
+		case entities.ModelFieldBaseURL:
+			if payload.BaseURL == "" {
+				return errors.BadRequest(constants.ErrInvalidBaseURL)
+			}
+		case entities.ModelFieldAPIKey:
+			if payload.APIKey == "" {
+				return errors.BadRequest(constants.ErrAPIKeyRequired)
+			}
+		case entities.ModelFieldQuotaDaily, entities.ModelFieldQuotaMonthly:
+			if payload.QuotaDaily < 0 || payload.QuotaMonthly < 0 {
+				return errors.BadRequest(constants.ErrInvalidQuota)
+			}
+		case entities.ModelFieldCostPer1kTokens:
+			if payload.CostPer1kTokens.IsNegative() {
+				return errors.BadRequest(constants.ErrInvalidCost)
+			}
+		case entities.ModelFieldStatus:
+			if !payload.Status.Valid() {
+				return errors.BadRequest(fmt.Sprintf(constants.ErrInvalidStatus, payload.Status))
+			}
+		case entities.ModelFieldLimits:
+			if err := validateModelLimits(&payload.Limits); err != nil {
+				return err
+			}
+		case entities.ModelFieldTier:
+			if !payload.Tier.Valid() {
+				return errors.BadRequest(fmt.Sprintf(constants.ErrInvalidModelTier, payload.Tier))
+			}
+		}
+	}
+	return nil
 }
 
-// DeleteModel deletes a model
-func (u *modelUsecase) DeleteModel(ctx context.Context, id string) errors.BaseError {
-	return u.repository.DeleteModel(ctx, id)
+// DeleteModel soft-deletes a model. It can be restored until it is purged.
+func (u *modelUsecase) DeleteModel(ctx context.Context, scope *entities.TenantScope, id string) errors.BaseError {
+	model, err := u.manageableModel(ctx, scope, id)
+	if err != nil {
+		return err
+	}
+	if err := u.checkModelAliases(ctx, model); err != nil {
+		return err
+	}
+	if err := u.checkModelReplacements(ctx, model); err != nil {
+		return err
+	}
+	if err := u.repository.DeleteModel(ctx, model.ID); err != nil {
+		return err
+	}
+	recordAudit(ctx, u.audit, scope, entities.AuditActionModelDeleted, model.ID, modelChanges(model, nil))
+	publishModelEvent(ctx, u.events, entities.ModelEventDeleted, model)
+	return nil
 }
 
-// GetCredentials retrieves API credentials from Vault
-func (u *modelUsecase) GetCredentials(ctx context.Context, modelID string) (*entities.Credentials, errors.BaseError) {
-	// Get model to retrieve vault path
-	model, err := u.repository.GetModel(ctx, modelID)
+// RestoreModel undeletes a deleted model that has not been purged yet
+func (u *modelUsecase) RestoreModel(ctx context.Context, scope *entities.TenantScope, id string) (*entities.AIModel, errors.BaseError) {
+	if id == "" {
+		return nil, errors.BadRequest(constants.ErrInvalidModelID)
+	}
+	deleted, err := u.repository.GetDeletedModel(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if !scope.CanAccessTenant(deleted.TenantID) {
+		return nil, errors.NotFound(constants.ErrModelNotDeleted)
+	}
+	if !scope.CanManageTenant(deleted.TenantID) {
+		return nil, errors.Forbidden(constants.ErrTenantAccessDenied)
+	}
+	if err := u.checkNameNotAlias(ctx, deleted.Name); err != nil {
+		return nil, err
+	}
 
-	// Check if model is active
-	if model.Status != entities.ModelStatusActive {
-		return nil, errors.BadRequest(fmt.Sprintf("model is not active: %s", model.Status))
+	model, err := u.repository.RestoreModel(ctx, deleted.ID)
+	if err != nil {
+		return nil, err
 	}
+	recordAudit(ctx, u.audit, scope, entities.AuditActionModelRestored, model.ID, modelChanges(nil, model))
+	publishModelEvent(ctx, u.events, entities.ModelEventUpdated, model)
+	return model, nil
+}
 
-	// Decrypt API Key
-	apiKey, errStr := u.crypto.Decrypt(model.EncryptedAPIKey)
-	if errStr != nil {
-		return nil, errors.Internal(fmt.Errorf("failed to decrypt api key: %v", errStr))
+// GetCredentials resolves API credentials from the model's secret backend. It is refused when
+// raw credentials are disabled and proxies must use credential leases instead.
+func (u *modelUsecase) GetCredentials(ctx context.Context, scope *entities.TenantScope, modelID string) (*entities.Credentials, errors.BaseError) {
+	if !u.allowRawCredentials {
+		return nil, errors.Forbidden(constants.ErrRawCredentialsDisabled)
+	}
+
+	model, key, err := u.SelectCredential(ctx, scope, modelID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fall back to the model's own key when it has no key pool
+	var keyID string
+	encryptedKey := model.EncryptedAPIKey
+	if key != nil {
+		keyID = key.ID
+		encryptedKey = key.EncryptedKey
+	}
+
+	// Resolve API Key from the model's secret backend
+	store, err := u.secretStores.Store(model.SecretBackend)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, err := store.Get(ctx, encryptedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Credential access is only granted once it is on record. Reads are frequent, so they are
+	// queued and chained in the background rather than taking the chain lock per request.
+	if u.audit != nil {
+		if err := u.audit.QueueAuditEvent(ctx, scope, entities.AuditActionCredentialsRead, entities.AuditResourceModel, model.ID, credentialChanges(keyID)); err != nil {
+			return nil, err
+		}
 	}
 
 	// Construct credentials
 	creds := &entities.Credentials{
+		KeyID:   keyID,
 		APIKey:  apiKey,
 		BaseURL: model.BaseURL,
 		Headers: make(map[string]string), // Initialize empty map or load from config if needed
@@ -99,58 +343,338 @@ func (u *modelUsecase) GetCredentials(ctx context.Context, modelID string) (*ent
 	return creds, nil
 }
 
-// LogUsage logs AI usage
-func (u *modelUsecase) LogUsage(ctx context.Context, payload *entities.LogUsagePayload) errors.BaseError {
-	// Validate model exists
-	_, err := u.repository.GetModel(ctx, payload.ModelID)
+// SelectCredential checks the model can be called by the scope and picks a key from its pool.
+// The key is nil when the model has no pool and its own key applies.
+func (u *modelUsecase) SelectCredential(ctx context.Context, scope *entities.TenantScope, modelID string) (*entities.AIModel, *entities.ProviderKey, errors.BaseError) {
+	model, err := u.GetModel(ctx, scope, modelID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := u.checkTenantActive(ctx, scope); err != nil {
+		return nil, nil, err
+	}
+
+	// Deprecated models keep serving until their sunset; the proxy then redirects to the replacement
+	if model.Deprecation != nil && model.Deprecation.Sunset(time.Now()) {
+		return nil, nil, errors.BadRequest(fmt.Sprintf(constants.ErrModelSunset, model.Deprecation.SunsetAt.UTC().Format(time.DateOnly)))
+	}
+	if model.Status != entities.ModelStatusActive && model.Status != entities.ModelStatusDeprecated {
+		return nil, nil, errors.BadRequest(fmt.Sprintf("model is not active: %s", model.Status))
+	}
+
+	if u.keySelector == nil {
+		return model, nil, nil
+	}
+	key, err := u.keySelector.SelectKey(ctx, model.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Catalog models are created without a key until an operator adds one
+	if key == nil && model.EncryptedAPIKey == "" {
+		return nil, nil, errors.BadRequest(constants.ErrNoActiveProviderKeys)
+	}
+	return model, key, nil
+}
+
+// LogUsage logs AI usage, attributing it to the caller's tenant and project
+func (u *modelUsecase) LogUsage(ctx context.Context, scope *entities.TenantScope, payload *entities.LogUsagePayload) errors.BaseError {
+	if !scope.IsPlatform() {
+		payload.TenantID = scope.TenantID
+		payload.ProjectID = scope.ProjectID
+	}
+	if occurredAt := payload.OccurredAt; occurredAt != nil && !occurredAt.IsZero() {
+		now := time.Now()
+		if occurredAt.After(now.Add(maxUsageClockSkew)) || occurredAt.Before(now.Add(-maxUsageEventAge)) {
+			return errors.BadRequest(fmt.Sprintf(constants.ErrUsageTimeOutOfRange, occurredAt.UTC().Format(time.RFC3339), int(maxUsageEventAge.Hours()/24), maxUsageClockSkew))
+		}
+	}
+
+	// Validate model exists and is visible to the tenant. Calls made just before a model
+	// was deleted are still logged.
+	model, err := u.repository.GetModel(ctx, payload.ModelID)
+	if err != nil && err.GetCode() == errors.NOT_FOUND {
+		model, err = u.repository.GetDeletedModel(ctx, payload.ModelID)
+	}
 	if err != nil {
 		return err
 	}
+	if payload.TenantID != "" && model.TenantID != "" && model.TenantID != payload.TenantID {
+		return errors.Forbidden(constants.ErrTenantAccessDenied)
+	}
+	payload.ModelID = model.ID
+
+	if err := u.repository.LogUsage(ctx, payload); err != nil {
+		// A redelivered event was already counted; treat it as logged
+		if err.GetCode() == errors.CONFLICT_ERROR {
+			return nil
+		}
+		return err
+	}
+
+	// Budgets are evaluated off the request path so alert delivery never slows the caller
+	if u.budgetEvaluator != nil {
+		u.budgetEvaluator.QueueUsage(payload)
+	}
+
+	return nil
+}
+
+// LogUsageBatch logs a batch of usage events. It returns the IDs of events rejected permanently
+// (unknown model, bad payload, access denied) and of events that failed for a reason of their
+// own, which the sender should retry; the rest of the batch is logged either way. Errors not
+// tied to one event, from the database or a cancelled request, fail the whole batch so the
+// sender retries it without counting an attempt against each event. Events already stored are
+// deduplicated by their event ID.
+func (u *modelUsecase) LogUsageBatch(ctx context.Context, scope *entities.TenantScope, payloads []*entities.LogUsagePayload) ([]string, []string, errors.BaseError) {
+	if len(payloads) > maxUsageBatchSize {
+		return nil, nil, errors.BadRequest(fmt.Sprintf(constants.ErrUsageBatchTooLarge, maxUsageBatchSize))
+	}
+
+	rejected := make([]string, 0)
+	failed := make([]string, 0)
+	for _, payload := range payloads {
+		if ctx.Err() != nil {
+			return nil, nil, errors.Internal(fmt.Errorf(constants.ErrFailedToLogUsage, ctx.Err()))
+		}
+		err := u.LogUsage(ctx, scope, payload)
+		if err == nil {
+			continue
+		}
+		switch err.GetCode() {
+		case errors.BAD_REQUEST, errors.NOT_FOUND, errors.FORBIDDEN:
+			rejected = append(rejected, payload.EventID)
+		case errors.INTERNAL_ERROR, errors.SERVICE_UNAVAILABLE:
+			return nil, nil, err
+		default:
+			log.Printf("Failed to log usage event %s: %v", payload.EventID, err)
+			failed = append(failed, payload.EventID)
+		}
+	}
 
-	return u.repository.LogUsage(ctx, payload)
+	return rejected, failed, nil
 }
 
-// CheckQuota checks if quota is exceeded
-func (u *modelUsecase) CheckQuota(ctx context.Context, modelID string) (*entities.QuotaStatus, errors.BaseError) {
+// CheckQuota checks if the model or tenant quota is exceeded.
+// Model quotas are counted per tenant, so each tenant gets its own allowance on shared models.
+func (u *modelUsecase) CheckQuota(ctx context.Context, scope *entities.TenantScope, modelID string) (*entities.QuotaStatus, errors.BaseError) {
 	// Get model
-	model, err := u.repository.GetModel(ctx, modelID)
+	model, err := u.GetModel(ctx, scope, modelID)
 	if err != nil {
 		return nil, err
 	}
 
+	tenantID := ""
+	if !scope.IsPlatform() {
+		tenantID = scope.TenantID
+	}
+
 	now := time.Now()
 
 	// Get daily usage
-	dailyUsed, err := u.repository.GetDailyUsage(ctx, modelID, now)
+	dailyUsed, err := u.repository.GetDailyUsage(ctx, model.ID, tenantID, now)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get monthly usage
-	monthlyUsed, err := u.repository.GetMonthlyUsage(ctx, modelID, now.Year(), int(now.Month()))
+	monthlyUsed, err := u.repository.GetMonthlyUsage(ctx, model.ID, tenantID, now.Year(), int(now.Month()))
 	if err != nil {
 		return nil, err
 	}
 
+	tenantQuota, err := u.tenantQuota(ctx, tenantID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return quotaStatus(model, dailyUsed, monthlyUsed, tenantQuota, now), nil
+}
+
+// tenantQuota loads a tenant's quota limits and usage across all models. It is empty for
+// platform callers, which have no tenant quota.
+func (u *modelUsecase) tenantQuota(ctx context.Context, tenantID string, now time.Time) (*entities.QuotaStatus, errors.BaseError) {
+	status := &entities.QuotaStatus{}
+	if tenantID == "" {
+		return status, nil
+	}
+
+	tenant, err := u.tenantRepository.GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	status.TenantDailyLimit = tenant.QuotaDaily
+	status.TenantMonthlyLimit = tenant.QuotaMonthly
+	if status.TenantDailyUsed, err = u.repository.GetTenantDailyUsage(ctx, tenantID, now); err != nil {
+		return nil, err
+	}
+	if status.TenantMonthlyUsed, err = u.repository.GetTenantMonthlyUsage(ctx, tenantID, now.Year(), int(now.Month())); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// quotaStatus combines a model's usage with the tenant quota into the model's quota status
+func quotaStatus(model *entities.AIModel, dailyUsed, monthlyUsed int64, tenant *entities.QuotaStatus, now time.Time) *entities.QuotaStatus {
+	status := &entities.QuotaStatus{
+		DailyUsed:          dailyUsed,
+		DailyLimit:         model.QuotaDaily,
+		MonthlyUsed:        monthlyUsed,
+		MonthlyLimit:       model.QuotaMonthly,
+		TenantDailyUsed:    tenant.TenantDailyUsed,
+		TenantDailyLimit:   tenant.TenantDailyLimit,
+		TenantMonthlyUsed:  tenant.TenantMonthlyUsed,
+		TenantMonthlyLimit: tenant.TenantMonthlyLimit,
+	}
+
 	// Check if exceeded
-	exceeded := false
-	if model.QuotaDaily > 0 && dailyUsed >= model.QuotaDaily {
-		exceeded = true
-	}
-	if model.QuotaMonthly > 0 && monthlyUsed >= model.QuotaMonthly {
-		exceeded = true
-	}
-
-	// Calculate reset time (next day at midnight)
-	tomorrow := now.AddDate(0, 0, 1)
-	resetTime := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, tomorrow.Location())
-
-	return &entities.QuotaStatus{
-		Exceeded:     exceeded,
-		DailyUsed:    dailyUsed,
-		DailyLimit:   model.QuotaDaily,
-		MonthlyUsed:  monthlyUsed,
-		MonthlyLimit: model.QuotaMonthly,
-		ResetTime:    resetTime.Format(time.RFC3339),
-	}, nil
+	if (model.QuotaDaily > 0 && dailyUsed >= model.QuotaDaily) ||
+		(model.QuotaMonthly > 0 && monthlyUsed >= model.QuotaMonthly) {
+		status.Exceeded = true
+		status.ExceededScope = entities.QuotaScopeModel
+	} else if (status.TenantDailyLimit > 0 && status.TenantDailyUsed >= status.TenantDailyLimit) ||
+		(status.TenantMonthlyLimit > 0 && status.TenantMonthlyUsed >= status.TenantMonthlyLimit) {
+		status.Exceeded = true
+		status.ExceededScope = entities.QuotaScopeTenant
+	}
+
+	// Calculate reset time (next day at midnight UTC, matching the daily rollup buckets)
+	tomorrow := now.UTC().AddDate(0, 0, 1)
+	resetTime := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC)
+	status.ResetTime = resetTime.Format(time.RFC3339)
+
+	return status
+}
+
+// defaultUsageStatsWindow is the date range used when a stats query has no start time
+const defaultUsageStatsWindow = 30 * 24 * time.Hour
+
+// GetUsageStats aggregates usage logs. Tenant-scoped callers only see their own tenant's usage.
+func (u *modelUsecase) GetUsageStats(ctx context.Context, scope *entities.TenantScope, filter *entities.UsageStatsFilter) ([]*entities.UsageStats, errors.BaseError) {
+	if !scope.IsPlatform() {
+		filter.TenantID = scope.TenantID
+	}
+	if filter.EndTime.IsZero() {
+		filter.EndTime = time.Now()
+	}
+	if filter.StartTime.IsZero() {
+		filter.StartTime = filter.EndTime.Add(-defaultUsageStatsWindow)
+	}
+	if !filter.StartTime.Before(filter.EndTime) {
+		return nil, errors.BadRequest(constants.ErrInvalidTimeRange)
+	}
+	if filter.ModelID != "" {
+		model, err := u.GetModel(ctx, scope, filter.ModelID)
+		// The usage of deleted models stays available until they are purged
+		if err != nil && err.GetCode() == errors.NOT_FOUND {
+			if deleted, deletedErr := u.repository.GetDeletedModel(ctx, filter.ModelID); deletedErr == nil && scope.CanAccessTenant(deleted.TenantID) {
+				model, err = deleted, nil
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		filter.ModelID = model.ID
+	}
+
+	return u.repository.GetUsageStats(ctx, filter)
+}
+
+// SetModelPricing adds a pricing version to a model. Existing versions are never modified,
+// so usage logged before EffectiveFrom keeps the price it was recorded with.
+func (u *modelUsecase) SetModelPricing(ctx context.Context, scope *entities.TenantScope, payload *entities.SetModelPricingPayload) (*entities.ModelPricing, errors.BaseError) {
+	if payload.ModelID == "" {
+		return nil, errors.BadRequest(constants.ErrInvalidModelID)
+	}
+	for _, price := range []decimal.Decimal{
+		payload.InputPer1kTokens,
+		payload.OutputPer1kTokens,
+		payload.CachedReadPer1kTokens,
+		payload.CacheWritePer1kTokens,
+		payload.PerImage,
+		payload.PerRequest,
+	} {
+		if price.IsNegative() {
+			return nil, errors.BadRequest(constants.ErrInvalidPricing)
+		}
+	}
+
+	model, err := u.GetModel(ctx, scope, payload.ModelID)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.CanManageTenant(model.TenantID) {
+		return nil, errors.Forbidden(constants.ErrTenantAccessDenied)
+	}
+	payload.ModelID = model.ID
+
+	pricing, err := u.repository.CreatePricing(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, u.audit, scope, entities.AuditActionPricingSet, model.ID, pricingChanges(pricing))
+	return pricing, nil
+}
+
+// ListModelPricing lists the pricing history of a model
+func (u *modelUsecase) ListModelPricing(ctx context.Context, scope *entities.TenantScope, modelID string) ([]*entities.ModelPricing, errors.BaseError) {
+	model, err := u.GetModel(ctx, scope, modelID)
+	if err != nil {
+		return nil, err
+	}
+	return u.repository.ListPricing(ctx, model.ID)
+}
+
+// checkFallbackModels checks each fallback of a model is another model the scope can use
+func (u *modelUsecase) checkFallbackModels(ctx context.Context, scope *entities.TenantScope, modelName string, fallbacks []string) errors.BaseError {
+	for _, fallback := range fallbacks {
+		if fallback == "" || fallback == modelName {
+			return errors.BadRequest(fmt.Sprintf(constants.ErrInvalidFallback, fallback, "must name another model"))
+		}
+		model, err := u.GetModel(ctx, scope, fallback)
+		if err != nil {
+			return errors.BadRequest(fmt.Sprintf(constants.ErrInvalidFallback, fallback, err.Error()))
+		}
+		if model.Name == modelName {
+			return errors.BadRequest(fmt.Sprintf(constants.ErrInvalidFallback, fallback, "must name another model"))
+		}
+	}
+	return nil
+}
+
+// validateModelLimits rejects negative limits and an output limit above the context window
+func validateModelLimits(limits *entities.ModelLimits) errors.BaseError {
+	if limits.ContextWindow < 0 || limits.MaxOutputTokens < 0 ||
+		(limits.ContextWindow > 0 && limits.MaxOutputTokens > limits.ContextWindow) {
+		return errors.BadRequest(constants.ErrInvalidModelLimits)
+	}
+	return nil
+}
+
+// manageableModel loads a model the scope is allowed to modify. Aliases are not resolved, so
+// changes always name the model itself.
+func (u *modelUsecase) manageableModel(ctx context.Context, scope *entities.TenantScope, id string) (*entities.AIModel, errors.BaseError) {
+	model, err := u.getModel(ctx, scope, id)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.CanManageTenant(model.TenantID) {
+		return nil, errors.Forbidden(constants.ErrTenantAccessDenied)
+	}
+	return model, nil
+}
+
+// checkTenantActive rejects requests from suspended tenants
+func (u *modelUsecase) checkTenantActive(ctx context.Context, scope *entities.TenantScope) errors.BaseError {
+	if scope.IsPlatform() {
+		return nil
+	}
+	tenant, err := u.tenantRepository.GetTenant(ctx, scope.TenantID)
+	if err != nil {
+		return err
+	}
+	if tenant.Status != entities.TenantStatusActive {
+		return errors.Forbidden(constants.ErrTenantSuspended)
+	}
+	return nil
 }
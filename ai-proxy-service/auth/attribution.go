@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/metadata"
+)
+
+// Metadata keys identifying the end user and conversation a request belongs to.
+// The gRPC gateway forwards the X-User-Id and X-Session-Id HTTP headers under these keys.
+const (
+	MetadataUserID    = "x-user-id"
+	MetadataSessionID = "x-session-id"
+)
+
+// UsageAttribution returns the user and session that usage should be attributed to.
+// A user bound to the caller's API key takes precedence over the x-user-id header.
+// Values that are not UUIDs are ignored, since usage logs key users and sessions by UUID.
+func UsageAttribution(ctx context.Context) (userID, sessionID string) {
+	if caller := CallerFromContext(ctx); caller != nil {
+		userID = caller.UserID
+	}
+	if userID == "" {
+		userID = uuidFromMetadata(ctx, MetadataUserID)
+	}
+	return userID, uuidFromMetadata(ctx, MetadataSessionID)
+}
+
+func uuidFromMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+
+	value := strings.TrimSpace(values[0])
+	if uuid.Validate(value) != nil {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-proxy-service/common/errors"
+	"github.com/blcvn/backend/services/ai-proxy-service/entities"
+)
+
+type iKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, apiKey string) (*entities.Caller, errors.BaseError)
+}
+
+// MaxCacheTTL bounds how long a verified key is trusted without asking the AI Model Service
+// again, so revoked, expired and over-budget keys stop working within seconds
+const MaxCacheTTL = 5 * time.Second
+
+type cachedCaller struct {
+	caller    *entities.Caller
+	expiresAt time.Time
+}
+
+// Authenticator verifies virtual API keys against the AI Model Service,
+// caching successful verifications for a short TTL
+type Authenticator struct {
+	verifier iKeyVerifier
+	ttl      time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedCaller
+}
+
+// NewAuthenticator creates a new API key authenticator. ttl is capped at MaxCacheTTL.
+func NewAuthenticator(verifier iKeyVerifier, ttl time.Duration) *Authenticator {
+	ttl = min(ttl, MaxCacheTTL)
+	return &Authenticator{
+		verifier: verifier,
+		ttl:      ttl,
+		cache:    make(map[string]cachedCaller),
+	}
+}
+
+// Authenticate resolves a plaintext API key to a caller identity
+func (a *Authenticator) Authenticate(ctx context.Context, apiKey string) (*entities.Caller, errors.BaseError) {
+	if apiKey == "" {
+		return nil, errors.Unauthorized("missing api key")
+	}
+
+	sum := sha256.Sum256([]byte(apiKey))
+	cacheKey := hex.EncodeToString(sum[:])
+
+	a.mu.Lock()
+	if entry, ok := a.cache[cacheKey]; ok {
+		if time.Now().Before(entry.expiresAt) {
+			a.mu.Unlock()
+			return entry.caller, nil
+		}
+		delete(a.cache, cacheKey)
+	}
+	a.mu.Unlock()
+
+	caller, err := a.verifier.VerifyAPIKey(ctx, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	// Calls without a forwarded tenant act at platform level in the AI Model Service, so a
+	// caller must never be without one
+	if caller.TenantID == "" {
+		return nil, errors.Unauthorized("api key is not bound to a tenant")
+	}
+
+	if a.ttl > 0 {
+		a.mu.Lock()
+		a.cache[cacheKey] = cachedCaller{caller: caller, expiresAt: time.Now().Add(a.ttl)}
+		a.mu.Unlock()
+	}
+
+	return caller, nil
+}
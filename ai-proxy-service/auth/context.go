@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/blcvn/backend/services/ai-proxy-service/entities"
+)
+
+type callerKey struct{}
+
+// WithCaller attaches the authenticated caller to the context
+func WithCaller(ctx context.Context, caller *entities.Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFromContext returns the authenticated caller, or nil when the request is anonymous
+func CallerFromContext(ctx context.Context) *entities.Caller {
+	caller, _ := ctx.Value(callerKey{}).(*entities.Caller)
+	return caller
+}
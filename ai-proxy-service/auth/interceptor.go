@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/blcvn/backend/services/ai-proxy-service/common/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata keys carrying the virtual API key. The gRPC gateway forwards the
+// HTTP Authorization header as "authorization" and X-Api-Key as "x-api-key".
+const (
+	MetadataAuthorization = "authorization"
+	MetadataAPIKey        = "x-api-key"
+)
+
+// publicMethods lists method name suffixes that do not require authentication
+var publicMethods = []string{"/HealthCheck"}
+
+// UnaryServerInterceptor authenticates unary calls and attaches the caller to the context
+func UnaryServerInterceptor(a *Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if isPublic(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		authCtx, err := a.authenticateContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(authCtx, req)
+	}
+}
+
+// StreamServerInterceptor authenticates streaming calls and attaches the caller to the context
+func StreamServerInterceptor(a *Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isPublic(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		authCtx, err := a.authenticateContext(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: authCtx})
+	}
+}
+
+func (a *Authenticator) authenticateContext(ctx context.Context) (context.Context, error) {
+	caller, err := a.Authenticate(ctx, apiKeyFromMetadata(ctx))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return WithCaller(ctx, caller), nil
+}
+
+// apiKeyFromMetadata extracts the API key from "authorization: Bearer <key>" or "x-api-key"
+func apiKeyFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get(MetadataAuthorization); len(values) > 0 {
+		value := strings.TrimSpace(values[0])
+		if len(value) > 7 && strings.EqualFold(value[:7], "bearer ") {
+			return strings.TrimSpace(value[7:])
+		}
+	}
+	if values := md.Get(MetadataAPIKey); len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	return ""
+}
+
+func isPublic(fullMethod string) bool {
+	for _, suffix := range publicMethods {
+		if strings.HasSuffix(fullMethod, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func toStatus(err errors.BaseError) error {
+	switch err.GetCode() {
+	case errors.UNAUTHORIZED:
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.FORBIDDEN:
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return status.Error(codes.Unavailable, err.Error())
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Metadata keys carrying the caller's tenant scope to the AI Model Service
+const (
+	MetadataTenantID  = "x-tenant-id"
+	MetadataProjectID = "x-project-id"
+)
+
+// ScopeClientInterceptor forwards the authenticated caller's tenant and project
+// to the AI Model Service so model visibility and quotas are tenant-scoped
+func ScopeClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withScopeMetadata(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func withScopeMetadata(ctx context.Context) context.Context {
+	caller := CallerFromContext(ctx)
+	if caller == nil || caller.TenantID == "" {
+		return ctx
+	}
+
+	pairs := []string{MetadataTenantID, caller.TenantID}
+	if caller.ProjectID != "" {
+		pairs = append(pairs, MetadataProjectID, caller.ProjectID)
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
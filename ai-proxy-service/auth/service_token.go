@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ServiceTokenClientInterceptor authenticates the proxy to the AI Model Service with a service
+// token, which internal RPCs require when the proxy has no mTLS client certificate
+func ServiceTokenClientInterceptor(token string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withServiceToken(ctx, token), method, req, reply, cc, opts...)
+	}
+}
+
+// ServiceTokenStreamClientInterceptor is ServiceTokenClientInterceptor for streaming RPCs
+func ServiceTokenStreamClientInterceptor(token string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withServiceToken(ctx, token), desc, cc, method, opts...)
+	}
+}
+
+func withServiceToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, MetadataAuthorization, "Bearer "+token)
+}
@@ -8,10 +8,16 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/blcvn/backend/services/ai-proxy-service/auth"
 	"github.com/blcvn/backend/services/ai-proxy-service/controllers"
 	"github.com/blcvn/backend/services/ai-proxy-service/helper"
+	"github.com/blcvn/backend/services/ai-proxy-service/outbox"
+	"github.com/blcvn/backend/services/ai-proxy-service/providers"
 	"github.com/blcvn/backend/services/ai-proxy-service/providers/anthropic"
 	"github.com/blcvn/backend/services/ai-proxy-service/usecases"
 	pb "github.com/blcvn/kratos-proto/go/ai-proxy"
@@ -19,6 +25,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -41,13 +48,125 @@ func runServe(cmd *cobra.Command, args []string) {
 	modelSvcAddr := getEnv("AI_MODEL_SERVICE_ADDR", "localhost:9085")
 	grpcPort := getEnv("GRPC_PORT", "9087")
 	httpPort := getEnv("HTTP_PORT", "8087")
+	authRequired := getEnv("AUTH_REQUIRED", "true") != "false"
+	apiKeyCacheTTL, err := time.ParseDuration(getEnv("API_KEY_CACHE_TTL", "5s"))
+	if err != nil {
+		log.Fatalf("Invalid API_KEY_CACHE_TTL: %v", err)
+	}
 
-	modelClient, err := helper.NewAIModelClient(modelSvcAddr)
+	proxyID := getEnv("PROXY_ID", "ai-proxy-service")
+
+	// TLS for the proxy's own servers and its connection to the AI Model Service
+	tlsCtx, stopTLS := context.WithCancel(context.Background())
+	defer stopTLS()
+	certFile, keyFile := tlsCertificateFiles(proxyID)
+	serverTLS, requireClientCert := loadServerTLS(tlsCtx, certFile, keyFile)
+	modelSvcTLS := modelServiceTLS(tlsCtx, modelSvcAddr, certFile, keyFile)
+	var modelSvcCreds credentials.TransportCredentials
+	if modelSvcTLS != nil {
+		modelSvcCreds = credentials.NewTLS(modelSvcTLS)
+	}
+
+	modelClient, err := helper.NewAIModelClient(modelSvcAddr, modelSvcCreds, getEnv("AI_MODEL_SERVICE_TOKEN", ""))
 	if err != nil {
 		log.Fatalf("Failed to connect to AI Model Service: %v", err)
 	}
+	switch credentialMode := helper.CredentialMode(getEnv("CREDENTIAL_MODE", string(helper.CredentialModeDirect))); credentialMode {
+	case helper.CredentialModeLease:
+		// Leases are bound to the proxy's client certificate, presented to the egress as well
+		if modelSvcTLS == nil {
+			log.Fatalf("CREDENTIAL_MODE=%s requires AI_MODEL_SERVICE_TLS=true", credentialMode)
+		}
+		egressTransport := http.DefaultTransport.(*http.Transport).Clone()
+		egressTransport.TLSClientConfig = modelSvcTLS.Clone()
+		providers.UseEgressTransport(egressTransport)
+		modelClient.UseCredentialLeases()
+		log.Println("Using credential leases; provider calls go through the AI Model Service egress")
+	case helper.CredentialModeDirect:
+		if modelSvcCreds == nil {
+			log.Println("Warning: AI_MODEL_SERVICE_TLS is off, provider API keys are fetched in cleartext")
+		}
+	default:
+		log.Fatalf("Invalid CREDENTIAL_MODE: %s", credentialMode)
+	}
 
-	usecase := usecases.NewProxyUsecase(modelClient)
+	// Cache model and credential lookups, invalidated by the AI Model Service's model watch
+	modelCacheSize, err := strconv.ParseUint(getEnv("MODEL_CACHE_SIZE", "1000"), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid MODEL_CACHE_SIZE: %v", err)
+	}
+	modelCacheTTL, err := time.ParseDuration(getEnv("MODEL_CACHE_TTL", "5m"))
+	if err != nil {
+		log.Fatalf("Invalid MODEL_CACHE_TTL: %v", err)
+	}
+	credentialCacheTTL, err := time.ParseDuration(getEnv("CREDENTIAL_CACHE_TTL", "30s"))
+	if err != nil {
+		log.Fatalf("Invalid CREDENTIAL_CACHE_TTL: %v", err)
+	}
+	cachedModelClient := helper.NewCachedModelClient(modelClient, helper.ModelCacheOptions{
+		Capacity:      modelCacheSize,
+		ModelTTL:      modelCacheTTL,
+		CredentialTTL: credentialCacheTTL,
+	})
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go cachedModelClient.Watch(watchCtx)
+
+	// Durable usage outbox, delivered to the AI Model Service in batches
+	outboxCtx, stopOutbox := context.WithCancel(context.Background())
+	defer stopOutbox()
+	usageBatchSize, err := strconv.Atoi(getEnv("USAGE_BATCH_SIZE", "100"))
+	if err != nil {
+		log.Fatalf("Invalid USAGE_BATCH_SIZE: %v", err)
+	}
+	usageFlushInterval, err := time.ParseDuration(getEnv("USAGE_FLUSH_INTERVAL", "2s"))
+	if err != nil {
+		log.Fatalf("Invalid USAGE_FLUSH_INTERVAL: %v", err)
+	}
+	usageMaxBackoff, err := time.ParseDuration(getEnv("USAGE_MAX_BACKOFF", "1m"))
+	if err != nil {
+		log.Fatalf("Invalid USAGE_MAX_BACKOFF: %v", err)
+	}
+	usageMaxAttempts, err := strconv.Atoi(getEnv("USAGE_MAX_ATTEMPTS", "10"))
+	if err != nil {
+		log.Fatalf("Invalid USAGE_MAX_ATTEMPTS: %v", err)
+	}
+	usageOutbox, err := outbox.NewOutbox(getEnv("USAGE_OUTBOX_DIR", "./data/usage-outbox"), modelClient, outbox.Options{
+		BatchSize:     usageBatchSize,
+		FlushInterval: usageFlushInterval,
+		MaxBackoff:    usageMaxBackoff,
+		MaxAttempts:   usageMaxAttempts,
+	})
+	if err != nil {
+		log.Fatalf("Failed to open usage outbox: %v", err)
+	}
+	go usageOutbox.Run(outboxCtx)
+
+	limitPolicy := usecases.LimitPolicy(getEnv("LIMIT_POLICY", string(usecases.LimitPolicyTruncate)))
+	if limitPolicy != usecases.LimitPolicyTruncate && limitPolicy != usecases.LimitPolicyReject {
+		log.Fatalf("Invalid LIMIT_POLICY: %s", limitPolicy)
+	}
+
+	// Per-model circuit breakers and latency percentiles used by tier routing
+	breakerFailures, err := strconv.Atoi(getEnv("CIRCUIT_BREAKER_MAX_REQUESTS", "5"))
+	if err != nil {
+		log.Fatalf("Invalid CIRCUIT_BREAKER_MAX_REQUESTS: %v", err)
+	}
+	breakerTimeout, err := strconv.Atoi(getEnv("CIRCUIT_BREAKER_TIMEOUT", "60"))
+	if err != nil {
+		log.Fatalf("Invalid CIRCUIT_BREAKER_TIMEOUT: %v", err)
+	}
+	latencyWindow, err := strconv.Atoi(getEnv("ROUTING_LATENCY_WINDOW", "100"))
+	if err != nil {
+		log.Fatalf("Invalid ROUTING_LATENCY_WINDOW: %v", err)
+	}
+	health := usecases.NewHealthTracker(usecases.HealthOptions{
+		FailureThreshold: breakerFailures,
+		OpenTimeout:      time.Duration(breakerTimeout) * time.Second,
+		LatencyWindow:    latencyWindow,
+	})
+
+	usecase := usecases.NewProxyUsecase(cachedModelClient, usageOutbox, limitPolicy, health)
 
 	// Register Providers
 	// Note: API Key and Model ID are dynamic per request, but the factory needs initial dummy or changing the provider signature.
@@ -73,7 +192,27 @@ func runServe(cmd *cobra.Command, args []string) {
 
 	controller := controllers.NewProxyController(usecase)
 
-	grpcServer := grpc.NewServer()
+	var serverOpts []grpc.ServerOption
+	if authRequired {
+		authenticator := auth.NewAuthenticator(modelClient, apiKeyCacheTTL)
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(auth.UnaryServerInterceptor(authenticator)),
+			grpc.ChainStreamInterceptor(auth.StreamServerInterceptor(authenticator)),
+		)
+	} else {
+		log.Println("Warning: AUTH_REQUIRED=false, proxy accepts unauthenticated calls")
+	}
+
+	if serverTLS != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(serverTLS.ServerConfig(requireClientCert))))
+		if requireClientCert {
+			log.Println("mTLS enabled, callers must present a client certificate")
+		} else {
+			log.Println("TLS enabled")
+		}
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
 	pb.RegisterAIProxyServiceServer(grpcServer, controller)
 
 	grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%s", grpcPort))
@@ -89,8 +228,14 @@ func runServe(cmd *cobra.Command, args []string) {
 	}()
 
 	ctx := context.Background()
-	gwMux := runtime.NewServeMux()
+	gwMux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(forwardAuthHeaders),
+	)
 	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if serverTLS != nil {
+		// The gateway dials its own gRPC server over loopback, presenting the proxy's certificate
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(serverTLS.LoopbackClientConfig()))}
+	}
 
 	err = pb.RegisterAIProxyServiceHandlerFromEndpoint(ctx, gwMux, fmt.Sprintf("localhost:%s", grpcPort), opts)
 	if err != nil {
@@ -105,10 +250,20 @@ func runServe(cmd *cobra.Command, args []string) {
 		Addr:    fmt.Sprintf(":%s", httpPort),
 		Handler: mux,
 	}
+	if serverTLS != nil {
+		httpServer.TLSConfig = serverTLS.ServerConfig(requireClientCert)
+	}
 
 	go func() {
 		log.Printf("Starting HTTP on %s", httpPort)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if serverTLS != nil {
+			// Certificates come from TLSConfig, which reloads them
+			err = httpServer.ListenAndServeTLS("", "")
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatalf("HTTP serve error: %v", err)
 		}
 	}()
@@ -121,6 +276,19 @@ func runServe(cmd *cobra.Command, args []string) {
 	grpcServer.GracefulStop()
 }
 
+// forwardedHeaders are HTTP headers passed to gRPC metadata in addition to the defaults
+var forwardedHeaders = []string{auth.MetadataAPIKey, auth.MetadataUserID, auth.MetadataSessionID}
+
+// forwardAuthHeaders forwards the API key and usage attribution headers to gRPC metadata
+func forwardAuthHeaders(key string) (string, bool) {
+	for _, header := range forwardedHeaders {
+		if strings.EqualFold(key, header) {
+			return header, true
+		}
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
 func getEnv(key, def string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-proxy-service/tlsconfig"
+)
+
+// tlsReloadInterval is how often certificate files are checked for replacements
+const tlsReloadInterval = 30 * time.Second
+
+// tlsCertificateFiles returns the certificate the proxy serves and presents to the AI Model
+// Service. With TLS_DEV_MODE=true and no TLS_CERT, a self-signed CA and certificate are generated.
+func tlsCertificateFiles(proxyID string) (string, string) {
+	certFile, keyFile := getEnv("TLS_CERT", ""), getEnv("TLS_KEY", "")
+	if certFile != "" || getEnv("TLS_DEV_MODE", "false") != "true" {
+		return certFile, keyFile
+	}
+
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if hostname, err := os.Hostname(); err == nil {
+		hosts = append(hosts, hostname)
+	}
+	dev, err := tlsconfig.GenerateDevCertificates(getEnv("TLS_DEV_DIR", "./data/dev-certs"), proxyID, hosts)
+	if err != nil {
+		log.Fatalf("Failed to generate dev TLS certificates: %v", err)
+	}
+	log.Printf("Warning: TLS_DEV_MODE=true, serving a self-signed certificate; clients must trust %s", dev.CAFile)
+	return dev.CertFile, dev.KeyFile
+}
+
+// loadServerTLS loads the proxy's server certificate, or returns nil when TLS is not configured.
+// With TLS_CLIENT_CA set, callers must present a client certificate it issued.
+func loadServerTLS(ctx context.Context, certFile, keyFile string) (*tlsconfig.Reloader, bool) {
+	if certFile == "" {
+		return nil, false
+	}
+
+	clientCA := getEnv("TLS_CLIENT_CA", "")
+	reloader, err := tlsconfig.NewReloader(certFile, keyFile, clientCA)
+	if err != nil {
+		log.Fatalf("Failed to load TLS certificate: %v", err)
+	}
+	go reloader.Run(ctx, tlsReloadInterval)
+	return reloader, clientCA != ""
+}
+
+// modelServiceTLS returns the client TLS configuration for the AI Model Service and its egress,
+// or nil for plaintext. The proxy presents its own certificate so the AI Model Service can check
+// its identity, and the server must match AI_MODEL_SERVICE_SERVER_NAME and, if set,
+// AI_MODEL_SERVICE_IDENTITY.
+func modelServiceTLS(ctx context.Context, addr, certFile, keyFile string) *tls.Config {
+	if getEnv("AI_MODEL_SERVICE_TLS", "false") != "true" {
+		return nil
+	}
+
+	reloader, err := tlsconfig.NewReloader(certFile, keyFile, getEnv("AI_MODEL_SERVICE_CA", ""))
+	if err != nil {
+		log.Fatalf("Failed to load AI Model Service TLS configuration: %v", err)
+	}
+	go reloader.Run(ctx, tlsReloadInterval)
+
+	serverName := getEnv("AI_MODEL_SERVICE_SERVER_NAME", "")
+	if serverName == "" {
+		serverName = addr
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			serverName = host
+		}
+	}
+	return reloader.ClientConfig(serverName, getEnv("AI_MODEL_SERVICE_IDENTITY", ""))
+}
@@ -7,6 +7,7 @@ type ErrorCode int
 const (
 	BAD_REQUEST    ErrorCode = 400
 	UNAUTHORIZED   ErrorCode = 401
+	FORBIDDEN      ErrorCode = 403
 	NOT_FOUND      ErrorCode = 404
 	INTERNAL_ERROR ErrorCode = 500
 	RATE_LIMIT     ErrorCode = 429
@@ -29,8 +30,9 @@ func NewBaseError(code ErrorCode, err error) BaseError {
 func (e *baseError) Error() string      { return e.err.Error() }
 func (e *baseError) GetCode() ErrorCode { return e.code }
 
-func BadRequest(msg string) BaseError   { return NewBaseError(BAD_REQUEST, fmt.Errorf(msg)) }
-func Unauthorized(msg string) BaseError { return NewBaseError(UNAUTHORIZED, fmt.Errorf(msg)) }
-func NotFound(msg string) BaseError     { return NewBaseError(NOT_FOUND, fmt.Errorf(msg)) }
+func BadRequest(msg string) BaseError   { return NewBaseError(BAD_REQUEST, fmt.Errorf("%s", msg)) }
+func Unauthorized(msg string) BaseError { return NewBaseError(UNAUTHORIZED, fmt.Errorf("%s", msg)) }
+func Forbidden(msg string) BaseError    { return NewBaseError(FORBIDDEN, fmt.Errorf("%s", msg)) }
+func NotFound(msg string) BaseError     { return NewBaseError(NOT_FOUND, fmt.Errorf("%s", msg)) }
 func Internal(err error) BaseError      { return NewBaseError(INTERNAL_ERROR, err) }
-func RateLimit(msg string) BaseError    { return NewBaseError(RATE_LIMIT, fmt.Errorf(msg)) }
+func RateLimit(msg string) BaseError    { return NewBaseError(RATE_LIMIT, fmt.Errorf("%s", msg)) }
@@ -2,10 +2,31 @@ package controllers
 
 import (
 	"context"
+	"log"
+	"time"
 
 	"github.com/blcvn/backend/services/ai-proxy-service/entities"
 	"github.com/blcvn/backend/services/ai-proxy-service/usecases"
 	aiproxy "github.com/blcvn/kratos-proto/go/ai-proxy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Response metadata describing how a tier request was routed; the HTTP gateway returns them as
+// Grpc-Metadata-X-Routing-* headers
+const (
+	metadataRoutingTier   = "x-routing-tier"
+	metadataRoutingModel  = "x-routing-model"
+	metadataRoutingReason = "x-routing-reason"
+)
+
+// Response metadata warning callers of a deprecated model; the HTTP gateway returns them as
+// Grpc-Metadata-X-Model-* headers
+const (
+	metadataModelDeprecation  = "x-model-deprecation"
+	metadataModelSunset       = "x-model-sunset"
+	metadataModelReplacement  = "x-model-replacement"
+	metadataModelRedirectedTo = "x-model-redirected-to"
 )
 
 // ProxyController implements the AIProxyService gRPC interface
@@ -34,13 +55,14 @@ func (c *ProxyController) Complete(ctx context.Context, req *aiproxy.CompleteReq
 
 	// Convert proto request to entity request
 	entityReq := &entities.CompletionRequest{
-		ModelID: req.Payload.ModelId,
-		Messages: []entities.Message{
-			{Role: entities.RoleUser, Content: req.Payload.Prompt}, // TODO: Support chat messages from proto if available or needed
-		},
+		ModelID:       req.Payload.ModelId,
+		Messages:      completionMessages(req.Payload),
 		Temperature:   float32(req.Payload.Temperature),
 		MaxTokens:     int32(req.Payload.MaxTokens),
 		StopSequences: req.Payload.Stop,
+		JSONMode:      req.Payload.JsonMode,
+		Tools:         completionTools(req.Payload.Tools),
+		Tier:          req.Payload.Tier,
 	}
 
 	// Execute completion
@@ -49,12 +71,38 @@ func (c *ProxyController) Complete(ctx context.Context, req *aiproxy.CompleteReq
 	if err != nil {
 		return &aiproxy.CompleteResponse{
 			Result: &aiproxy.Result{
-				Code:    aiproxy.ResultCode_INTERNAL,
+				Code:    aiproxy.ResultCode(err.GetCode()),
 				Message: err.Error(),
 			},
 		}, nil
 	}
 
+	if routing := response.Routing; routing != nil {
+		header := metadata.Pairs(
+			metadataRoutingTier, routing.Tier,
+			metadataRoutingModel, routing.Model,
+			metadataRoutingReason, routing.Reason,
+		)
+		if err := grpc.SetHeader(ctx, header); err != nil {
+			log.Printf("Warning: failed to set routing metadata: %v", err)
+		}
+	}
+	if deprecation := response.Deprecation; deprecation != nil {
+		header := metadata.Pairs(metadataModelDeprecation, deprecation.Message)
+		if deprecation.SunsetAt != nil {
+			header.Set(metadataModelSunset, deprecation.SunsetAt.Format(time.RFC3339))
+		}
+		if deprecation.ReplacementID != "" {
+			header.Set(metadataModelReplacement, deprecation.ReplacementID)
+		}
+		if deprecation.Redirected {
+			header.Set(metadataModelRedirectedTo, response.Model)
+		}
+		if err := grpc.SetHeader(ctx, header); err != nil {
+			log.Printf("Warning: failed to set deprecation metadata: %v", err)
+		}
+	}
+
 	// Convert provider response to proto response
 	return &aiproxy.CompleteResponse{
 		Result: &aiproxy.Result{
@@ -63,18 +111,72 @@ func (c *ProxyController) Complete(ctx context.Context, req *aiproxy.CompleteReq
 		},
 		Completion: &aiproxy.CompletionResponse{
 			Id:               "", // TODO: generate ID
-			ModelId:          req.Payload.ModelId,
+			ModelId:          response.Model,
 			Text:             response.Content,
 			TotalTokens:      int32(response.Usage.TotalTokens),
 			PromptTokens:     int32(response.Usage.PromptTokens),
 			CompletionTokens: int32(response.Usage.CompletionTokens),
 			LatencyMs:        0, // TODO: track latency
 			FromCache:        fromCache,
-			Provider:         "", // TODO: track provider
+			Provider:         response.Provider,
+			ToolCalls:        toolCallsToPb(response.ToolCalls),
 		},
 	}, nil
 }
 
+// completionMessages returns the payload's chat messages, or its prompt as a single user message
+func completionMessages(payload *aiproxy.CompletePayload) []entities.Message {
+	if len(payload.Messages) == 0 {
+		return []entities.Message{{Role: entities.RoleUser, Content: payload.Prompt}}
+	}
+	messages := make([]entities.Message, 0, len(payload.Messages))
+	for _, m := range payload.Messages {
+		messages = append(messages, entities.Message{
+			Role:      messageRole(m.Role),
+			Content:   m.Content,
+			ImageURLs: m.ImageUrls,
+		})
+	}
+	return messages
+}
+
+func messageRole(role aiproxy.Role) entities.MessageRole {
+	switch role {
+	case aiproxy.Role_ROLE_SYSTEM:
+		return entities.RoleSystem
+	case aiproxy.Role_ROLE_ASSISTANT:
+		return entities.RoleAssistant
+	case aiproxy.Role_ROLE_TOOL:
+		return entities.RoleTool
+	default:
+		return entities.RoleUser
+	}
+}
+
+func completionTools(definitions []*aiproxy.ToolDefinition) []entities.Tool {
+	tools := make([]entities.Tool, 0, len(definitions))
+	for _, d := range definitions {
+		tools = append(tools, entities.Tool{
+			Name:        d.Name,
+			Description: d.Description,
+			Parameters:  d.ParametersJson,
+		})
+	}
+	return tools
+}
+
+func toolCallsToPb(calls []entities.ToolCall) []*aiproxy.ToolCall {
+	pbCalls := make([]*aiproxy.ToolCall, 0, len(calls))
+	for _, call := range calls {
+		pbCalls = append(pbCalls, &aiproxy.ToolCall{
+			Id:            call.ID,
+			Name:          call.Name,
+			ArgumentsJson: call.Arguments,
+		})
+	}
+	return pbCalls
+}
+
 // StreamComplete handles streaming completion requests (not implemented yet)
 func (c *ProxyController) StreamComplete(req *aiproxy.CompleteRequest, stream aiproxy.AIProxyService_StreamCompleteServer) error {
 	// TODO: Implement streaming
@@ -0,0 +1,26 @@
+package entities
+
+// Caller is the authenticated identity behind a proxy request
+type Caller struct {
+	APIKeyID      string
+	TenantID      string
+	ProjectID     string
+	UserID        string
+	AllowedModels []string
+}
+
+// CanUseModel reports whether the caller is scoped to the given model.
+// An empty allow-list grants access to every model.
+func (c *Caller) CanUseModel(identifiers ...string) bool {
+	if len(c.AllowedModels) == 0 {
+		return true
+	}
+	for _, allowed := range c.AllowedModels {
+		for _, id := range identifiers {
+			if id != "" && allowed == id {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -2,6 +2,9 @@ package entities
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
 )
 
 type MessageRole string
@@ -16,6 +19,24 @@ const (
 type Message struct {
 	Role    MessageRole
 	Content string
+	// ImageURLs are images sent with the message, as URLs or data: URIs
+	ImageURLs []string
+}
+
+// Tool is a function the model may call
+type Tool struct {
+	Name        string
+	Description string
+	// Parameters is the JSON schema of the function's arguments
+	Parameters string
+}
+
+// ToolCall is a call to one of the request's tools that the model asks for
+type ToolCall struct {
+	ID   string
+	Name string
+	// Arguments is the JSON object of the call's arguments
+	Arguments string
 }
 
 type CompletionRequest struct {
@@ -24,15 +45,93 @@ type CompletionRequest struct {
 	Temperature   float32
 	MaxTokens     int32
 	StopSequences []string
+	// JSONMode asks the model to answer with a JSON object
+	JSONMode bool
+	// Tools are the functions the model may call
+	Tools []Tool
+	// Alias is the alias the request named when it was routed through the alias's weighted
+	// routes; set by usecase
+	Alias string
+	// Tier asks for a model of a capability tier (cheap, balanced or best) instead of ModelID
+	Tier string
+	// Routing records why a tier request went to its model; set by usecase
+	Routing *RoutingDecision
+	// HealthTrial is the circuit breaker trial a tier request was let through as, 0 if none;
+	// set by usecase
+	HealthTrial uint64
+	// Deprecation warns that the requested model is deprecated or was retired; set by usecase
+	Deprecation *DeprecationNotice
+	// RequestedModelID is the retired model a request was redirected from; set by usecase
+	RequestedModelID string
 	// Credentials injected by usecase
 	APIKey  string
 	BaseURL string
+	Headers map[string]string
+}
+
+// PromptHash returns a SHA-256 fingerprint of the conversation, so repeated prompts
+// can be grouped in usage analytics without storing their content
+func (r *CompletionRequest) PromptHash() string {
+	h := sha256.New()
+	for _, m := range r.Messages {
+		h.Write([]byte(m.Role))
+		h.Write([]byte{0})
+		h.Write([]byte(m.Content))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HasImages reports whether any message carries an image
+func (r *CompletionRequest) HasImages() bool {
+	for _, m := range r.Messages {
+		if len(m.ImageURLs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// EstimatePromptTokens approximates the prompt size (1 token ≈ 4 characters), for checking it
+// against a model's context window before the call
+func (r *CompletionRequest) EstimatePromptTokens() int32 {
+	chars := 0
+	for _, m := range r.Messages {
+		chars += len(m.Content)
+	}
+	return int32((chars + 3) / 4)
 }
 
 type CompletionResponse struct {
+	// Model and Provider identify the concrete model that served the request: the model an
+	// alias resolves to, or a fallback when the requested model cannot handle it; set by usecase
+	Model        string
+	Provider     string
 	Content      string
+	ToolCalls    []ToolCall
 	Usage        Usage
 	FinishReason string
+	// Routing is the routing decision of a tier request; set by usecase
+	Routing *RoutingDecision
+	// Deprecation is the deprecation notice of the requested model; set by usecase
+	Deprecation *DeprecationNotice
+}
+
+// RoutingDecision is the model a tier request was routed to and why
+type RoutingDecision struct {
+	Tier   string
+	Model  string
+	Reason string
+}
+
+// DeprecationNotice tells callers of a deprecated model when it is retired and what replaces it
+type DeprecationNotice struct {
+	Model         string
+	SunsetAt      *time.Time
+	ReplacementID string
+	// Redirected is set when the model was retired and the request was served by its replacement
+	Redirected bool
+	Message    string
 }
 
 type Usage struct {
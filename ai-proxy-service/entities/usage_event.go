@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+// UsageStatus is the outcome of a provider call
+type UsageStatus string
+
+const (
+	UsageStatusSuccess UsageStatus = "success"
+	UsageStatusError   UsageStatus = "error"
+	UsageStatusTimeout UsageStatus = "timeout"
+)
+
+// UsageEvent is a billable usage record queued in the proxy outbox.
+// ID is stable across retries so the AI Model Service can drop duplicates.
+type UsageEvent struct {
+	ID               string      `json:"id"`
+	ModelID          string      `json:"model_id"`
+	TenantID         string      `json:"tenant_id,omitempty"`
+	ProjectID        string      `json:"project_id,omitempty"`
+	UserID           string      `json:"user_id,omitempty"`
+	SessionID        string      `json:"session_id,omitempty"`
+	APIKeyID         string      `json:"api_key_id,omitempty"`
+	Alias            string      `json:"alias,omitempty"`
+	RequestedModelID string      `json:"requested_model_id,omitempty"`
+	PromptHash       string      `json:"prompt_hash,omitempty"`
+	PromptTokens     int32       `json:"prompt_tokens"`
+	CompletionTokens int32       `json:"completion_tokens"`
+	LatencyMs        int32       `json:"latency_ms"`
+	Status           UsageStatus `json:"status,omitempty"`
+	ErrorMessage     string      `json:"error_message,omitempty"`
+	OccurredAt       time.Time   `json:"occurred_at"`
+	// Attempts counts the deliveries in which this event failed on its own
+	Attempts int `json:"attempts,omitempty"`
+}
@@ -5,11 +5,15 @@ go 1.24.4
 require (
 	github.com/blcvn/kratos-proto/go/ai-model v1.0.0
 	github.com/blcvn/kratos-proto/go/ai-proxy v1.0.0
+	github.com/google/uuid v1.6.0
 	github.com/grpc-ecosystem/grpc-gateway/v2 v2.27.7
+	github.com/jellydator/ttlcache/v3 v3.1.0
 	github.com/prometheus/client_golang v1.23.2
 	github.com/spf13/cobra v1.10.2
 	github.com/tmc/langchaingo v0.1.14
+	golang.org/x/sync v0.19.0
 	google.golang.org/grpc v1.78.0
+	google.golang.org/protobuf v1.36.11
 )
 
 require (
@@ -17,7 +21,6 @@ require (
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/dlclark/regexp2 v1.10.0 // indirect
 	github.com/golang/protobuf v1.5.4 // indirect
-	github.com/google/uuid v1.6.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
 	github.com/pkoukk/tiktoken-go v0.1.6 // indirect
@@ -34,9 +37,14 @@ require (
 	golang.org/x/text v0.34.0 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20260128011058-8636f8732409 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20260128011058-8636f8732409 // indirect
-	google.golang.org/protobuf v1.36.11 // indirect
 )
 
 replace github.com/blcvn/ba-shared-libs/pkg => ../../ba-shared-libs/pkg
 
 replace github.com/blcvn/ba-shared-libs/proto => ../../ba-shared-libs/proto
+
+// The AI Model Service and proxy APIs used here are not in a tagged kratos-proto release yet
+
+replace github.com/blcvn/kratos-proto/go/ai-model => ../../kratos-proto/go/ai-model
+
+replace github.com/blcvn/kratos-proto/go/ai-proxy => ../../kratos-proto/go/ai-proxy
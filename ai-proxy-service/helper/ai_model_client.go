@@ -3,35 +3,97 @@ package helper
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/blcvn/backend/services/ai-proxy-service/auth"
+	"github.com/blcvn/backend/services/ai-proxy-service/common/errors"
+	"github.com/blcvn/backend/services/ai-proxy-service/entities"
 	model_pb "github.com/blcvn/kratos-proto/go/ai-model"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CredentialMode selects how the proxy obtains provider credentials
+type CredentialMode string
+
+const (
+	// CredentialModeDirect fetches raw API keys and calls providers directly
+	CredentialModeDirect CredentialMode = "direct"
+	// CredentialModeLease fetches short-lived credential leases and calls providers through
+	// the AI Model Service egress, which injects the key
+	CredentialModeLease CredentialMode = "lease"
 )
 
 type AIModelClient struct {
-	client model_pb.AIModelServiceClient
+	client         model_pb.AIModelServiceClient
+	credentialMode CredentialMode
 }
 
-func NewAIModelClient(addr string) (*AIModelClient, error) {
-	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// NewAIModelClient connects to the AI Model Service. A nil creds dials without TLS; a non-empty
+// serviceToken is sent with every call to authorize the proxy's internal RPCs.
+func NewAIModelClient(addr string, creds credentials.TransportCredentials, serviceToken string) (*AIModelClient, error) {
+	if creds == nil {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.Dial(addr,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithChainUnaryInterceptor(auth.ScopeClientInterceptor(), auth.ServiceTokenClientInterceptor(serviceToken)),
+		grpc.WithChainStreamInterceptor(auth.ServiceTokenStreamClientInterceptor(serviceToken)),
+	)
 	if err != nil {
 		return nil, err
 	}
 	return &AIModelClient{
-		client: model_pb.NewAIModelServiceClient(conn),
+		client:         model_pb.NewAIModelServiceClient(conn),
+		credentialMode: CredentialModeDirect,
 	}, nil
 }
 
+// UseCredentialLeases switches GetCredentials to credential leases. Leases are bound to the
+// client certificate the proxy presents, so the connection must use mTLS.
+func (c *AIModelClient) UseCredentialLeases() {
+	c.credentialMode = CredentialModeLease
+}
+
+// GetCredentials returns the credentials for one provider call. In lease mode the API key is a
+// credential lease and the base URL points at the egress, so providers are called unchanged.
 func (c *AIModelClient) GetCredentials(ctx context.Context, modelID string) (*model_pb.Credentials, error) {
+	creds, _, err := c.credentials(ctx, modelID)
+	return creds, err
+}
+
+// credentials fetches credentials along with the time a credential lease expires;
+// raw credentials return a zero expiry
+func (c *AIModelClient) credentials(ctx context.Context, modelID string) (*model_pb.Credentials, time.Time, error) {
+	if c.credentialMode == CredentialModeLease {
+		return c.leaseCredentials(ctx, modelID)
+	}
+
 	resp, err := c.client.GetCredentials(ctx, &model_pb.GetCredentialsRequest{ModelId: modelID})
 	if err != nil {
-		return nil, err
+		return nil, time.Time{}, err
+	}
+	if resp.Result.Code != model_pb.ResultCode_SUCCESS {
+		return nil, time.Time{}, fmt.Errorf("failed to get credentials: %s", resp.Result.Message)
+	}
+	return resp.Credentials, time.Time{}, nil
+}
+
+func (c *AIModelClient) leaseCredentials(ctx context.Context, modelID string) (*model_pb.Credentials, time.Time, error) {
+	resp, err := c.client.IssueCredentialLease(ctx, &model_pb.IssueCredentialLeaseRequest{ModelId: modelID})
+	if err != nil {
+		return nil, time.Time{}, err
 	}
 	if resp.Result.Code != model_pb.ResultCode_SUCCESS {
-		return nil, fmt.Errorf("failed to get credentials: %s", resp.Result.Message)
+		return nil, time.Time{}, fmt.Errorf("failed to get credential lease: %s", resp.Result.Message)
 	}
-	return resp.Credentials, nil
+	return &model_pb.Credentials{
+		KeyId:   resp.Lease.KeyId,
+		ApiKey:  resp.Lease.Token,
+		BaseUrl: resp.Lease.BaseUrl,
+	}, resp.Lease.ExpiresAt.AsTime(), nil
 }
 
 func (c *AIModelClient) GetModel(ctx context.Context, modelID string) (*model_pb.AIModel, error) {
@@ -45,6 +107,11 @@ func (c *AIModelClient) GetModel(ctx context.Context, modelID string) (*model_pb
 	return resp.Model, nil
 }
 
+// WatchModels opens the stream of model changes used to invalidate cached lookups
+func (c *AIModelClient) WatchModels(ctx context.Context) (model_pb.AIModelService_WatchModelsClient, error) {
+	return c.client.WatchModels(ctx, &model_pb.WatchModelsRequest{})
+}
+
 func (c *AIModelClient) CheckQuota(ctx context.Context, modelID string, tokens int32) (bool, error) {
 	// Note: CheckQuotaRequest in proto currently only supports model_id.
 	// Tokens check might need proto update or logic change.
@@ -63,12 +130,113 @@ func (c *AIModelClient) CheckQuota(ctx context.Context, modelID string, tokens i
 	return true, nil
 }
 
-func (c *AIModelClient) LogUsage(ctx context.Context, modelID string, promptTokens, completionTokens int32) error {
-	_, err := c.client.LogUsage(ctx, &model_pb.LogUsageRequest{
-		Payload: &model_pb.LogUsagePayload{
-			ModelId:    modelID,
-			TokensUsed: int64(promptTokens + completionTokens),
-		},
+// LogUsageBatch delivers queued usage events. It returns the IDs of events the AI Model Service
+// rejected permanently and of events that failed and should be retried; any error means the
+// whole batch should be retried.
+func (c *AIModelClient) LogUsageBatch(ctx context.Context, events []*entities.UsageEvent) ([]string, []string, error) {
+	payloads := make([]*model_pb.LogUsagePayload, len(events))
+	for i, event := range events {
+		// Events queued before outcomes were recorded only describe successful calls
+		status := event.Status
+		if status == "" {
+			status = entities.UsageStatusSuccess
+		}
+		payloads[i] = &model_pb.LogUsagePayload{
+			EventId:          event.ID,
+			ModelId:          event.ModelID,
+			TenantId:         event.TenantID,
+			ProjectId:        event.ProjectID,
+			UserId:           event.UserID,
+			SessionId:        event.SessionID,
+			ApiKeyId:         event.APIKeyID,
+			Alias:            event.Alias,
+			RequestedModelId: event.RequestedModelID,
+			PromptHash:       event.PromptHash,
+			TokensUsed:       int64(event.PromptTokens + event.CompletionTokens),
+			PromptTokens:     int64(event.PromptTokens),
+			CompletionTokens: int64(event.CompletionTokens),
+			LatencyMs:        event.LatencyMs,
+			Status:           model_pb.UsageStatus(model_pb.UsageStatus_value[string(status)]),
+			ErrorMessage:     event.ErrorMessage,
+			OccurredAt:       timestamppb.New(event.OccurredAt),
+		}
+	}
+
+	resp, err := c.client.LogUsageBatch(ctx, &model_pb.LogUsageBatchRequest{Payloads: payloads})
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.Result.Code != model_pb.ResultCode_SUCCESS {
+		return nil, nil, fmt.Errorf("failed to log usage batch: %s", resp.Result.Message)
+	}
+	return resp.RejectedEventIds, resp.FailedEventIds, nil
+}
+
+// CheckBudget reports whether a hard-stop budget blocks the caller from using the model.
+// Tenant and project are forwarded as request metadata by the scope interceptor.
+func (c *AIModelClient) CheckBudget(ctx context.Context, modelID string, caller *entities.Caller) (bool, string, error) {
+	req := &model_pb.GetBudgetStatusRequest{ModelId: modelID}
+	if caller != nil {
+		req.UserId = caller.UserID
+	}
+
+	resp, err := c.client.GetBudgetStatus(ctx, req)
+	if err != nil {
+		return false, "", err
+	}
+	if resp.Result.Code != model_pb.ResultCode_SUCCESS {
+		return false, "", fmt.Errorf("failed to get budget status: %s", resp.Result.Message)
+	}
+	return resp.Blocked, resp.Result.Message, nil
+}
+
+// VerifyAPIKey authenticates a virtual API key against the AI Model Service
+func (c *AIModelClient) VerifyAPIKey(ctx context.Context, apiKey string) (*entities.Caller, errors.BaseError) {
+	resp, err := c.client.VerifyAPIKey(ctx, &model_pb.VerifyAPIKeyRequest{ApiKey: apiKey})
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	if resp.Result.Code != model_pb.ResultCode_SUCCESS {
+		return nil, errors.NewBaseError(errors.ErrorCode(resp.Result.Code), fmt.Errorf("%s", resp.Result.Message))
+	}
+	if resp.Identity == nil {
+		return nil, errors.Unauthorized("api key verification returned no identity")
+	}
+
+	return &entities.Caller{
+		APIKeyID:      resp.Identity.ApiKeyId,
+		TenantID:      resp.Identity.TenantId,
+		ProjectID:     resp.Identity.ProjectId,
+		UserID:        resp.Identity.UserId,
+		AllowedModels: resp.Identity.AllowedModels,
+	}, nil
+}
+
+// ReportKeyFailure reports a pooled provider key rejected with the given HTTP status so it is quarantined
+func (c *AIModelClient) ReportKeyFailure(ctx context.Context, modelID, keyID string, statusCode int32) error {
+	resp, err := c.client.ReportProviderKeyFailure(ctx, &model_pb.ReportProviderKeyFailureRequest{
+		ModelId:    modelID,
+		KeyId:      keyID,
+		StatusCode: statusCode,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	if resp.Result.Code != model_pb.ResultCode_SUCCESS {
+		return fmt.Errorf("failed to report provider key failure: %s", resp.Result.Message)
+	}
+	return nil
+}
+
+// ListRoutingCandidates lists the active models of a tier the caller's tenant can use, with their
+// price and remaining quota. It is not cached, since the quota changes with every request.
+func (c *AIModelClient) ListRoutingCandidates(ctx context.Context, tier string) ([]*model_pb.RoutingCandidate, error) {
+	resp, err := c.client.ListRoutingCandidates(ctx, &model_pb.ListRoutingCandidatesRequest{Tier: tier})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Result.Code != model_pb.ResultCode_SUCCESS {
+		return nil, fmt.Errorf("failed to list routing candidates: %s", resp.Result.Message)
+	}
+	return resp.Candidates, nil
 }
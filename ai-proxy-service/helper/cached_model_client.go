@@ -0,0 +1,279 @@
+package helper
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-proxy-service/auth"
+	"github.com/blcvn/backend/services/ai-proxy-service/metrics"
+	model_pb "github.com/blcvn/kratos-proto/go/ai-model"
+	"github.com/jellydator/ttlcache/v3"
+	"golang.org/x/sync/singleflight"
+)
+
+// modelEventCredentialsChanged announces a change to a model's key pool that leaves the model itself as is
+const modelEventCredentialsChanged = "credentials_changed"
+
+const (
+	// cacheFetchTimeout bounds a lookup shared by concurrent cache misses
+	cacheFetchTimeout = 30 * time.Second
+	// leaseExpiryMargin stops a cached credential lease from being handed out just before it expires
+	leaseExpiryMargin = 10 * time.Second
+	// Reconnect policy of the model watch
+	modelWatchBackoff    = time.Second
+	maxModelWatchBackoff = 30 * time.Second
+)
+
+// ModelCacheOptions configures the model and credential caches
+type ModelCacheOptions struct {
+	// Capacity bounds each cache; the least recently used entries are evicted first
+	Capacity uint64
+	// ModelTTL is how long a model is cached; 0 disables model caching
+	ModelTTL time.Duration
+	// CredentialTTL is how long credentials are reused; 0 disables credential caching.
+	// Cached credentials pin the pool key they were issued for, so keep it short.
+	CredentialTTL time.Duration
+}
+
+// CachedModelClient serves model and credential lookups from TTL and LRU bounded caches.
+// Concurrent misses for the same entry share one RPC, and entries are dropped when the
+// AI Model Service announces a change through WatchModels.
+type CachedModelClient struct {
+	*AIModelClient
+
+	models        *ttlcache.Cache[string, *model_pb.AIModel]
+	credentials   *ttlcache.Cache[string, *model_pb.Credentials]
+	modelTTL      time.Duration
+	credentialTTL time.Duration
+	group         singleflight.Group
+	// generation changes on every invalidation, so a lookup that raced one is not cached
+	generation atomic.Uint64
+}
+
+// NewCachedModelClient wraps client with model and credential caches
+func NewCachedModelClient(client *AIModelClient, opts ModelCacheOptions) *CachedModelClient {
+	c := &CachedModelClient{
+		AIModelClient: client,
+		models: ttlcache.New(
+			ttlcache.WithTTL[string, *model_pb.AIModel](opts.ModelTTL),
+			ttlcache.WithCapacity[string, *model_pb.AIModel](opts.Capacity),
+			ttlcache.WithDisableTouchOnHit[string, *model_pb.AIModel](),
+		),
+		credentials: ttlcache.New(
+			ttlcache.WithTTL[string, *model_pb.Credentials](opts.CredentialTTL),
+			ttlcache.WithCapacity[string, *model_pb.Credentials](opts.Capacity),
+			ttlcache.WithDisableTouchOnHit[string, *model_pb.Credentials](),
+		),
+		modelTTL:      opts.ModelTTL,
+		credentialTTL: opts.CredentialTTL,
+	}
+	go c.models.Start()
+	go c.credentials.Start()
+	return c
+}
+
+// GetModel returns the model from the cache, fetching it on a miss
+func (c *CachedModelClient) GetModel(ctx context.Context, modelID string) (*model_pb.AIModel, error) {
+	if c.modelTTL <= 0 {
+		return c.AIModelClient.GetModel(ctx, modelID)
+	}
+
+	key := cacheKey(ctx, modelID)
+	if item := c.models.Get(key); item != nil {
+		metrics.ModelCacheLookups.WithLabelValues("model", "hit").Inc()
+		return item.Value(), nil
+	}
+	metrics.ModelCacheLookups.WithLabelValues("model", "miss").Inc()
+
+	value, err := c.load(ctx, "model:"+key, func(ctx context.Context) (any, error) {
+		generation := c.generation.Load()
+		model, err := c.AIModelClient.GetModel(ctx, modelID)
+		if err == nil && c.generation.Load() == generation {
+			c.models.Set(key, model, ttlcache.DefaultTTL)
+		}
+		return model, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*model_pb.AIModel), nil
+}
+
+// GetCredentials returns credentials from the cache, fetching them on a miss. A cached
+// credential lease is only reused while it stays valid for leaseExpiryMargin.
+func (c *CachedModelClient) GetCredentials(ctx context.Context, modelID string) (*model_pb.Credentials, error) {
+	if c.credentialTTL <= 0 {
+		return c.AIModelClient.GetCredentials(ctx, modelID)
+	}
+
+	key := cacheKey(ctx, modelID)
+	if item := c.credentials.Get(key); item != nil {
+		metrics.ModelCacheLookups.WithLabelValues("credentials", "hit").Inc()
+		return item.Value(), nil
+	}
+	metrics.ModelCacheLookups.WithLabelValues("credentials", "miss").Inc()
+
+	value, err := c.load(ctx, "credentials:"+key, func(ctx context.Context) (any, error) {
+		generation := c.generation.Load()
+		creds, expiresAt, err := c.AIModelClient.credentials(ctx, modelID)
+		if err != nil {
+			return nil, err
+		}
+
+		ttl := c.credentialTTL
+		if !expiresAt.IsZero() {
+			ttl = min(ttl, time.Until(expiresAt)-leaseExpiryMargin)
+		}
+		if ttl > 0 && c.generation.Load() == generation {
+			c.credentials.Set(key, creds, ttl)
+		}
+		return creds, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*model_pb.Credentials), nil
+}
+
+// ReportKeyFailure reports a rejected pool key and drops every cached credential that carries it,
+// so the retry picks another key
+func (c *CachedModelClient) ReportKeyFailure(ctx context.Context, modelID, keyID string, statusCode int32) error {
+	err := c.AIModelClient.ReportKeyFailure(ctx, modelID, keyID, statusCode)
+
+	c.generation.Add(1)
+	for _, key := range c.credentials.Keys() {
+		if item := c.credentials.Get(key); item != nil && item.Value().KeyId == keyID {
+			c.credentials.Delete(key)
+			metrics.ModelCacheInvalidations.WithLabelValues("key_failure").Inc()
+		}
+	}
+	return err
+}
+
+// Watch applies model change events to the caches until ctx is cancelled, reconnecting
+// with backoff. The caches are flushed whenever the watch (re)connects, since changes made
+// while it was down were missed.
+func (c *CachedModelClient) Watch(ctx context.Context) {
+	backoff := modelWatchBackoff
+	for {
+		watched, err := c.watch(ctx)
+		c.purge()
+		if ctx.Err() != nil {
+			return
+		}
+		if watched {
+			backoff = modelWatchBackoff
+		}
+		log.Printf("Warning: model watch disconnected, cached models expire by TTL until it reconnects in %s: %v", backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxModelWatchBackoff)
+	}
+}
+
+// watch consumes one WatchModels stream. It reports whether the watch was established.
+func (c *CachedModelClient) watch(ctx context.Context) (bool, error) {
+	stream, err := c.AIModelClient.WatchModels(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	watched := false
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return watched, err
+		}
+		if resp.Result.Code != model_pb.ResultCode_SUCCESS {
+			// Events may have been lost; the stream ends after this result
+			log.Printf("Warning: model watch interrupted: %s", resp.Result.Message)
+			c.purge()
+			continue
+		}
+		if resp.Event == nil {
+			// The watch is in place: anything cached before it may be stale
+			watched = true
+			c.purge()
+			continue
+		}
+		c.invalidate(resp.Event)
+	}
+}
+
+// invalidate drops the cached entries of the model or alias an event names, whether they were
+// looked up by ID or by name
+func (c *CachedModelClient) invalidate(event *model_pb.ModelEvent) {
+	c.generation.Add(1)
+
+	identifiers := map[string]bool{event.ModelId: true}
+	if event.ModelName != "" {
+		identifiers[event.ModelName] = true
+	}
+
+	if event.Type != modelEventCredentialsChanged {
+		for _, key := range c.models.Keys() {
+			item := c.models.Get(key)
+			if identifiers[modelIdentifier(key)] || (item != nil && item.Value().Id == event.ModelId) {
+				identifiers[modelIdentifier(key)] = true
+				c.models.Delete(key)
+				metrics.ModelCacheInvalidations.WithLabelValues("event").Inc()
+			}
+		}
+	}
+
+	for _, key := range c.credentials.Keys() {
+		if identifiers[modelIdentifier(key)] {
+			c.credentials.Delete(key)
+			metrics.ModelCacheInvalidations.WithLabelValues("event").Inc()
+		}
+	}
+}
+
+// purge drops every cached entry
+func (c *CachedModelClient) purge() {
+	c.generation.Add(1)
+	c.models.DeleteAll()
+	c.credentials.DeleteAll()
+	metrics.ModelCacheInvalidations.WithLabelValues("resync").Inc()
+}
+
+// load runs fetch once for concurrent lookups of the same key. The shared fetch does not
+// inherit the first caller's cancellation, so one abandoned request cannot fail the others.
+func (c *CachedModelClient) load(ctx context.Context, key string, fetch func(context.Context) (any, error)) (any, error) {
+	result := c.group.DoChan(key, func() (any, error) {
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cacheFetchTimeout)
+		defer cancel()
+		return fetch(fetchCtx)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-result:
+		return res.Val, res.Err
+	}
+}
+
+// cacheKey scopes an entry to the caller's tenant and project, which the AI Model Service
+// uses to decide which models are visible. Keys have the form "<tenant>/<project>/<model>".
+func cacheKey(ctx context.Context, modelID string) string {
+	var tenantID, projectID string
+	if caller := auth.CallerFromContext(ctx); caller != nil {
+		tenantID, projectID = caller.TenantID, caller.ProjectID
+	}
+	return tenantID + "/" + projectID + "/" + modelID
+}
+
+// modelIdentifier returns the model ID or name a cache key was looked up by
+func modelIdentifier(key string) string {
+	_, rest, _ := strings.Cut(key, "/")
+	_, modelID, _ := strings.Cut(rest, "/")
+	return modelID
+}
@@ -43,6 +43,43 @@ var (
 		[]string{"model_id", "provider"},
 	)
 
+	// AliasRouteRequests tracks requests routed through an alias's weighted routes, per model
+	AliasRouteRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ai_proxy_alias_route_requests_total",
+			Help: "Total requests routed through model aliases",
+		},
+		[]string{"alias", "model", "status"}, // status: success, error, timeout
+	)
+
+	// AliasRouteDuration tracks the latency of requests routed through an alias, per model
+	AliasRouteDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "ai_proxy_alias_route_duration_seconds",
+			Help:    "Duration in seconds of requests routed through model aliases",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"alias", "model"},
+	)
+
+	// AliasRouteTokens tracks tokens used by requests routed through an alias, per model
+	AliasRouteTokens = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ai_proxy_alias_route_tokens_total",
+			Help: "Total tokens used by requests routed through model aliases",
+		},
+		[]string{"alias", "model", "type"}, // type: prompt, completion
+	)
+
+	// AliasRouteCost tracks the estimated cost of requests routed through an alias, per model
+	AliasRouteCost = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ai_proxy_alias_route_cost_total",
+			Help: "Estimated cost in USD of requests routed through model aliases, at the model's per-1k-token rate",
+		},
+		[]string{"alias", "model"},
+	)
+
 	// CacheHits tracks cache hit/miss
 	CacheHits = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -52,12 +89,97 @@ var (
 		[]string{"status"}, // status: hit, miss
 	)
 
-	// CircuitBreakerState tracks circuit breaker states
+	// ModelCacheLookups tracks model and credential lookups served from the proxy cache
+	ModelCacheLookups = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ai_proxy_model_cache_lookups_total",
+			Help: "Total model and credential cache lookups",
+		},
+		[]string{"cache", "status"}, // cache: model, credentials; status: hit, miss
+	)
+
+	// ModelCacheInvalidations tracks cache entries dropped after model changes
+	ModelCacheInvalidations = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ai_proxy_model_cache_invalidations_total",
+			Help: "Total model cache invalidations",
+		},
+		[]string{"reason"}, // reason: event, key_failure, resync
+	)
+
+	// CircuitBreakerState tracks the circuit breaker state of each model
 	CircuitBreakerState = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "ai_proxy_circuit_breaker_state",
 			Help: "Circuit breaker state (0=closed, 1=open, 2=half-open)",
 		},
-		[]string{"provider"},
+		[]string{"model", "provider"},
+	)
+
+	// TierRoutingDecisions tracks the models tier requests were routed to
+	TierRoutingDecisions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ai_proxy_tier_routing_decisions_total",
+			Help: "Total tier requests routed, by the model picked",
+		},
+		[]string{"tier", "model"},
+	)
+
+	// DeprecatedModelRequests tracks requests for deprecated models
+	DeprecatedModelRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ai_proxy_deprecated_model_requests_total",
+			Help: "Total requests for deprecated models",
+		},
+		[]string{"model", "outcome"}, // outcome: warned, redirected, rejected
+	)
+
+	// UsageOutboxBacklog tracks usage events waiting in the proxy outbox
+	UsageOutboxBacklog = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "ai_proxy_usage_outbox_backlog",
+			Help: "Number of usage events waiting to be delivered",
+		},
+	)
+
+	// UsageOutboxLag tracks the age of the oldest undelivered usage event
+	UsageOutboxLag = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "ai_proxy_usage_outbox_lag_seconds",
+			Help: "Age in seconds of the oldest undelivered usage event",
+		},
+	)
+
+	// UsageOutboxSent tracks usage events delivered to the AI Model Service
+	UsageOutboxSent = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "ai_proxy_usage_outbox_sent_total",
+			Help: "Total usage events delivered",
+		},
+	)
+
+	// UsageOutboxRejected tracks usage events permanently rejected by the AI Model Service
+	UsageOutboxRejected = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "ai_proxy_usage_outbox_rejected_total",
+			Help: "Total usage events rejected and dropped",
+		},
+	)
+
+	// UsageOutboxDeadLettered tracks usage events moved aside after failing too many deliveries
+	UsageOutboxDeadLettered = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "ai_proxy_usage_outbox_dead_lettered_total",
+			Help: "Total usage events moved to the dead-letter directory",
+		},
+	)
+
+	// UsageOutboxErrors tracks outbox failures
+	UsageOutboxErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ai_proxy_usage_outbox_errors_total",
+			Help: "Total usage outbox errors",
+		},
+		[]string{"stage"}, // stage: enqueue, send
 	)
 )
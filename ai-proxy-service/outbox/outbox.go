@@ -0,0 +1,357 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-proxy-service/entities"
+	"github.com/blcvn/backend/services/ai-proxy-service/metrics"
+)
+
+// eventFileSuffix marks committed event files; partially written files use tmpFileSuffix
+const (
+	eventFileSuffix = ".json"
+	tmpFileSuffix   = ".tmp"
+)
+
+// deadLetterDir is the subdirectory events are moved to once they failed MaxAttempts deliveries
+const deadLetterDir = "dead-letter"
+
+// iBatchSender delivers a batch of usage events and returns the IDs it rejected permanently
+// and the IDs that failed and should be retried
+type iBatchSender interface {
+	LogUsageBatch(ctx context.Context, events []*entities.UsageEvent) ([]string, []string, error)
+}
+
+// Options tunes batching and retry behaviour
+type Options struct {
+	BatchSize     int
+	FlushInterval time.Duration
+	MinBackoff    time.Duration
+	MaxBackoff    time.Duration
+	SendTimeout   time.Duration
+	// MaxAttempts is how many deliveries an event may fail on its own before it is dead-lettered
+	MaxAttempts int
+}
+
+// Outbox is a durable, disk-backed queue of usage events.
+// Each event is written to its own file before Enqueue returns, so events survive
+// restarts and model service outages; a background loop ships them in batches.
+type Outbox struct {
+	dir     string
+	sender  iBatchSender
+	opts    Options
+	notify  chan struct{}
+	mu      sync.Mutex
+	counter uint64
+}
+
+// NewOutbox creates an outbox storing events under dir
+func NewOutbox(dir string, sender iBatchSender, opts Options) (*Outbox, error) {
+	if err := os.MkdirAll(filepath.Join(dir, deadLetterDir), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create outbox dir: %w", err)
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 2 * time.Second
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = time.Second
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = time.Minute
+	}
+	if opts.SendTimeout <= 0 {
+		opts.SendTimeout = 10 * time.Second
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 10
+	}
+
+	o := &Outbox{
+		dir:    dir,
+		sender: sender,
+		opts:   opts,
+		notify: make(chan struct{}, 1),
+	}
+	o.updateGauges()
+	return o, nil
+}
+
+// Enqueue durably stores an event for delivery
+func (o *Outbox) Enqueue(event *entities.UsageEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode usage event: %w", err)
+	}
+
+	// File names sort in enqueue order: nanosecond timestamp, then a per-process counter
+	o.mu.Lock()
+	o.counter++
+	name := fmt.Sprintf("%020d-%08d-%s", time.Now().UnixNano(), o.counter%100000000, event.ID)
+	o.mu.Unlock()
+
+	tmpPath := filepath.Join(o.dir, name+tmpFileSuffix)
+	if err := writeFileSync(tmpPath, data); err != nil {
+		metrics.UsageOutboxErrors.WithLabelValues("enqueue").Inc()
+		return fmt.Errorf("failed to write usage event: %w", err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(o.dir, name+eventFileSuffix)); err != nil {
+		metrics.UsageOutboxErrors.WithLabelValues("enqueue").Inc()
+		return fmt.Errorf("failed to commit usage event: %w", err)
+	}
+
+	metrics.UsageOutboxBacklog.Inc()
+	select {
+	case o.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run ships queued events until ctx is cancelled. Undelivered events stay on disk.
+func (o *Outbox) Run(ctx context.Context) {
+	o.removeStaleTmpFiles()
+
+	ticker := time.NewTicker(o.opts.FlushInterval)
+	defer ticker.Stop()
+
+	backoff := o.opts.MinBackoff
+	for {
+		sent, err := o.flush(ctx)
+		if err != nil {
+			metrics.UsageOutboxErrors.WithLabelValues("send").Inc()
+			wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+			log.Printf("Warning: usage outbox delivery failed, retrying in %s: %v", wait, err)
+			if !sleep(ctx, wait) {
+				return
+			}
+			backoff *= 2
+			if backoff > o.opts.MaxBackoff {
+				backoff = o.opts.MaxBackoff
+			}
+			continue
+		}
+		backoff = o.opts.MinBackoff
+
+		// A full batch cleared from the queue means more may be waiting; keep draining. Events
+		// that failed on their own are retried on the next tick.
+		if sent == o.opts.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-o.notify:
+		}
+	}
+}
+
+// flush sends the oldest batch of events and removes the delivered files, returning how many
+// events left the queue. An error means the whole batch failed, e.g. while the AI Model Service
+// or its database is down, and no event is charged a delivery attempt. Events that failed on
+// their own are kept for the next flush, without backing off the rest of the queue, and are
+// dead-lettered after MaxAttempts.
+func (o *Outbox) flush(ctx context.Context) (int, error) {
+	files, err := o.pendingFiles()
+	if err != nil {
+		return 0, err
+	}
+	o.setGauges(files)
+	if len(files) == 0 {
+		return 0, nil
+	}
+	if len(files) > o.opts.BatchSize {
+		files = files[:o.opts.BatchSize]
+	}
+
+	events := make([]*entities.UsageEvent, 0, len(files))
+	byID := make(map[string]*entities.UsageEvent, len(files))
+	paths := make(map[string]string, len(files))
+	for _, file := range files {
+		path := filepath.Join(o.dir, file)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read usage event: %w", err)
+		}
+
+		var event entities.UsageEvent
+		if err := json.Unmarshal(data, &event); err != nil || event.ID == "" {
+			log.Printf("Warning: dropping corrupt usage event %s: %v", file, err)
+			o.remove(path)
+			continue
+		}
+		events = append(events, &event)
+		byID[event.ID] = &event
+		paths[event.ID] = path
+	}
+	if len(events) == 0 {
+		return len(files), nil
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, o.opts.SendTimeout)
+	defer cancel()
+
+	rejected, failed, err := o.sender.LogUsageBatch(sendCtx, events)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range rejected {
+		log.Printf("Warning: usage event %s rejected by AI Model Service, dropping", id)
+		metrics.UsageOutboxRejected.Inc()
+	}
+	for _, id := range failed {
+		event, ok := byID[id]
+		if !ok {
+			continue
+		}
+		path := paths[id]
+		delete(paths, id)
+		event.Attempts++
+		if event.Attempts >= o.opts.MaxAttempts {
+			o.deadLetter(path, event)
+		} else if err := o.rewrite(path, event); err != nil {
+			log.Printf("Warning: failed to record delivery attempt of usage event %s: %v", id, err)
+		}
+	}
+	for _, path := range paths {
+		o.remove(path)
+	}
+	metrics.UsageOutboxSent.Add(float64(len(events) - len(rejected) - len(failed)))
+
+	kept := 0
+	for _, id := range failed {
+		if event, ok := byID[id]; ok && event.Attempts < o.opts.MaxAttempts {
+			kept++
+		}
+	}
+	if kept > 0 {
+		log.Printf("Warning: %d usage events failed and will be retried", kept)
+	}
+	return len(files) - kept, nil
+}
+
+// rewrite replaces an event file in place, keeping its name and so its place in the queue
+func (o *Outbox) rewrite(path string, event *entities.UsageEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	tmpPath := strings.TrimSuffix(path, eventFileSuffix) + tmpFileSuffix
+	if err := writeFileSync(tmpPath, data); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// deadLetter moves an event that keeps failing out of the queue. Moving its file back into the
+// outbox directory replays it.
+func (o *Outbox) deadLetter(path string, event *entities.UsageEvent) {
+	log.Printf("Warning: usage event %s failed %d deliveries, moving it to %s", event.ID, event.Attempts, deadLetterDir)
+	if err := o.rewrite(path, event); err != nil {
+		log.Printf("Warning: failed to record delivery attempt of usage event %s: %v", event.ID, err)
+	}
+	if err := os.Rename(path, filepath.Join(o.dir, deadLetterDir, filepath.Base(path))); err != nil {
+		log.Printf("Warning: failed to dead-letter usage event %s: %v", event.ID, err)
+		return
+	}
+	metrics.UsageOutboxDeadLettered.Inc()
+}
+
+// pendingFiles lists committed event files oldest first
+func (o *Outbox) pendingFiles() ([]string, error) {
+	entries, err := os.ReadDir(o.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox: %w", err)
+	}
+
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), eventFileSuffix) {
+			files = append(files, entry.Name())
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// setGauges reports backlog size and the age of the oldest pending event
+func (o *Outbox) setGauges(files []string) {
+	metrics.UsageOutboxBacklog.Set(float64(len(files)))
+	if len(files) == 0 {
+		metrics.UsageOutboxLag.Set(0)
+		return
+	}
+
+	prefix, _, _ := strings.Cut(files[0], "-")
+	if enqueuedAt, err := strconv.ParseInt(prefix, 10, 64); err == nil {
+		metrics.UsageOutboxLag.Set(time.Since(time.Unix(0, enqueuedAt)).Seconds())
+	}
+}
+
+func (o *Outbox) updateGauges() {
+	if files, err := o.pendingFiles(); err == nil {
+		o.setGauges(files)
+	}
+}
+
+// removeStaleTmpFiles deletes events that were never committed, e.g. after a crash mid-write
+func (o *Outbox) removeStaleTmpFiles() {
+	matches, err := filepath.Glob(filepath.Join(o.dir, "*"+tmpFileSuffix))
+	if err != nil {
+		return
+	}
+	for _, path := range matches {
+		o.remove(path)
+	}
+}
+
+func (o *Outbox) remove(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.Printf("Warning: failed to remove outbox file %s: %v", path, err)
+	}
+}
+
+// writeFileSync writes data and fsyncs it so a committed event survives a crash
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// sleep waits for d or until ctx is cancelled, reporting whether the wait completed
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
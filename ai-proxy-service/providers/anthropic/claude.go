@@ -30,19 +30,7 @@ func (c *ClaudeProvider) Complete(ctx context.Context, req *entities.CompletionR
 
 	// Build messages
 	// entities.Message -> llms.MessageContent
-	messages := make([]llms.MessageContent, len(req.Messages))
-	for i, m := range req.Messages {
-		role := llms.ChatMessageTypeGeneric
-		switch m.Role {
-		case entities.RoleSystem:
-			role = llms.ChatMessageTypeSystem
-		case entities.RoleUser:
-			role = llms.ChatMessageTypeHuman
-		case entities.RoleAssistant:
-			role = llms.ChatMessageTypeAI
-		}
-		messages[i] = llms.TextParts(role, m.Content)
-	}
+	messages := providers.Messages(req.Messages)
 
 	// Dynamic client creation using injected credentials
 	opts := []anthropic.Option{
@@ -52,35 +40,47 @@ func (c *ClaudeProvider) Complete(ctx context.Context, req *entities.CompletionR
 	if req.BaseURL != "" {
 		opts = append(opts, anthropic.WithBaseURL(req.BaseURL))
 	}
+	if client := providers.HTTPClient(req.Headers); client != nil {
+		opts = append(opts, anthropic.WithHTTPClient(client))
+	}
 
 	ll, err := anthropic.New(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Anthropic LLM: %w", err)
 	}
 
-	// Build options
+	// Build options; max_tokens is required by the API, so fall back to a default when unset
+	maxTokens := 32000
+	if req.MaxTokens > 0 {
+		maxTokens = int(req.MaxTokens)
+	}
 	callOpts := []llms.CallOption{
 		llms.WithTemperature(float64(req.Temperature)),
-		llms.WithMaxTokens(32000),
+		llms.WithMaxTokens(maxTokens),
 	}
 
 	if len(req.StopSequences) > 0 {
 		callOpts = append(callOpts, llms.WithStopWords(req.StopSequences))
 	}
+	if len(req.Tools) > 0 {
+		callOpts = append(callOpts, llms.WithTools(providers.Tools(req.Tools)))
+	}
 
 	// Call LLM
 	response, err := ll.GenerateContent(ctx, messages, callOpts...)
 	if err != nil {
 		log.Printf("Anthropic GenerateContent Error: %v", err)
-		return nil, fmt.Errorf("failed to generate content: %w", err)
+		return nil, fmt.Errorf("failed to generate content: %w (%w)", err, anthropic.MapError(err))
 	}
 
 	// Extract text from response
 	var text string
 	var stopReason string
+	var toolCalls []entities.ToolCall
 	if len(response.Choices) > 0 {
 		text = response.Choices[0].Content
 		stopReason = response.Choices[0].StopReason
+		toolCalls = providers.ToolCalls(response.Choices[0].ToolCalls)
 	}
 
 	// Estimate token counts (rough approximation: 1 token ≈ 4 characters)
@@ -93,6 +93,7 @@ func (c *ClaudeProvider) Complete(ctx context.Context, req *entities.CompletionR
 	log.Printf("Anthropic Usage: Content %s PromptTokens=%d, CompletionTokens=%d, TotalTokens=%d", text, promptTokens, completionTokens, promptTokens+completionTokens)
 	return &entities.CompletionResponse{
 		Content:      text,
+		ToolCalls:    toolCalls,
 		FinishReason: stopReason,
 		Usage: entities.Usage{
 			PromptTokens:     promptTokens,
@@ -105,19 +106,7 @@ func (c *ClaudeProvider) Complete(ctx context.Context, req *entities.CompletionR
 // StreamComplete implements streaming completion
 func (c *ClaudeProvider) StreamComplete(ctx context.Context, req *entities.CompletionRequest, callback func(*entities.StreamResponse) error) error {
 	// Build messages
-	messages := make([]llms.MessageContent, len(req.Messages))
-	for i, m := range req.Messages {
-		role := llms.ChatMessageTypeGeneric
-		switch m.Role {
-		case entities.RoleSystem:
-			role = llms.ChatMessageTypeSystem
-		case entities.RoleUser:
-			role = llms.ChatMessageTypeHuman
-		case entities.RoleAssistant:
-			role = llms.ChatMessageTypeAI
-		}
-		messages[i] = llms.TextParts(role, m.Content)
-	}
+	messages := providers.Messages(req.Messages)
 
 	// Dynamic client creation using injected credentials
 	opts := []anthropic.Option{
@@ -127,6 +116,9 @@ func (c *ClaudeProvider) StreamComplete(ctx context.Context, req *entities.Compl
 	if req.BaseURL != "" {
 		opts = append(opts, anthropic.WithBaseURL(req.BaseURL))
 	}
+	if client := providers.HTTPClient(req.Headers); client != nil {
+		opts = append(opts, anthropic.WithHTTPClient(client))
+	}
 
 	ll, err := anthropic.New(opts...)
 	if err != nil {
@@ -145,10 +137,17 @@ func (c *ClaudeProvider) StreamComplete(ctx context.Context, req *entities.Compl
 	if len(req.StopSequences) > 0 {
 		callOpts = append(callOpts, llms.WithStopWords(req.StopSequences))
 	}
+	if len(req.Tools) > 0 {
+		callOpts = append(callOpts, llms.WithTools(providers.Tools(req.Tools)))
+	}
 
 	// Call LLM
 	_, err = ll.GenerateContent(ctx, messages, callOpts...)
-	return err
+	if err != nil {
+		// Map to standard error codes so rejected keys can be quarantined
+		return fmt.Errorf("%w (%w)", err, anthropic.MapError(err))
+	}
+	return nil
 }
 
 // // GenerateContent uses LangChainGo's standardized interface directly
@@ -0,0 +1,50 @@
+package providers
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/tmc/langchaingo/llms"
+)
+
+// statusCodePattern finds the HTTP status in the errors the LangChainGo clients return
+var statusCodePattern = regexp.MustCompile(`status code: (\d{3})`)
+
+// KeyFailureStatus reports whether a provider error means the API key itself was rejected,
+// returning the equivalent HTTP status: 401 for authentication failures, 429 for rate limiting
+func KeyFailureStatus(err error) (int32, bool) {
+	switch {
+	case err == nil:
+		return 0, false
+	case llms.IsAuthenticationError(err):
+		return http.StatusUnauthorized, true
+	case llms.IsRateLimitError(err):
+		return http.StatusTooManyRequests, true
+	}
+	return 0, false
+}
+
+// IsProviderFailure reports whether a provider error means the provider itself is unhealthy: a
+// 5xx response, a timeout or a transport error. A 4xx answer (an invalid request, a rejected key,
+// rate limiting) is not, since the provider handled the call.
+func IsProviderFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if match := statusCodePattern.FindStringSubmatch(err.Error()); match != nil {
+		status, _ := strconv.Atoi(match[1])
+		return status >= http.StatusInternalServerError
+	}
+
+	var llmErr *llms.Error
+	if errors.As(err, &llmErr) {
+		switch llmErr.Code {
+		case llms.ErrCodeTimeout, llms.ErrCodeProviderUnavailable, llms.ErrCodeUnknown:
+			return true
+		}
+		return false
+	}
+	return true
+}
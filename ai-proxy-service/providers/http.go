@@ -0,0 +1,38 @@
+package providers
+
+import "net/http"
+
+// egressTransport carries every provider call when the proxy uses credential leases. The calls
+// go to the AI Model Service egress, which requires the proxy's client certificate.
+var egressTransport http.RoundTripper
+
+// UseEgressTransport sends provider calls through rt, e.g. an mTLS transport for the egress
+func UseEgressTransport(rt http.RoundTripper) {
+	egressTransport = rt
+}
+
+// HTTPClient returns the HTTP client for a provider call, adding the credential headers to every
+// request, or nil when the SDK's default client will do
+func HTTPClient(headers map[string]string) *http.Client {
+	if len(headers) == 0 && egressTransport == nil {
+		return nil
+	}
+	base := http.DefaultTransport
+	if egressTransport != nil {
+		base = egressTransport
+	}
+	return &http.Client{Transport: &headerTransport{headers: headers, base: base}}
+}
+
+type headerTransport struct {
+	headers map[string]string
+	base    http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+	return t.base.RoundTrip(req)
+}
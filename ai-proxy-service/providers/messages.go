@@ -0,0 +1,65 @@
+package providers
+
+import (
+	"encoding/json"
+
+	"github.com/blcvn/backend/services/ai-proxy-service/entities"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Messages converts request messages to LangChainGo messages; images become image parts
+func Messages(messages []entities.Message) []llms.MessageContent {
+	contents := make([]llms.MessageContent, len(messages))
+	for i, m := range messages {
+		role := llms.ChatMessageTypeGeneric
+		switch m.Role {
+		case entities.RoleSystem:
+			role = llms.ChatMessageTypeSystem
+		case entities.RoleUser:
+			role = llms.ChatMessageTypeHuman
+		case entities.RoleAssistant:
+			role = llms.ChatMessageTypeAI
+		}
+		contents[i] = llms.TextParts(role, m.Content)
+		for _, url := range m.ImageURLs {
+			contents[i].Parts = append(contents[i].Parts, llms.ImageURLPart(url))
+		}
+	}
+	return contents
+}
+
+// Tools converts request tools to LangChainGo function tools
+func Tools(tools []entities.Tool) []llms.Tool {
+	converted := make([]llms.Tool, 0, len(tools))
+	for _, t := range tools {
+		var parameters any = map[string]any{"type": "object"}
+		if t.Parameters != "" {
+			parameters = json.RawMessage(t.Parameters)
+		}
+		converted = append(converted, llms.Tool{
+			Type: "function",
+			Function: &llms.FunctionDefinition{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  parameters,
+			},
+		})
+	}
+	return converted
+}
+
+// ToolCalls converts the function calls of a LangChainGo response choice
+func ToolCalls(calls []llms.ToolCall) []entities.ToolCall {
+	var converted []entities.ToolCall
+	for _, call := range calls {
+		if call.FunctionCall == nil {
+			continue
+		}
+		converted = append(converted, entities.ToolCall{
+			ID:        call.ID,
+			Name:      call.FunctionCall.Name,
+			Arguments: call.FunctionCall.Arguments,
+		})
+	}
+	return converted
+}
@@ -41,19 +41,7 @@ func NewGPTProvider(apiKey, modelID string) (*GPTProvider, error) {
 func (g *GPTProvider) Complete(ctx context.Context, req *entities.CompletionRequest) (*entities.CompletionResponse, error) {
 	// Build messages
 	// entities.Message -> llms.MessageContent
-	messages := make([]llms.MessageContent, len(req.Messages))
-	for i, m := range req.Messages {
-		role := llms.ChatMessageTypeGeneric
-		switch m.Role {
-		case entities.RoleSystem:
-			role = llms.ChatMessageTypeSystem
-		case entities.RoleUser:
-			role = llms.ChatMessageTypeHuman
-		case entities.RoleAssistant:
-			role = llms.ChatMessageTypeAI
-		}
-		messages[i] = llms.TextParts(role, m.Content)
-	}
+	messages := providers.Messages(req.Messages)
 
 	// Dynamic client creation using injected credentials
 	clientOpts := []openai.Option{
@@ -63,6 +51,9 @@ func (g *GPTProvider) Complete(ctx context.Context, req *entities.CompletionRequ
 	if req.BaseURL != "" {
 		clientOpts = append(clientOpts, openai.WithBaseURL(req.BaseURL))
 	}
+	if client := providers.HTTPClient(req.Headers); client != nil {
+		clientOpts = append(clientOpts, openai.WithHTTPClient(client))
+	}
 
 	ll, err := openai.New(clientOpts...)
 	if err != nil {
@@ -79,6 +70,12 @@ func (g *GPTProvider) Complete(ctx context.Context, req *entities.CompletionRequ
 	if len(req.StopSequences) > 0 {
 		callOpts = append(callOpts, llms.WithStopWords(req.StopSequences))
 	}
+	if len(req.Tools) > 0 {
+		callOpts = append(callOpts, llms.WithTools(providers.Tools(req.Tools)))
+	}
+	if req.JSONMode {
+		callOpts = append(callOpts, llms.WithJSONMode())
+	}
 
 	// Call LLM
 	response, err := ll.GenerateContent(ctx, messages, callOpts...)
@@ -89,9 +86,11 @@ func (g *GPTProvider) Complete(ctx context.Context, req *entities.CompletionRequ
 	// Extract text from response
 	var text string
 	var stopReason string
+	var toolCalls []entities.ToolCall
 	if len(response.Choices) > 0 {
 		text = response.Choices[0].Content
 		stopReason = response.Choices[0].StopReason
+		toolCalls = providers.ToolCalls(response.Choices[0].ToolCalls)
 	}
 
 	// Estimate token counts (rough approximation: 1 token ≈ 4 characters)
@@ -104,6 +103,7 @@ func (g *GPTProvider) Complete(ctx context.Context, req *entities.CompletionRequ
 
 	return &entities.CompletionResponse{
 		Content:      text,
+		ToolCalls:    toolCalls,
 		FinishReason: stopReason,
 		Usage: entities.Usage{
 			PromptTokens:     promptTokens,
@@ -128,6 +128,9 @@ func (g *GPTProvider) StreamComplete(ctx context.Context, req *entities.Completi
 	if req.BaseURL != "" {
 		clientOpts = append(clientOpts, openai.WithBaseURL(req.BaseURL))
 	}
+	if client := providers.HTTPClient(req.Headers); client != nil {
+		clientOpts = append(clientOpts, openai.WithHTTPClient(client))
+	}
 
 	ll, err := openai.New(clientOpts...)
 	if err != nil {
@@ -135,19 +138,7 @@ func (g *GPTProvider) StreamComplete(ctx context.Context, req *entities.Completi
 	}
 
 	// Build messages
-	messages := make([]llms.MessageContent, len(req.Messages))
-	for i, m := range req.Messages {
-		role := llms.ChatMessageTypeGeneric
-		switch m.Role {
-		case entities.RoleSystem:
-			role = llms.ChatMessageTypeSystem
-		case entities.RoleUser:
-			role = llms.ChatMessageTypeHuman
-		case entities.RoleAssistant:
-			role = llms.ChatMessageTypeAI
-		}
-		messages[i] = llms.TextParts(role, m.Content)
-	}
+	messages := providers.Messages(req.Messages)
 
 	// Build options
 	callOpts := []llms.CallOption{
@@ -161,6 +152,12 @@ func (g *GPTProvider) StreamComplete(ctx context.Context, req *entities.Completi
 	if len(req.StopSequences) > 0 {
 		callOpts = append(callOpts, llms.WithStopWords(req.StopSequences))
 	}
+	if len(req.Tools) > 0 {
+		callOpts = append(callOpts, llms.WithTools(providers.Tools(req.Tools)))
+	}
+	if req.JSONMode {
+		callOpts = append(callOpts, llms.WithJSONMode())
+	}
 
 	_, err = ll.GenerateContent(ctx, messages, callOpts...)
 	return err
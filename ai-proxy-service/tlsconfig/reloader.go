@@ -0,0 +1,216 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Reloader serves a certificate, key and CA bundle loaded from files, picking up replacements
+// (e.g. from cert-manager or a renewal job) without a restart
+type Reloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	pool    *x509.CertPool
+	modTime time.Time
+}
+
+// NewReloader loads the certificate and CA bundle. certFile and keyFile may be empty for a client
+// without a certificate; an empty caFile verifies peers against the system roots.
+func NewReloader(certFile, keyFile, caFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Run reloads the files whenever they change until ctx is cancelled. A failed reload keeps
+// serving the previous certificate.
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		modTime, err := r.latestModTime()
+		if err != nil {
+			log.Printf("Warning: failed to stat TLS files: %v", err)
+			continue
+		}
+		r.mu.RLock()
+		changed := modTime.After(r.modTime)
+		r.mu.RUnlock()
+		if !changed {
+			continue
+		}
+
+		if err := r.reload(); err != nil {
+			log.Printf("Warning: failed to reload TLS certificate, keeping the previous one: %v", err)
+			continue
+		}
+		log.Printf("Reloaded TLS certificate %s", r.certFile)
+	}
+}
+
+// ServerConfig returns a server TLS config. With requireClientCert, clients must present a
+// certificate issued by the CA bundle.
+func (r *Reloader) ServerConfig(requireClientCert bool) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.current()
+			if cert == nil {
+				return nil, fmt.Errorf("no server certificate configured")
+			}
+
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+			}
+			if requireClientCert {
+				config.ClientAuth = tls.RequireAndVerifyClientCert
+				config.ClientCAs = pool
+			}
+			return config, nil
+		},
+	}
+}
+
+// ClientConfig returns a client TLS config that presents the certificate, if any, and verifies
+// the server against the current CA bundle and serverName. A non-empty identity must also match
+// the server certificate's identity (see PeerIdentity).
+func (r *Reloader) ClientConfig(serverName, identity string) *tls.Config {
+	return &tls.Config{
+		MinVersion:           tls.VersionTLS12,
+		ServerName:           serverName,
+		GetClientCertificate: r.clientCertificate,
+		// Verification is done in VerifyConnection so that a reloaded CA bundle applies
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return fmt.Errorf("server presented no certificate")
+			}
+			_, pool := r.current()
+			opts := x509.VerifyOptions{
+				Roots:         pool,
+				DNSName:       serverName,
+				Intermediates: x509.NewCertPool(),
+			}
+			for _, intermediate := range state.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(intermediate)
+			}
+			if _, err := state.PeerCertificates[0].Verify(opts); err != nil {
+				return err
+			}
+
+			if identity != "" {
+				if peer := PeerIdentity(state.PeerCertificates[0]); peer != identity {
+					return fmt.Errorf("server identity %q is not the expected %q", peer, identity)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// LoopbackClientConfig returns a client TLS config for dialing this process's own listener over
+// loopback. It presents the certificate but does not verify the server, whose certificate need
+// not name the loopback address.
+func (r *Reloader) LoopbackClientConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:           tls.VersionTLS12,
+		GetClientCertificate: r.clientCertificate,
+		InsecureSkipVerify:   true,
+	}
+}
+
+// PeerIdentity names a peer by its first URI SAN (e.g. a SPIFFE ID) or its common name
+func PeerIdentity(cert *x509.Certificate) string {
+	if len(cert.URIs) > 0 {
+		return cert.URIs[0].String()
+	}
+	return cert.Subject.CommonName
+}
+
+// clientCertificate presents the current certificate, or none when the reloader has no certificate
+func (r *Reloader) clientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	cert, _ := r.current()
+	if cert == nil {
+		return &tls.Certificate{}, nil
+	}
+	return cert, nil
+}
+
+func (r *Reloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, r.pool
+}
+
+func (r *Reloader) reload() error {
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return err
+	}
+
+	var cert *tls.Certificate
+	if r.certFile != "" {
+		loaded, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load certificate: %w", err)
+		}
+		cert = &loaded
+	}
+
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		pem, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in CA bundle %s", r.caFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = cert
+	r.pool = pool
+	r.modTime = modTime
+	return nil
+}
+
+// latestModTime returns the most recent modification time of the configured files
+func (r *Reloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{r.certFile, r.keyFile, r.caFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,185 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/blcvn/backend/services/ai-proxy-service/common/errors"
+	"github.com/blcvn/backend/services/ai-proxy-service/entities"
+	model_pb "github.com/blcvn/kratos-proto/go/ai-model"
+)
+
+// LimitPolicy decides what happens to a request that exceeds a model's token limits
+type LimitPolicy string
+
+const (
+	// LimitPolicyTruncate lowers max_tokens to the model's output limit and drops the oldest
+	// messages until the prompt fits the context window
+	LimitPolicyTruncate LimitPolicy = "truncate"
+	// LimitPolicyReject refuses the request, unless a fallback model can take it as is
+	LimitPolicyReject LimitPolicy = "reject"
+)
+
+// modelStatusActive is the status of a model that can serve requests
+const modelStatusActive = "active"
+
+// selectModel resolves the model that serves req: the requested model, or the model an alias's
+// weighted routes assign the caller to, when it supports the request, otherwise the first of its
+// fallback models that does. A deprecated model is replaced by its replacement after its sunset.
+// Tier requests are routed by selectTierModel instead. req is fitted to the selected model's limits.
+func (u *ProxyUsecase) selectModel(ctx context.Context, req *entities.CompletionRequest, caller *entities.Caller, stream bool) (*model_pb.AIModel, errors.BaseError) {
+	if req.Tier != "" {
+		return u.selectTierModel(ctx, req, caller, stream)
+	}
+
+	model, err := u.modelClient.GetModel(ctx, req.ModelID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	if caller != nil && !caller.CanUseModel(req.ModelID, model.Id, model.Name) {
+		return nil, errors.Forbidden(fmt.Sprintf("api key is not allowed to use model: %s", req.ModelID))
+	}
+	model = u.routeAlias(ctx, model, req, caller)
+	model, berr := u.resolveDeprecation(ctx, model, req, caller)
+	if berr != nil {
+		return nil, berr
+	}
+
+	reason := u.unsupportedReason(model, req, stream)
+	if reason == "" {
+		u.useModel(model, req)
+		return model, nil
+	}
+
+	for _, name := range model.FallbackModels {
+		fallback, err := u.modelClient.GetModel(ctx, name)
+		if err != nil {
+			log.Printf("Warning: fallback model %s of %s is unavailable: %v", name, model.Name, err)
+			continue
+		}
+		if fallback.Status.String() != modelStatusActive {
+			continue
+		}
+		if caller != nil && !caller.CanUseModel(name, fallback.Id, fallback.Name) {
+			continue
+		}
+		if u.unsupportedReason(fallback, req, stream) != "" {
+			continue
+		}
+
+		log.Printf("Info: model %s %s, falling back to %s", model.Name, reason, fallback.Name)
+		u.useModel(fallback, req)
+		return fallback, nil
+	}
+
+	return nil, errors.BadRequest(fmt.Sprintf("model %s %s", model.Name, reason))
+}
+
+// useModel points req at the selected model: providers are called with its provider model ID,
+// since the request may name an alias or a model it fell back from
+func (u *ProxyUsecase) useModel(model *model_pb.AIModel, req *entities.CompletionRequest) {
+	if model.ModelId != "" {
+		req.ModelID = model.ModelId
+	}
+	u.fitRequest(model, req)
+}
+
+// unsupportedReason returns why model cannot serve req, or "" when it can.
+// Models without capabilities predate the registry and are not checked.
+func (u *ProxyUsecase) unsupportedReason(model *model_pb.AIModel, req *entities.CompletionRequest, stream bool) string {
+	if caps := model.Capabilities; caps != nil {
+		if stream && !caps.Streaming {
+			return "does not support streaming"
+		}
+		if req.JSONMode && !caps.JsonMode {
+			return "does not support json mode"
+		}
+		if len(req.Tools) > 0 && !caps.Tools {
+			return "does not support tools"
+		}
+		if req.HasImages() && !caps.Vision {
+			return "does not support image input"
+		}
+	}
+
+	limits := model.Limits
+	if limits == nil {
+		return ""
+	}
+
+	maxTokens := req.MaxTokens
+	if limits.MaxOutputTokens > 0 && maxTokens > limits.MaxOutputTokens {
+		if u.limitPolicy == LimitPolicyReject {
+			return fmt.Sprintf("allows at most %d output tokens", limits.MaxOutputTokens)
+		}
+		maxTokens = limits.MaxOutputTokens
+	}
+
+	if limits.ContextWindow > 0 {
+		messages := req.Messages
+		if u.limitPolicy == LimitPolicyTruncate {
+			messages = dropOldestMessages(messages, len(messages))
+		}
+		if estimateTokens(messages)+maxTokens > limits.ContextWindow {
+			return fmt.Sprintf("has a context window of %d tokens", limits.ContextWindow)
+		}
+	}
+	return ""
+}
+
+// fitRequest fits a request the model supports into its limits: max_tokens is capped at the
+// output limit, the oldest messages are dropped until the prompt and output fit the context
+// window, and an unset max_tokens becomes whatever output room is left
+func (u *ProxyUsecase) fitRequest(model *model_pb.AIModel, req *entities.CompletionRequest) {
+	limits := model.Limits
+	if limits == nil {
+		return
+	}
+
+	if limits.MaxOutputTokens > 0 && req.MaxTokens > limits.MaxOutputTokens {
+		req.MaxTokens = limits.MaxOutputTokens
+	}
+
+	if limits.ContextWindow > 0 {
+		for req.EstimatePromptTokens()+req.MaxTokens > limits.ContextWindow {
+			messages := dropOldestMessages(req.Messages, 1)
+			if len(messages) == len(req.Messages) {
+				break
+			}
+			req.Messages = messages
+		}
+	}
+
+	if req.MaxTokens <= 0 {
+		room := limits.MaxOutputTokens
+		if limits.ContextWindow > 0 {
+			if left := limits.ContextWindow - req.EstimatePromptTokens(); room <= 0 || left < room {
+				room = left
+			}
+		}
+		if room > 0 {
+			req.MaxTokens = room
+		}
+	}
+}
+
+// dropOldestMessages returns messages without the oldest n conversation messages.
+// System messages and the last message are always kept.
+func dropOldestMessages(messages []entities.Message, n int) []entities.Message {
+	kept := make([]entities.Message, 0, len(messages))
+	for i, m := range messages {
+		if n > 0 && m.Role != entities.RoleSystem && i < len(messages)-1 {
+			n--
+			continue
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
+
+// estimateTokens approximates the size of messages like CompletionRequest.EstimatePromptTokens
+func estimateTokens(messages []entities.Message) int32 {
+	req := entities.CompletionRequest{Messages: messages}
+	return req.EstimatePromptTokens()
+}
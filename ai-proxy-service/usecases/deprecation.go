@@ -0,0 +1,70 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-proxy-service/common/errors"
+	"github.com/blcvn/backend/services/ai-proxy-service/entities"
+	"github.com/blcvn/backend/services/ai-proxy-service/metrics"
+	model_pb "github.com/blcvn/kratos-proto/go/ai-model"
+)
+
+// resolveDeprecation handles a request for a deprecated model. Before its sunset the model serves
+// the request with a warning; after it the request is redirected to the replacement model, or
+// refused when there is none. The notice for the caller is set on req.
+func (u *ProxyUsecase) resolveDeprecation(ctx context.Context, model *model_pb.AIModel, req *entities.CompletionRequest, caller *entities.Caller) (*model_pb.AIModel, errors.BaseError) {
+	deprecation := model.Deprecation
+	if deprecation == nil {
+		return model, nil
+	}
+
+	notice := &entities.DeprecationNotice{
+		Model:         model.Name,
+		ReplacementID: deprecation.ReplacementModelId,
+	}
+	if deprecation.SunsetAt != nil {
+		sunsetAt := deprecation.SunsetAt.AsTime()
+		notice.SunsetAt = &sunsetAt
+	}
+
+	if notice.SunsetAt == nil || time.Now().Before(*notice.SunsetAt) {
+		notice.Message = fmt.Sprintf("model %s is deprecated", model.Name)
+		if notice.SunsetAt != nil {
+			notice.Message += fmt.Sprintf(" and will be retired on %s", notice.SunsetAt.Format(time.DateOnly))
+		}
+		if notice.ReplacementID != "" {
+			notice.Message += fmt.Sprintf(", migrate to %s", notice.ReplacementID)
+		}
+		req.Deprecation = notice
+		metrics.DeprecatedModelRequests.WithLabelValues(model.Name, "warned").Inc()
+		return model, nil
+	}
+
+	if notice.ReplacementID == "" {
+		metrics.DeprecatedModelRequests.WithLabelValues(model.Name, "rejected").Inc()
+		return nil, errors.BadRequest(fmt.Sprintf("model %s was retired on %s", model.Name, notice.SunsetAt.Format(time.DateOnly)))
+	}
+
+	replacement, err := u.modelClient.GetModel(ctx, notice.ReplacementID)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+	if replacement.Status.String() != modelStatusActive {
+		metrics.DeprecatedModelRequests.WithLabelValues(model.Name, "rejected").Inc()
+		return nil, errors.BadRequest(fmt.Sprintf("model %s was retired and its replacement %s is %s", model.Name, replacement.Name, replacement.Status.String()))
+	}
+	if caller != nil && !caller.CanUseModel(replacement.Id, replacement.Name) {
+		metrics.DeprecatedModelRequests.WithLabelValues(model.Name, "rejected").Inc()
+		return nil, errors.Forbidden(fmt.Sprintf("model %s was retired and the api key is not allowed to use its replacement %s", model.Name, replacement.Name))
+	}
+
+	notice.Redirected = true
+	notice.Message = fmt.Sprintf("model %s was retired on %s, request served by %s", model.Name, notice.SunsetAt.Format(time.DateOnly), replacement.Name)
+	req.Deprecation = notice
+	// Usage is still counted against the retired model in the deprecated callers report
+	req.RequestedModelID = model.Id
+	metrics.DeprecatedModelRequests.WithLabelValues(model.Name, "redirected").Inc()
+	return replacement, nil
+}
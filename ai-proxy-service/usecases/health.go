@@ -0,0 +1,196 @@
+package usecases
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-proxy-service/metrics"
+	"github.com/blcvn/backend/services/ai-proxy-service/providers"
+	model_pb "github.com/blcvn/kratos-proto/go/ai-model"
+)
+
+// BreakerState is the state of a model's circuit breaker
+type BreakerState int
+
+const (
+	// BreakerClosed lets requests through
+	BreakerClosed BreakerState = iota
+	// BreakerOpen keeps the model out of tier routing until the open timeout has passed
+	BreakerOpen
+	// BreakerHalfOpen lets one trial request through to test whether the model recovered
+	BreakerHalfOpen
+)
+
+// HealthOptions configures the circuit breakers and latency windows of a HealthTracker
+type HealthOptions struct {
+	// FailureThreshold is the number of consecutive failed calls that opens a model's breaker
+	FailureThreshold int
+	// OpenTimeout is how long a breaker stays open before it lets a trial request through
+	OpenTimeout time.Duration
+	// LatencyWindow is the number of recent successful calls latency percentiles are taken over
+	LatencyWindow int
+}
+
+// HealthTracker keeps the live health of each model the proxy calls: a circuit breaker fed by
+// the outcome of provider calls and the latencies of its recent successful calls
+type HealthTracker struct {
+	opts      HealthOptions
+	mu        sync.Mutex
+	models    map[string]*modelHealth
+	lastTrial uint64
+}
+
+type modelHealth struct {
+	provider string
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	// trial identifies the one request a half-open breaker lets through while it is in flight;
+	// 0 when there is none
+	trial        uint64
+	trialStarted time.Time
+	// latencies is a ring buffer of the most recent successful call latencies
+	latencies []time.Duration
+	next      int
+}
+
+// NewHealthTracker creates a health tracker
+func NewHealthTracker(opts HealthOptions) *HealthTracker {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = 5
+	}
+	if opts.LatencyWindow <= 0 {
+		opts.LatencyWindow = 100
+	}
+	return &HealthTracker{
+		opts:   opts,
+		models: make(map[string]*modelHealth),
+	}
+}
+
+// Record feeds the outcome of a provider call to the model's breaker. trial is the value
+// TryAcquire returned for the call, so only the trial's own completion ends the trial. Only
+// provider failures (5xx, timeouts, transport errors) count: a failure while half-open, or
+// FailureThreshold of them in a row, opens the breaker, and a success closes it. A call the
+// provider rejected with a 4xx leaves the breaker as it is.
+func (t *HealthTracker) Record(model *model_pb.AIModel, latency time.Duration, trial uint64, callErr error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h := t.health(model)
+	if trial != 0 && h.trial == trial {
+		h.trial = 0
+	}
+	switch {
+	case callErr == nil:
+		h.failures = 0
+		h.state = BreakerClosed
+		h.trial = 0
+		if len(h.latencies) < t.opts.LatencyWindow {
+			h.latencies = append(h.latencies, latency)
+		} else {
+			h.latencies[h.next] = latency
+		}
+		h.next = (h.next + 1) % t.opts.LatencyWindow
+	case providers.IsProviderFailure(callErr):
+		h.failures++
+		if h.state == BreakerHalfOpen || h.failures >= t.opts.FailureThreshold {
+			h.state = BreakerOpen
+			h.openedAt = time.Now()
+			h.trial = 0
+		}
+	}
+	metrics.CircuitBreakerState.WithLabelValues(model.Name, h.provider).Set(float64(h.state))
+}
+
+// Release ends a trial whose call was abandoned before it said anything about the model's health
+func (t *HealthTracker) Release(modelID string, trial uint64) {
+	if trial == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if h, ok := t.models[modelID]; ok && h.trial == trial {
+		h.trial = 0
+	}
+}
+
+// Available reports whether the model's breaker would let a request through: it is closed, or
+// it has been open for OpenTimeout and no trial request is in flight. It only filters
+// candidates; TryAcquire decides.
+func (t *HealthTracker) Available(modelID string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.models[modelID]
+	if !ok {
+		return true
+	}
+	return t.admits(h)
+}
+
+// TryAcquire lets a request through the model's breaker, checking and claiming in one step so
+// that only one request becomes the trial of a half-open breaker. A breaker whose open timeout
+// has passed turns half-open. It returns the trial ID to pass to Record, 0 when the request is
+// not a trial, and false when the breaker refuses the request.
+func (t *HealthTracker) TryAcquire(model *model_pb.AIModel) (uint64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h := t.health(model)
+	if !t.admits(h) {
+		return 0, false
+	}
+	if h.state == BreakerOpen {
+		h.state = BreakerHalfOpen
+	}
+	var trial uint64
+	if h.state == BreakerHalfOpen {
+		t.lastTrial++
+		trial = t.lastTrial
+		h.trial = trial
+		h.trialStarted = time.Now()
+	}
+	metrics.CircuitBreakerState.WithLabelValues(model.Name, h.provider).Set(float64(h.state))
+	return trial, true
+}
+
+// admits reports whether h lets a request through. A trial that has been in flight for longer
+// than OpenTimeout is presumed lost and no longer blocks a new one.
+func (t *HealthTracker) admits(h *modelHealth) bool {
+	switch h.state {
+	case BreakerOpen:
+		return time.Since(h.openedAt) >= t.opts.OpenTimeout
+	case BreakerHalfOpen:
+		return h.trial == 0 || time.Since(h.trialStarted) >= t.opts.OpenTimeout
+	}
+	return true
+}
+
+// Latency returns the p-th percentile (0 to 1) of the model's recent successful call latencies,
+// or false when none were recorded yet
+func (t *HealthTracker) Latency(modelID string, p float64) (time.Duration, bool) {
+	t.mu.Lock()
+	h, ok := t.models[modelID]
+	if !ok || len(h.latencies) == 0 {
+		t.mu.Unlock()
+		return 0, false
+	}
+	latencies := slices.Clone(h.latencies)
+	t.mu.Unlock()
+
+	slices.Sort(latencies)
+	return latencies[int(p*float64(len(latencies)-1))], true
+}
+
+func (t *HealthTracker) health(model *model_pb.AIModel) *modelHealth {
+	h, ok := t.models[model.Id]
+	if !ok {
+		h = &modelHealth{}
+		t.models[model.Id] = h
+	}
+	h.provider = model.Provider
+	return h
+}
@@ -0,0 +1,96 @@
+package usecases
+
+import (
+	"context"
+	"hash/fnv"
+	"log"
+	"math/rand"
+
+	"github.com/blcvn/backend/services/ai-proxy-service/auth"
+	"github.com/blcvn/backend/services/ai-proxy-service/entities"
+	"github.com/blcvn/backend/services/ai-proxy-service/metrics"
+	model_pb "github.com/blcvn/kratos-proto/go/ai-model"
+)
+
+// routeBuckets is the number of buckets traffic is split into; route weights are percentages
+const routeBuckets = 100
+
+// routeAlias picks the model that serves a request for an alias with weighted routes: one of
+// the routes' models or, for the traffic they leave over, the alias's own model. Callers are
+// assigned to a bucket by a hash of the alias and their user, session or API key, so each keeps
+// seeing the same model while the weights only grow. Anonymous callers are spread at random.
+// A route to a model the caller's API key may not use is skipped, leaving the caller on the
+// alias's own model.
+func (u *ProxyUsecase) routeAlias(ctx context.Context, model *model_pb.AIModel, req *entities.CompletionRequest, caller *entities.Caller) *model_pb.AIModel {
+	if model.Alias == "" || len(model.AliasRoutes) == 0 {
+		return model
+	}
+	req.Alias = model.Alias
+
+	bucket := routeBucket(model.Alias, routingKey(ctx, caller))
+	var upper int32
+	for _, route := range model.AliasRoutes {
+		upper += route.Weight
+		if bucket >= upper {
+			continue
+		}
+		if caller != nil && !caller.CanUseModel(route.ModelId, route.ModelName) {
+			return model
+		}
+
+		routed, err := u.modelClient.GetModel(ctx, route.ModelId)
+		if err != nil {
+			log.Printf("Warning: model %s routed from alias %s is unavailable: %v", route.ModelName, model.Alias, err)
+			return model
+		}
+		if routed.Status.String() != modelStatusActive {
+			log.Printf("Warning: model %s routed from alias %s is %s", routed.Name, model.Alias, routed.Status.String())
+			return model
+		}
+		if caller != nil && !caller.CanUseModel(routed.Id, routed.Name) {
+			return model
+		}
+		return routed
+	}
+	return model
+}
+
+// routingKey identifies the caller for sticky route assignment: the user, else the session,
+// else the API key. It is empty for anonymous callers.
+func routingKey(ctx context.Context, caller *entities.Caller) string {
+	userID, sessionID := auth.UsageAttribution(ctx)
+	switch {
+	case userID != "":
+		return "user:" + userID
+	case sessionID != "":
+		return "session:" + sessionID
+	case caller != nil && caller.APIKeyID != "":
+		return "key:" + caller.APIKeyID
+	}
+	return ""
+}
+
+// routeBucket maps a caller to one of routeBuckets buckets of an alias
+func routeBucket(alias, key string) int32 {
+	if key == "" {
+		return rand.Int31n(routeBuckets)
+	}
+	h := fnv.New32a()
+	h.Write([]byte(alias))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return int32(h.Sum32() % routeBuckets)
+}
+
+// recordRouteMetrics records the outcome of a request routed through an alias, labelled with the
+// model that served it, so the models behind an alias can be compared before promoting one
+func recordRouteMetrics(model *model_pb.AIModel, req *entities.CompletionRequest, usage entities.Usage, event *entities.UsageEvent) {
+	if req.Alias == "" {
+		return
+	}
+	metrics.AliasRouteRequests.WithLabelValues(req.Alias, model.Name, string(event.Status)).Inc()
+	metrics.AliasRouteDuration.WithLabelValues(req.Alias, model.Name).Observe(float64(event.LatencyMs) / 1000)
+	metrics.AliasRouteTokens.WithLabelValues(req.Alias, model.Name, "prompt").Add(float64(usage.PromptTokens))
+	metrics.AliasRouteTokens.WithLabelValues(req.Alias, model.Name, "completion").Add(float64(usage.CompletionTokens))
+	metrics.AliasRouteCost.WithLabelValues(req.Alias, model.Name).Add(float64(usage.PromptTokens+usage.CompletionTokens) / 1000 * model.CostPer_1KTokens)
+}
@@ -0,0 +1,175 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blcvn/backend/services/ai-proxy-service/common/errors"
+	"github.com/blcvn/backend/services/ai-proxy-service/entities"
+	"github.com/blcvn/backend/services/ai-proxy-service/metrics"
+	model_pb "github.com/blcvn/kratos-proto/go/ai-model"
+)
+
+// tierWeight is how much estimated cost and p95 latency count in the score of a tier's models
+type tierWeight struct {
+	cost    float64
+	latency float64
+}
+
+// tierWeights maps the capability tiers callers can ask for to their scoring weights
+var tierWeights = map[string]tierWeight{
+	"cheap":    {cost: 0.8, latency: 0.2},
+	"balanced": {cost: 0.5, latency: 0.5},
+	"best":     {cost: 0.2, latency: 0.8},
+}
+
+// tierCandidate is an eligible model of a tier with its estimated cost and latency
+type tierCandidate struct {
+	model      *model_pb.AIModel
+	cost       float64
+	latency    time.Duration
+	hasLatency bool
+	score      float64
+}
+
+// selectTierModel picks the model that serves a tier request among the tier's models from the AI
+// Model Service. Models the API key may not use, that cannot serve the request, whose remaining
+// quota is below the request's estimated tokens or whose circuit breaker is open are skipped. The
+// rest are scored on estimated cost and p95 latency, each relative to the highest among them and
+// weighted by the tier, and the lowest score wins. The decision and its reason are set on req.
+func (u *ProxyUsecase) selectTierModel(ctx context.Context, req *entities.CompletionRequest, caller *entities.Caller, stream bool) (*model_pb.AIModel, errors.BaseError) {
+	weight, ok := tierWeights[req.Tier]
+	if !ok {
+		return nil, errors.BadRequest(fmt.Sprintf("unknown tier %q, expected cheap, balanced or best", req.Tier))
+	}
+	if req.ModelID != "" {
+		return nil, errors.BadRequest("set either model_id or tier, not both")
+	}
+
+	models, err := u.modelClient.ListRoutingCandidates(ctx, req.Tier)
+	if err != nil {
+		return nil, errors.Internal(err)
+	}
+
+	promptTokens := req.EstimatePromptTokens()
+	var eligible []*tierCandidate
+	var skipped []string
+	for _, candidate := range models {
+		model := candidate.Model
+		if model == nil {
+			continue
+		}
+		// Models the API key may not use are left out of the reason too
+		if caller != nil && !caller.CanUseModel(model.Id, model.Name) {
+			continue
+		}
+		if reason := u.unsupportedReason(model, req, stream); reason != "" {
+			skipped = append(skipped, fmt.Sprintf("%s (%s)", model.Name, reason))
+			continue
+		}
+		if candidate.QuotaRemaining >= 0 && candidate.QuotaRemaining < int64(promptTokens+req.MaxTokens) {
+			skipped = append(skipped, fmt.Sprintf("%s (quota exhausted)", model.Name))
+			continue
+		}
+		if !u.health.Available(model.Id) {
+			skipped = append(skipped, fmt.Sprintf("%s (circuit open)", model.Name))
+			continue
+		}
+
+		c := &tierCandidate{
+			model: model,
+			cost:  (float64(promptTokens)*candidate.InputPer_1KTokens + float64(req.MaxTokens)*candidate.OutputPer_1KTokens) / 1000,
+		}
+		c.latency, c.hasLatency = u.health.Latency(model.Id, 0.95)
+		eligible = append(eligible, c)
+	}
+	if len(eligible) == 0 {
+		message := fmt.Sprintf("no model of tier %s can serve the request", req.Tier)
+		if len(skipped) > 0 {
+			message += ": skipped " + strings.Join(skipped, ", ")
+		}
+		return nil, errors.BadRequest(message)
+	}
+
+	// The best candidate whose breaker still lets the request through wins; another request may
+	// have taken a half-open breaker's trial since the candidates were filtered
+	scoreTierCandidates(eligible, weight)
+	var chosen *tierCandidate
+	var others []string
+	for _, c := range eligible {
+		if chosen != nil {
+			others = append(others, fmt.Sprintf("%s %.2f", c.model.Name, c.score))
+			continue
+		}
+		trial, ok := u.health.TryAcquire(c.model)
+		if !ok {
+			skipped = append(skipped, fmt.Sprintf("%s (circuit open)", c.model.Name))
+			continue
+		}
+		chosen = c
+		req.HealthTrial = trial
+	}
+	if chosen == nil {
+		return nil, errors.BadRequest(fmt.Sprintf("no model of tier %s can serve the request: skipped %s", req.Tier, strings.Join(skipped, ", ")))
+	}
+
+	reason := fmt.Sprintf("%s scored %.2f (est. $%.6f, p95 %s)", chosen.model.Name, chosen.score, chosen.cost, formatLatency(chosen))
+	if len(others) > 0 {
+		reason += "; over " + strings.Join(others, ", ")
+	}
+	if len(skipped) > 0 {
+		reason += "; skipped " + strings.Join(skipped, ", ")
+	}
+
+	u.useModel(chosen.model, req)
+	req.Routing = &entities.RoutingDecision{Tier: req.Tier, Model: chosen.model.Name, Reason: reason}
+	metrics.TierRoutingDecisions.WithLabelValues(req.Tier, chosen.model.Name).Inc()
+	return chosen.model, nil
+}
+
+// scoreTierCandidates scores candidates and sorts them best first, ties broken by name. Models
+// without recorded latency are scored at the average p95 of the others, so they are tried without
+// being favoured.
+func scoreTierCandidates(candidates []*tierCandidate, weight tierWeight) {
+	var maxCost float64
+	var maxLatency, totalLatency time.Duration
+	measured := 0
+	for _, c := range candidates {
+		maxCost = max(maxCost, c.cost)
+		if c.hasLatency {
+			maxLatency = max(maxLatency, c.latency)
+			totalLatency += c.latency
+			measured++
+		}
+	}
+
+	for _, c := range candidates {
+		latency := c.latency
+		if !c.hasLatency && measured > 0 {
+			latency = totalLatency / time.Duration(measured)
+		}
+		if maxCost > 0 {
+			c.score += weight.cost * c.cost / maxCost
+		}
+		if maxLatency > 0 {
+			c.score += weight.latency * float64(latency) / float64(maxLatency)
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score < candidates[j].score
+		}
+		return candidates[i].model.Name < candidates[j].model.Name
+	})
+}
+
+func formatLatency(c *tierCandidate) string {
+	if !c.hasLatency {
+		return "unknown"
+	}
+	return c.latency.Round(time.Millisecond).String()
+}
@@ -3,30 +3,46 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
+	"github.com/blcvn/backend/services/ai-proxy-service/auth"
 	"github.com/blcvn/backend/services/ai-proxy-service/common/errors"
 	"github.com/blcvn/backend/services/ai-proxy-service/entities"
+	"github.com/blcvn/backend/services/ai-proxy-service/providers"
 	model_pb "github.com/blcvn/kratos-proto/go/ai-model"
+	"github.com/google/uuid"
 )
 
 type iAIModelClient interface {
 	GetCredentials(ctx context.Context, modelID string) (*model_pb.Credentials, error)
 	GetModel(ctx context.Context, modelID string) (*model_pb.AIModel, error)
 	CheckQuota(ctx context.Context, modelID string, tokens int32) (bool, error)
-	LogUsage(ctx context.Context, modelID string, promptTokens, completionTokens int32) error
+	CheckBudget(ctx context.Context, modelID string, caller *entities.Caller) (bool, string, error)
+	ReportKeyFailure(ctx context.Context, modelID, keyID string, statusCode int32) error
+	ListRoutingCandidates(ctx context.Context, tier string) ([]*model_pb.RoutingCandidate, error)
+}
+
+type iUsageRecorder interface {
+	Enqueue(event *entities.UsageEvent) error
 }
 
 // ProxyUsecase implements the core business logic for AI Proxy
 type ProxyUsecase struct {
-	modelClient iAIModelClient
-	providers   map[string]entities.LLMProvider
+	modelClient   iAIModelClient
+	usageRecorder iUsageRecorder
+	providers     map[string]entities.LLMProvider
+	limitPolicy   LimitPolicy
+	health        *HealthTracker
 }
 
-func NewProxyUsecase(modelClient iAIModelClient) *ProxyUsecase {
+func NewProxyUsecase(modelClient iAIModelClient, usageRecorder iUsageRecorder, limitPolicy LimitPolicy, health *HealthTracker) *ProxyUsecase {
 	return &ProxyUsecase{
-		modelClient: modelClient,
-		providers:   make(map[string]entities.LLMProvider),
+		modelClient:   modelClient,
+		usageRecorder: usageRecorder,
+		providers:     make(map[string]entities.LLMProvider),
+		limitPolicy:   limitPolicy,
+		health:        health,
 	}
 }
 
@@ -46,38 +62,61 @@ func (u *ProxyUsecase) Complete(c context.Context, req *entities.CompletionReque
 	// }
 	ctx, cancel := context.WithTimeout(c, 600*time.Second)
 	defer cancel()
-	// 2. Get Model Info (to know provider)
-	model, err := u.modelClient.GetModel(ctx, req.ModelID)
-	if err != nil {
-		return nil, errors.Internal(err)
+	// 2. Get Model Info (to know provider), falling back when the model cannot serve the request
+	caller := auth.CallerFromContext(ctx)
+	model, berr := u.selectModel(ctx, req, caller, false)
+	if berr != nil {
+		return nil, berr
 	}
 
-	// 3. Get Credentials
-	creds, err := u.modelClient.GetCredentials(ctx, req.ModelID)
-	if err != nil {
-		return nil, errors.Internal(err)
+	// Enforce hard-stop USD budgets
+	if err := u.checkBudget(ctx, model.Id, caller); err != nil {
+		return nil, err
 	}
 
-	// Inject credentials into request
-	req.APIKey = creds.ApiKey
-	req.BaseURL = creds.BaseUrl
-
-	// 4. Get Provider
+	// 3. Get Provider
 	provider, ok := u.providers[model.Provider]
 	if !ok {
 		return nil, errors.BadRequest(fmt.Sprintf("unsupported provider: %s", model.Provider))
 	}
 
-	// 5. Call LLM
-	// TODO: Inject creds into provider before calling, or pass creds to Complete
-	resp, err := provider.Complete(ctx, req)
-	if err != nil {
-		return nil, errors.Internal(err)
-	}
+	// 4. Get Credentials and call LLM. A pooled key the provider rejects is reported
+	// for quarantine and the call is retried with another key from the pool.
+	var resp *entities.CompletionResponse
+	for attempt := 1; ; attempt++ {
+		creds, err := u.modelClient.GetCredentials(ctx, model.Id)
+		if err != nil {
+			return nil, errors.Internal(err)
+		}
+
+		// Inject credentials into request
+		req.APIKey = creds.ApiKey
+		req.BaseURL = creds.BaseUrl
+		req.Headers = creds.Headers
 
-	// 5. Log Usage
-	_ = u.modelClient.LogUsage(ctx, req.ModelID, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+		// TODO: Inject creds into provider before calling, or pass creds to Complete
+		start := time.Now()
+		resp, err = provider.Complete(ctx, req)
 
+		// 5. Log Usage, including failed and timed out calls
+		var usage entities.Usage
+		if resp != nil {
+			usage = resp.Usage
+		}
+		u.recordUsage(ctx, model, req, usage, time.Since(start), err)
+
+		if err == nil {
+			break
+		}
+		if !u.reportKeyFailure(ctx, model.Id, creds.KeyId, err) || attempt >= maxKeyAttempts {
+			return nil, errors.Internal(err)
+		}
+	}
+
+	resp.Model = model.Name
+	resp.Provider = model.Provider
+	resp.Routing = req.Routing
+	resp.Deprecation = req.Deprecation
 	return resp, nil
 }
 
@@ -98,14 +137,20 @@ func (u *ProxyUsecase) StreamComplete(ctx context.Context, req *entities.Complet
 	// 	return errors.RateLimit("quota exceeded for this model")
 	// }
 
-	// 2. Get Model Info
-	model, err := u.modelClient.GetModel(ctx, req.ModelID)
-	if err != nil {
-		return errors.Internal(err)
+	// 2. Get Model Info, falling back when the model cannot stream the request
+	caller := auth.CallerFromContext(ctx)
+	model, berr := u.selectModel(ctx, req, caller, true)
+	if berr != nil {
+		return berr
+	}
+
+	// Enforce hard-stop USD budgets
+	if err := u.checkBudget(ctx, model.Id, caller); err != nil {
+		return err
 	}
 
 	// 3. Get Credentials
-	creds, err := u.modelClient.GetCredentials(ctx, req.ModelID)
+	creds, err := u.modelClient.GetCredentials(ctx, model.Id)
 	if err != nil {
 		return errors.Internal(err)
 	}
@@ -113,6 +158,7 @@ func (u *ProxyUsecase) StreamComplete(ctx context.Context, req *entities.Complet
 	// Inject credentials into request
 	req.APIKey = creds.ApiKey
 	req.BaseURL = creds.BaseUrl
+	req.Headers = creds.Headers
 
 	// 4. Get Provider
 	provider, ok := u.providers[model.Provider]
@@ -121,23 +167,113 @@ func (u *ProxyUsecase) StreamComplete(ctx context.Context, req *entities.Complet
 	}
 
 	// 4. Stream LLM
-	var totalPrompt, totalCompletion int32
+	var usage entities.Usage
+	start := time.Now()
 	err = provider.StreamComplete(ctx, req, func(sr *entities.StreamResponse) error {
 		if sr.Usage != nil {
-			totalPrompt = sr.Usage.PromptTokens
-			totalCompletion = sr.Usage.CompletionTokens
+			usage = *sr.Usage
 		}
 		return callback(sr)
 	})
 
+	// 5. Log Usage, including failed and timed out streams
+	u.recordUsage(ctx, model, req, usage, time.Since(start), err)
+
 	if err != nil {
+		// Chunks may already have been sent, so a rejected key is quarantined but not retried
+		u.reportKeyFailure(ctx, model.Id, creds.KeyId, err)
 		return errors.Internal(err)
 	}
 
-	// 5. Log Usage
-	if totalPrompt > 0 || totalCompletion > 0 {
-		_ = u.modelClient.LogUsage(ctx, req.ModelID, totalPrompt, totalCompletion)
-	}
+	return nil
+}
 
+// checkBudget rejects the request when a hard-stop budget for the caller or model is exhausted
+func (u *ProxyUsecase) checkBudget(ctx context.Context, modelID string, caller *entities.Caller) errors.BaseError {
+	blocked, reason, err := u.modelClient.CheckBudget(ctx, modelID, caller)
+	if err != nil {
+		return errors.Internal(err)
+	}
+	if blocked {
+		return errors.RateLimit(reason)
+	}
 	return nil
 }
+
+// maxKeyAttempts bounds how many pooled keys a completion tries after provider key rejections
+const maxKeyAttempts = 2
+
+// reportKeyFailure reports a key the provider rejected for authentication or rate limiting,
+// so the AI Model Service quarantines it. It returns whether the call may be retried with another key.
+func (u *ProxyUsecase) reportKeyFailure(ctx context.Context, modelID, keyID string, callErr error) bool {
+	if keyID == "" {
+		return false
+	}
+	statusCode, ok := providers.KeyFailureStatus(callErr)
+	if !ok {
+		return false
+	}
+
+	if err := u.modelClient.ReportKeyFailure(ctx, modelID, keyID, statusCode); err != nil {
+		log.Printf("Error: failed to report provider key %s for model %s: %v", keyID, modelID, err)
+		return false
+	}
+	return true
+}
+
+// maxUsageErrorLength caps the provider error message stored with a usage log
+const maxUsageErrorLength = 1024
+
+// recordUsage queues a usage event in the durable outbox; delivery happens in the background.
+// callErr is the provider error, if any, and decides whether the call is logged as an error or timeout.
+func (u *ProxyUsecase) recordUsage(ctx context.Context, model *model_pb.AIModel, req *entities.CompletionRequest, usage entities.Usage, latency time.Duration, callErr error) {
+	event := &entities.UsageEvent{
+		ID:               uuid.NewString(),
+		ModelID:          model.Id,
+		Alias:            req.Alias,
+		RequestedModelID: req.RequestedModelID,
+		PromptHash:       req.PromptHash(),
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		LatencyMs:        int32(latency.Milliseconds()),
+		Status:           usageStatus(ctx, callErr),
+		OccurredAt:       time.Now().UTC(),
+	}
+	if callErr != nil {
+		event.ErrorMessage = callErr.Error()
+		if len(event.ErrorMessage) > maxUsageErrorLength {
+			event.ErrorMessage = event.ErrorMessage[:maxUsageErrorLength]
+		}
+	}
+	if caller := auth.CallerFromContext(ctx); caller != nil {
+		event.TenantID = caller.TenantID
+		event.ProjectID = caller.ProjectID
+		event.APIKeyID = caller.APIKeyID
+	}
+	event.UserID, event.SessionID = auth.UsageAttribution(ctx)
+	recordRouteMetrics(model, req, usage, event)
+	// Calls the caller gave up on say nothing about the model's health
+	if ctx.Err() != context.Canceled {
+		u.health.Record(model, latency, req.HealthTrial, callErr)
+	} else {
+		u.health.Release(model.Id, req.HealthTrial)
+	}
+
+	if err := u.usageRecorder.Enqueue(event); err != nil {
+		log.Printf("Error: failed to record usage for model %s: %v", model.Id, err)
+	}
+}
+
+// usageStatus classifies the outcome of a provider call
+func usageStatus(ctx context.Context, callErr error) entities.UsageStatus {
+	if callErr == nil {
+		return entities.UsageStatusSuccess
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return entities.UsageStatusTimeout
+	}
+	if timeoutErr, ok := callErr.(interface{ Timeout() bool }); ok && timeoutErr.Timeout() {
+		return entities.UsageStatusTimeout
+	}
+	return entities.UsageStatusError
+}